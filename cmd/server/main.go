@@ -2,16 +2,24 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"transaction-processor/internal/auth"
 	"transaction-processor/internal/config"
 	"transaction-processor/internal/database"
+	"transaction-processor/internal/grpcapi"
 	"transaction-processor/internal/handler"
 	"transaction-processor/internal/logger"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/payment"
 	"transaction-processor/internal/repository/postgres"
+	"transaction-processor/internal/risk"
+	"transaction-processor/internal/scheduler"
 	"transaction-processor/internal/service"
 	"transaction-processor/internal/worker"
 
@@ -27,44 +35,224 @@ func main() {
 	// Setup logger
 	log := logger.New(true)
 
-	// Load configuration from environment
+	// Load configuration from CONFIG_FILE and environment
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to load config")
 	}
+	if err := logger.SetLevel(cfg.Log.Level); err != nil {
+		log.Fatal().Err(err).Msg("Failed to set log level")
+	}
 
 	// Initialize database connection
 	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	dbPool, err := database.NewPool(dbCtx, cfg.Database)
+	pools, err := database.NewPool(dbCtx, cfg.Database)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to connect to database")
 	}
-	defer dbPool.Close()
+	defer pools.Close()
+	dbPool := pools.Primary
+
+	// Reads that tolerate lag go to the replica while it is within DB_REPLICA_MAX_LAG
+	var replica *postgres.ReplicaRouter
+	if pools.Replica != nil {
+		replica = postgres.NewReplicaRouter(pools.Replica, cfg.Database.ReplicaMaxLag, cfg.Database.ReplicaLagCheckInterval, log)
+	}
 
 	// Repositories
-	userRepo := postgres.NewUserRepository(dbPool)
-	transactionRepo := postgres.NewTransactionRepository(dbPool)
+	userRepo := postgres.NewUserRepository(dbPool, replica)
+	transactionRepo := postgres.NewTransactionRepository(dbPool, replica)
+	limitRepo := postgres.NewLimitRepository(dbPool)
+	adjustmentRepo := postgres.NewAdjustmentRepository(dbPool)
+	bonusRepo := postgres.NewBonusRepository(dbPool)
+	paymentRepo := postgres.NewPaymentRepository(dbPool)
+	balanceListener := postgres.NewBalanceListener(dbPool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(dbPool)
+	partitionRepo := postgres.NewPartitionRepository(dbPool)
+	workerStateRepo := postgres.NewWorkerStateRepository(dbPool)
+	jobRunRepo := postgres.NewJobRunRepository(dbPool)
+	healthRepo := postgres.NewHealthRepository(dbPool)
 
 	// Transaction manage used by services
 	txManager := postgres.NewTransactionManager(dbPool)
 
+	amountLimits, err := service.NewAmountLimits(cfg.Limits)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid amount limits")
+	}
+	debtPolicy, err := service.NewDebtPolicy(cfg.Debt)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid debt config")
+	}
+	bonusPolicy, err := service.NewBonusPolicy(cfg.Bonus)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid bonus config")
+	}
+
+	var riskEngine *risk.Engine
+	if cfg.Risk.RulesFile != "" {
+		riskEngine, err = risk.LoadFile(cfg.Risk.RulesFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid risk rules")
+		}
+	}
+
+	var tenants *service.Tenants
+	if cfg.Tenant.File != "" {
+		tenants, err = service.LoadTenants(cfg.Tenant.File)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid tenants")
+		}
+	}
+
+	var verifier *auth.Verifier
+	switch {
+	case cfg.Auth.JWKSFile != "":
+		verifier, err = auth.LoadVerifier(cfg.Auth)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid JWKS")
+		}
+	case cfg.Auth.Disabled:
+		log.Warn().Msg("AUTHENTICATION IS DISABLED (AUTH_DISABLED=true): API calls are not authenticated and every caller has every role, never run like this in production")
+	default:
+		log.Fatal().Msg("AUTH_JWKS_FILE is not set, set it or AUTH_DISABLED=true to serve the API without authentication")
+	}
+
 	// Services
-	transService := service.NewTransactionService(userRepo, transactionRepo, txManager, log)
-	cancelService := service.NewCancellationService(userRepo, transactionRepo, txManager, log)
+	transService := service.NewTransactionService(userRepo, transactionRepo, limitRepo, idempotencyRepo, txManager, amountLimits, tenants, debtPolicy, bonusPolicy, riskEngine, cfg.Idempotency.KeyTTL, log)
+	cancelService := service.NewCancellationService(userRepo, transactionRepo, limitRepo, workerStateRepo, txManager,
+		model.ShortfallPolicy(cfg.Cancellation.ShortfallPolicy), tenants, debtPolicy, log)
+	limitService := service.NewLimitService(userRepo, limitRepo, txManager, cfg.Limits.IncreaseCoolingOff, log)
+	reviewService := service.NewReviewService(userRepo, transactionRepo, limitRepo, txManager, debtPolicy, bonusPolicy, log)
+	integrityService := service.NewIntegrityService(userRepo, cfg.Integrity.PauseOnDrift, log)
+	adjustmentService := service.NewAdjustmentService(userRepo, transactionRepo, adjustmentRepo, txManager, log)
+	bonusService := service.NewBonusService(userRepo, bonusRepo, txManager, bonusPolicy, log)
+	if cfg.Payment.CallbackSecret == "" {
+		log.Warn().Msg("PAYMENT_CALLBACK_SECRET is not set, payment provider callbacks will be refused")
+	}
+	paymentService := service.NewPaymentService(userRepo, transactionRepo, paymentRepo, txManager, amountLimits, tenants,
+		payment.NewStubProvider(cfg.Payment.CallbackSecret), log)
+	balanceStream := service.NewBalanceStreamService(userRepo, balanceListener, log)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.KeyTTL, log)
+	partitionService := service.NewPartitionService(partitionRepo, txManager, cfg.Partition.PremakeMonths,
+		cfg.Partition.RetentionMonths, cfg.Partition.ArchiveDir, log)
 
 	// Root context to be caceled on SIGINT / SIGTERM
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	// Worker for odd transaction cancellation
-	cancellationWorker := worker.NewCancellationWorker(cancelService, cfg.Worker.CancellationInterval, log)
-	cancellationWorker.Start(ctx)
-	defer cancellationWorker.Stop()
+	// Only the elected leader runs scheduled jobs, the others take over when its lock is released
+	leader := postgres.NewLeaderElector(dbPool, cfg.Leader.LockName, cfg.Leader.CheckInterval, log)
+	go leader.Run(ctx)
+
+	// Partitions for the coming months must exist before transactions are inserted into them
+	if err := partitionService.EnsurePartitions(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to create transaction partitions")
+	}
+
+	// Background jobs, scheduled runs happen on the leader only
+	jobScheduler := scheduler.NewScheduler(jobRunRepo, leader, cfg.Worker.Jitter, cfg.Worker.JobTimeout, cfg.Worker.JobRunRetention, log)
+	cancellationWorker := worker.NewCancellationWorker(cancelService, jobScheduler, jobRunRepo, log)
+	schedules, err := jobSchedules(cfg.Worker)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid job schedule")
+	}
+	for _, job := range []scheduler.Job{
+		cancellationWorker.Job(schedules[worker.CancellationJob]),
+		worker.NewIntegrityJob(integrityService, schedules[worker.IntegrityJob]),
+		worker.NewPartitionJob(partitionService, schedules[worker.PartitionJob]),
+		worker.NewIdempotencyCleanupJob(idempotencyService, schedules[worker.IdempotencyCleanupJob]),
+		worker.NewBonusExpiryJob(bonusService, schedules[worker.BonusExpiryJob]),
+	} {
+		if err := jobScheduler.Register(job); err != nil {
+			log.Fatal().Err(err).Msg("Failed to register job")
+		}
+	}
+	// An interval set through the admin API replaces the configured cancellation schedule on every instance
+	if err := cancellationWorker.Sync(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to load the cancellation interval")
+	}
+	jobScheduler.Start(ctx)
+	go cancellationWorker.Run(ctx, cfg.Leader.CheckInterval)
+
+	// Readiness checks for load balancers and orchestrators
+	healthService := service.NewHealthService(healthRepo, jobScheduler, cfg.Health.Timeout, cfg.Health.MaxPoolUsage,
+		postgres.RequiredSchemaVersion, log)
+
+	// Balance change notifications for SSE streams
+	go balanceStream.Run(ctx)
+
+	// Replica lag checks, reads fall back to the primary until the first check passes
+	if replica != nil {
+		go replica.Run(ctx)
+	}
+
+	// Runtime-safe settings are reloaded on SIGHUP, the others need a restart
+	reloader := config.NewReloader(cfg, log)
+	reloader.OnReload(func(cfg *config.Config) error {
+		return logger.SetLevel(cfg.Log.Level)
+	})
+	reloader.OnReload(func(cfg *config.Config) error {
+		return amountLimits.Update(cfg.Limits)
+	})
+	reloader.OnReload(func(cfg *config.Config) error {
+		return debtPolicy.Update(cfg.Debt)
+	})
+	reloader.OnReload(func(cfg *config.Config) error {
+		return bonusPolicy.Update(cfg.Bonus)
+	})
+	// Only changed schedules are applied, the cancellation worker keeps an interval set through the admin API
+	workerCfg := cfg.Worker
+	reloader.OnReload(func(cfg *config.Config) error {
+		previous, err := jobSchedules(workerCfg)
+		if err != nil {
+			return err
+		}
+		next, err := jobSchedules(cfg.Worker)
+		if err != nil {
+			return err
+		}
+		for name, schedule := range next {
+			if schedule.String() == previous[name].String() {
+				continue
+			}
+			if name == worker.CancellationJob {
+				if err := cancellationWorker.Configure(ctx, schedule); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := jobScheduler.SetSchedule(name, schedule); err != nil {
+				return err
+			}
+		}
+		workerCfg = cfg.Worker
+		return nil
+	})
+	go reloader.Run(ctx)
 
 	// http handler
-	h := handler.NewHandler(transService, log)
+	h := handler.NewHandler(handler.Deps{
+		Transactions:       transService,
+		Limits:             limitService,
+		Reviews:            reviewService,
+		Integrity:          integrityService,
+		Adjustments:        adjustmentService,
+		Bonuses:            bonusService,
+		Payments:           paymentService,
+		BalanceStream:      balanceStream,
+		Idempotency:        idempotencyService,
+		Partitions:         partitionService,
+		Cancellations:      cancelService,
+		CancellationWorker: cancellationWorker,
+		Leadership:         leader,
+		Health:             healthService,
+		Tenants:            tenants,
+		Verifier:           verifier,
+		Logger:             log,
+	})
 	router := h.SetupRoutes()
 
 	// http server configuration
@@ -83,10 +271,29 @@ func main() {
 
 	log.Info().Str("port", cfg.Server.Port).Msg("Server started")
 
+	// gRPC server for internal game servers, same services as the REST API
+	grpcServer := grpcapi.NewServer(transService, tenants, verifier, log)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to listen for gRPC")
+	}
+
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start gRPC server")
+		}
+	}()
+
+	log.Info().Str("port", cfg.Server.GRPCPort).Msg("gRPC server started")
+
 	// Wait for shutdown signal
 	<-ctx.Done()
 	log.Info().Msg("Shutdown signal received, starting graceful shutdown...")
 
+	// Fail readiness first so load balancers stop routing new requests before the servers stop
+	healthService.Drain()
+	time.Sleep(cfg.Health.DrainDelay)
+
 	// Graceful shutdown
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer shutdownCancel()
@@ -97,5 +304,37 @@ func main() {
 		log.Info().Msg("HTTP server stopped gracefully")
 	}
 
+	grpcServer.Shutdown(shutdownCtx)
+	log.Info().Msg("gRPC server stopped")
+
+	// Job runs in flight finish unless they outlast the shutdown timeout
+	if err := jobScheduler.Shutdown(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("Job runs cancelled at shutdown")
+	} else {
+		log.Info().Msg("Job scheduler stopped")
+	}
+
 	log.Info().Msg("Shutdown complete")
 }
+
+// jobSchedules builds the schedule of every job from its cron expression or interval
+func jobSchedules(cfg config.WorkerConfig) (map[string]scheduler.Schedule, error) {
+	schedules := make(map[string]scheduler.Schedule)
+	for name, spec := range map[string]struct {
+		cron     string
+		interval time.Duration
+	}{
+		worker.CancellationJob:       {cfg.CancellationCron, cfg.CancellationInterval},
+		worker.IntegrityJob:          {cfg.IntegrityCron, cfg.IntegrityInterval},
+		worker.PartitionJob:          {cfg.PartitionCron, cfg.PartitionInterval},
+		worker.IdempotencyCleanupJob: {cfg.IdempotencyCron, cfg.IdempotencyCleanup},
+		worker.BonusExpiryJob:        {cfg.BonusExpiryCron, cfg.BonusExpiryInterval},
+	} {
+		schedule, err := scheduler.New(spec.cron, spec.interval)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", name, err)
+		}
+		schedules[name] = schedule
+	}
+	return schedules, nil
+}
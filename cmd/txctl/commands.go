@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+var errUsage = errors.New("invalid arguments, run txctl without arguments for usage")
+
+func runBalance(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	userID, err := parseUserID(args[0])
+	if err != nil {
+		return err
+	}
+
+	balance, err := a.transactions.GetBalance(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return printJSON(balance)
+}
+
+func runTransactions(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("transactions", flag.ContinueOnError)
+	limit := fs.Int("limit", 20, "maximum number of transactions")
+	offset := fs.Int("offset", 0, "number of transactions to skip")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	userID, err := parseUserID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	transactions, err := a.transactions.GetTransactionsByUser(ctx, userID, *limit, *offset)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTRANSACTION_ID\tSOURCE\tSTATE\tAMOUNT\tSTATUS\tCREATED_AT")
+	for _, t := range transactions {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			t.ID, t.TransactionID, t.SourceType, t.State, t.Amount.StringFixed(2), t.Status, t.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func runTransaction(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	transaction, err := a.transactions.GetTransaction(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return printJSON(transaction)
+}
+
+func runTransactionEvents(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	events, err := a.transactions.GetTransactionEvents(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return printJSON(events)
+}
+
+func runCancel(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("cancel", flag.ContinueOnError)
+	operator := fs.String("operator", "", "operator performing the cancellation (required)")
+	reason := fs.String("reason", "", "cancellation reason: provider_error, duplicate, fraud, customer_request or other (required)")
+	note := fs.String("note", "", "free-text note recorded in the audit trail")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *operator == "" || *reason == "" {
+		return errUsage
+	}
+	cancelReason, err := model.ParseCancelReason(*reason)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := a.cancellation.CancelTransaction(ctx, fs.Arg(0), *operator, cancelReason, *note)
+	if err != nil {
+		return err
+	}
+	return printJSON(transaction)
+}
+
+func runAdjust(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("adjust", flag.ContinueOnError)
+	operator := fs.String("operator", "", "operator performing the adjustment (required)")
+	reason := fs.String("reason", "", "reason for the adjustment (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || *operator == "" || *reason == "" {
+		return errUsage
+	}
+	userID, err := parseUserID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	amount, err := decimal.NewFromString(fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", fs.Arg(1), err)
+	}
+
+	adjustment, err := a.adjustments.CreateAdjustment(ctx, userID, amount, *reason, *operator)
+	if err != nil {
+		return err
+	}
+	return printJSON(adjustment)
+}
+
+func runAdjustments(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("adjustments", flag.ContinueOnError)
+	status := fs.String("status", string(model.AdjustmentPending), "adjustment status (pending, approved, rejected)")
+	limit := fs.Int("limit", 20, "maximum number of adjustments")
+	offset := fs.Int("offset", 0, "number of adjustments to skip")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errUsage
+	}
+	adjustmentStatus, err := model.ParseAdjustmentStatus(*status)
+	if err != nil {
+		return err
+	}
+
+	adjustments, err := a.adjustments.ListAdjustments(ctx, adjustmentStatus, *limit, *offset)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER_ID\tAMOUNT\tSTATUS\tCREATED_BY\tDECIDED_BY\tREASON")
+	for _, adj := range adjustments {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			adj.ID, adj.UserID, adj.Amount.StringFixed(2), adj.Status, adj.CreatedBy, adj.DecidedBy, adj.Reason)
+	}
+	return w.Flush()
+}
+
+func runApproveAdjustment(ctx context.Context, a *app, args []string) error {
+	return adjustmentDecision(ctx, a, "approve-adjustment", true, args)
+}
+
+func runRejectAdjustment(ctx context.Context, a *app, args []string) error {
+	return adjustmentDecision(ctx, a, "reject-adjustment", false, args)
+}
+
+func adjustmentDecision(ctx context.Context, a *app, name string, approve bool, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	operator := fs.String("operator", "", "operator deciding on the adjustment, must differ from the requester (required)")
+	note := fs.String("note", "", "decision note")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *operator == "" {
+		return errUsage
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("adjustment id must be an integer, got %q", fs.Arg(0))
+	}
+
+	var adjustment *model.BalanceAdjustment
+	if approve {
+		adjustment, err = a.adjustments.ApproveAdjustment(ctx, id, *operator, *note)
+	} else {
+		adjustment, err = a.adjustments.RejectAdjustment(ctx, id, *operator, *note)
+	}
+	if err != nil {
+		return err
+	}
+	return printJSON(adjustment)
+}
+
+func runCancelPass(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	result, err := a.cancellation.ProcessOddRecordCancellation(ctx)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runCancelShortfalls(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("cancel-shortfalls", flag.ContinueOnError)
+	limit := fs.Int("limit", 20, "maximum number of transactions")
+	offset := fs.Int("offset", 0, "number of transactions to skip")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errUsage
+	}
+
+	transactions, err := a.cancellation.ListCancellationShortfalls(ctx, *limit, *offset)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TRANSACTION_ID\tUSER_ID\tAMOUNT\tSTATUS\tPOLICY\tSHORTFALL\tREASON")
+	for _, t := range transactions {
+		shortfall := ""
+		if t.CancelShortfall != nil {
+			shortfall = t.CancelShortfall.StringFixed(2)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			t.TransactionID, t.UserID, t.Amount.StringFixed(2), t.Status, t.CancelPolicy, shortfall, t.CancelReason)
+	}
+	return w.Flush()
+}
+
+func runVerify(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	report, err := a.integrity.RunCheck(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Drifts) == 0 {
+		fmt.Println("all balances match transaction history")
+		return nil
+	}
+
+	paused := make(map[int64]bool, len(report.PausedUsers))
+	for _, userID := range report.PausedUsers {
+		paused[userID] = true
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER_ID\tBALANCE\tEXPECTED\tDRIFT\tPAUSED")
+	for _, d := range report.Drifts {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%t\n", d.UserID, d.Balance.StringFixed(2), d.Expected.StringFixed(2), d.Drift.StringFixed(2), paused[d.UserID])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%d user(s) with balance drift, total %s", len(report.Drifts), report.TotalDrift)
+}
+
+func runResume(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("resume", flag.ContinueOnError)
+	operator := fs.String("operator", "", "operator resuming the user (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *operator == "" {
+		return errUsage
+	}
+	userID, err := parseUserID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if err := a.integrity.ResumeUser(ctx, userID, *operator); err != nil {
+		return err
+	}
+	fmt.Printf("balance mutations resumed for user %d\n", userID)
+	return nil
+}
+
+func runPartitions(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	partitions, err := a.partitions.ListPartitions(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFROM\tTO\tARCHIVED_AT\tROWS\tARCHIVE")
+	for _, p := range partitions {
+		from, archivedAt := "-", "-"
+		if p.RangeStart != nil {
+			from = p.RangeStart.Format("2006-01-02")
+		}
+		if p.ArchivedAt != nil {
+			archivedAt = p.ArchivedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", p.Name, from, p.RangeEnd.Format("2006-01-02"), archivedAt, p.RowCount, p.ArchivePath)
+	}
+	return w.Flush()
+}
+
+func runArchive(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	if err := a.partitions.EnsurePartitions(ctx); err != nil {
+		return err
+	}
+
+	archived, err := a.partitions.ArchivePartitions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range archived {
+		fmt.Printf("archived %s: %d transaction(s) to %s\n", p.Name, p.RowCount, p.ArchivePath)
+	}
+	if len(archived) == 0 {
+		fmt.Println("no partitions to archive")
+	}
+	return nil
+}
+
+func runJobRuns(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("job-runs", flag.ContinueOnError)
+	job := fs.String("job", "", "job name, all jobs when empty")
+	limit := fs.Int("limit", 20, "maximum number of runs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || *limit <= 0 {
+		return errUsage
+	}
+
+	runs, err := a.jobRuns.GetJobRuns(ctx, *job, *limit)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tJOB\tTRIGGER\tSTATUS\tSTARTED_AT\tDURATION\tINSTANCE\tERROR")
+	for _, run := range runs {
+		duration := "-"
+		if run.FinishedAt != nil {
+			duration = run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			run.ID, run.Job, run.Trigger, run.Status, run.StartedAt.Format(time.RFC3339), duration, run.Instance, run.Error)
+	}
+	return w.Flush()
+}
+
+func parseUserID(s string) (int64, error) {
+	userID, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, fmt.Errorf("user_id must be a positive integer, got %q", s)
+	}
+	return userID, nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
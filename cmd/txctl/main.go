@@ -0,0 +1,150 @@
+// Command txctl runs operations tasks against the transaction processor database.
+// It is built on the same services and repositories as the API, so every balance change
+// goes through the same row locking.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/database"
+	"transaction-processor/internal/logger"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+	"transaction-processor/internal/repository/postgres"
+	"transaction-processor/internal/service"
+
+	"github.com/rs/zerolog"
+)
+
+const usage = `Usage: txctl <command> [flags] [args]
+
+Commands:
+  balance <user_id>                                       Show a user's balance
+  transactions [-limit N] [-offset N] <user_id>           List a user's transactions
+  transaction <transaction_id>                            Show a single transaction
+  transaction-events <transaction_id>                     Show a transaction's audit trail
+  cancel -operator NAME -reason CODE [-note TEXT] <id>    Cancel a processed transaction and reverse its balance effect
+  adjust -operator NAME -reason TEXT <user_id> <amount>   Request a credit (positive) or debit (negative), pending approval
+  adjustments [-status S] [-limit N] [-offset N]          List balance adjustments (default: pending)
+  approve-adjustment -operator NAME [-note TEXT] <id>     Approve and apply an adjustment requested by another operator
+  reject-adjustment -operator NAME [-note TEXT] <id>      Reject a pending adjustment
+  cancel-pass                                             Run the odd-record cancellation once
+  cancel-shortfalls [-limit N] [-offset N]                List cancellations the balance could not cover
+  verify                                                  Compare balances with transaction history
+  resume -operator NAME <user_id>                         Resume balance mutations paused by the integrity check
+  partitions                                              List transaction partitions
+  archive                                                 Create upcoming partitions and archive the ones past retention
+  job-runs [-job NAME] [-limit N]                         List the latest background job runs
+
+Configuration is read from the same environment variables as the server.
+`
+
+// app holds the services the commands run against
+type app struct {
+	transactions service.TransactionService
+	cancellation service.CancellationService
+	adjustments  service.AdjustmentService
+	integrity    service.IntegrityService
+	partitions   service.PartitionService
+	jobRuns      repository.JobRunRepository
+}
+
+type command func(ctx context.Context, a *app, args []string) error
+
+var commands = map[string]command{
+	"balance":            runBalance,
+	"transactions":       runTransactions,
+	"transaction":        runTransaction,
+	"transaction-events": runTransactionEvents,
+	"cancel":             runCancel,
+	"adjust":             runAdjust,
+	"adjustments":        runAdjustments,
+	"approve-adjustment": runApproveAdjustment,
+	"reject-adjustment":  runRejectAdjustment,
+	"cancel-pass":        runCancelPass,
+	"cancel-shortfalls":  runCancelShortfalls,
+	"verify":             runVerify,
+	"resume":             runResume,
+	"partitions":         runPartitions,
+	"archive":            runArchive,
+	"job-runs":           runJobRuns,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	// Logs go to stderr so command output can be piped
+	log := logger.New(true).Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "2006-01-02 15:04:05"})
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	dbCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	pools, err := database.NewPool(dbCtx, cfg.Database)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to database")
+	}
+	defer pools.Close()
+	dbPool := pools.Primary
+
+	// Operator commands always read from the primary
+	userRepo := postgres.NewUserRepository(dbPool, nil)
+	transactionRepo := postgres.NewTransactionRepository(dbPool, nil)
+	limitRepo := postgres.NewLimitRepository(dbPool)
+	adjustmentRepo := postgres.NewAdjustmentRepository(dbPool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(dbPool)
+	partitionRepo := postgres.NewPartitionRepository(dbPool)
+	workerStateRepo := postgres.NewWorkerStateRepository(dbPool)
+	txManager := postgres.NewTransactionManager(dbPool)
+
+	debtPolicy, err := service.NewDebtPolicy(cfg.Debt)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid debt config")
+	}
+	var tenants *service.Tenants
+	if cfg.Tenant.File != "" {
+		tenants, err = service.LoadTenants(cfg.Tenant.File)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid tenants")
+		}
+	}
+
+	a := &app{
+		transactions: service.NewTransactionService(userRepo, transactionRepo, limitRepo, idempotencyRepo, txManager, nil, nil, nil, nil, nil, cfg.Idempotency.KeyTTL, log),
+		cancellation: service.NewCancellationService(userRepo, transactionRepo, limitRepo, workerStateRepo, txManager,
+			model.ShortfallPolicy(cfg.Cancellation.ShortfallPolicy), tenants, debtPolicy, log),
+		adjustments: service.NewAdjustmentService(userRepo, transactionRepo, adjustmentRepo, txManager, log),
+		integrity:   service.NewIntegrityService(userRepo, cfg.Integrity.PauseOnDrift, log),
+		partitions: service.NewPartitionService(partitionRepo, txManager, cfg.Partition.PremakeMonths,
+			cfg.Partition.RetentionMonths, cfg.Partition.ArchiveDir, log),
+		jobRuns: postgres.NewJobRunRepository(dbPool),
+	}
+
+	if err := cmd(ctx, a, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		dbPool.Close()
+		os.Exit(1)
+	}
+}
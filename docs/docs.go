@@ -15,9 +15,56 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/transactions": {
+        "/admin/adjustments": {
+            "get": {
+                "description": "Returns adjustments with the given status, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "adjustments"
+                ],
+                "summary": "List balance adjustments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Status (pending, approved, rejected)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.AdjustmentListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Process a win/lost transaction from third-party provider",
+                "description": "Creates a pending credit (positive amount) or debit (negative amount), applied once a second operator approves it",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,60 +72,125 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "adjustments"
                 ],
-                "summary": "Process a transaction",
+                "summary": "Request a balance adjustment",
                 "parameters": [
                     {
-                        "enum": [
-                            "game",
-                            "server",
-                            "payment"
-                        ],
                         "type": "string",
-                        "description": "Source type",
-                        "name": "Source-Type",
+                        "description": "Requesting operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Adjustment",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.AdjustmentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BalanceAdjustment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/adjustments/{id}/approve": {
+            "post": {
+                "description": "Applies a pending adjustment to the user's balance, must be called by a different operator than the requester",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "adjustments"
+                ],
+                "summary": "Approve a balance adjustment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Approving operator",
+                        "name": "X-Operator-ID",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "Adjustment ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Transaction details",
-                        "name": "transaction",
+                        "description": "Decision note",
+                        "name": "decision",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionRequest"
+                            "$ref": "#/definitions/transaction-processor_internal_model.ReviewDecisionRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Already processed",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionResponse"
+                            "$ref": "#/definitions/transaction-processor_internal_model.BalanceAdjustment"
                         }
                     },
-                    "201": {
-                        "description": "Created",
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
-                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionResponse"
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "403": {
+                        "description": "Self approval",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Adjustment not found",
                         "schema": {
                             "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "Conflict",
+                        "description": "Not pending",
                         "schema": {
                             "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
                         }
@@ -86,24 +198,94 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/user/{id}": {
-            "get": {
-                "description": "Returns a paginated list of transactions for a user",
+        "/admin/adjustments/{id}/reject": {
+            "post": {
+                "description": "Declines a pending adjustment, the balance is not changed",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "adjustments"
                 ],
-                "summary": "Get user transactions",
+                "summary": "Reject a balance adjustment",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rejecting operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Adjustment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "description": "Decision note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ReviewDecisionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BalanceAdjustment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Self approval",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Adjustment not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not pending",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cancellations/shortfalls": {
+            "get": {
+                "description": "Returns transactions whose automatic cancellation failed (cancellation_failed) or was made with a shortfall (partial or debt), newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "List cancellations the balance could not cover",
+                "parameters": [
                     {
                         "type": "integer",
                         "default": 10,
@@ -125,59 +307,2035 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/transaction-processor_internal_model.TransactionListResponse"
                         }
-                    },
-                    "404": {
-                        "description": "User not found",
+                    }
+                }
+            }
+        },
+        "/admin/integrity": {
+            "get": {
+                "description": "Recomputes every balance from transaction history and reports users that drifted.\nDrifting users are paused when INTEGRITY_PAUSE_ON_DRIFT is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "integrity"
+                ],
+                "summary": "Run a balance integrity check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                            "$ref": "#/definitions/transaction-processor_internal_model.IntegrityReport"
                         }
                     }
                 }
             }
         },
-        "/users/{id}/balance": {
+        "/admin/partitions": {
             "get": {
-                "description": "Returns the current balance for a user",
+                "description": "Monthly partitions of the transactions table, archived ones include their archive file and row count",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "partitions"
                 ],
-                "summary": "Get user balance",
+                "summary": "List transaction partitions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transaction-processor_internal_model.TransactionPartition"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payments": {
+            "get": {
+                "description": "Returns deposits and withdrawals with the given status, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "List payments",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Status (pending, submitting, approved, completed, failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/transaction-processor_internal_model.BalanceResponse"
+                            "$ref": "#/definitions/transaction-processor_internal_model.PaymentListResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "400": {
+                        "description": "Invalid status",
                         "schema": {
                             "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "transaction-processor_internal_model.BalanceResponse": {
+        },
+        "/admin/payments/{id}/approve": {
+            "post": {
+                "description": "Submits a pending deposit or withdrawal to the payment provider, which reports the outcome with a callback.\nA payment left submitting by a failed submission is submitted again, the provider recognizes it by its transaction ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Approve a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Approving operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not pending or submitting",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payments/{id}/reject": {
+            "post": {
+                "description": "Fails a pending deposit or withdrawal, the reserved funds of a withdrawal go back to the balance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Reject a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rejecting operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "rejection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.PaymentRejectRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not pending",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews": {
+            "get": {
+                "description": "Returns transactions held for manual review, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "List transactions pending review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{transaction_id}/approve": {
+            "post": {
+                "description": "Applies a transaction held for review to the user's balance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Approve a pending transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ReviewDecisionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewing operator, required when auth is disabled",
+                        "name": "X-Operator-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not pending review",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{transaction_id}/reject": {
+            "post": {
+                "description": "Declines a transaction held for review, the balance is not changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Reject a pending transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ReviewDecisionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewing operator, required when auth is disabled",
+                        "name": "X-Operator-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not pending review",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transactions/{transaction_id}/cancel": {
+            "post": {
+                "description": "Reverses a processed transaction's balance change and records the operator, reason and note in its audit trail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Cancel a processed transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation reason",
+                        "name": "cancel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.CancelRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not cancellable",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transactions/{transaction_id}/events": {
+            "get": {
+                "description": "Returns every status change of a transaction with its actor, reason and balances, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get the audit trail of a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transaction-processor_internal_model.TransactionEvent"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/bonus": {
+            "post": {
+                "description": "Credits bonus funds that must be wagered before they become real money. The grant adds to the active bonus and restarts its expiry",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bonus"
+                ],
+                "summary": "Grant bonus funds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Grant",
+                        "name": "grant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BonusGrantRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BonusWallet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Mutations paused",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/bonus/entries": {
+            "get": {
+                "description": "Returns a user's bonus grants and expirations, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bonus"
+                ],
+                "summary": "List bonus grants and expirations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BonusEntryListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/provider": {
+            "put": {
+                "description": "Sets the game provider a user belongs to, the only provider whose tokens may reach the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Bind a user to a provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Provider",
+                        "name": "provider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.UserProviderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Provider not allowed for the tenant",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/resume": {
+            "post": {
+                "description": "Lifts the pause set by the integrity check once the drift has been resolved",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "integrity"
+                ],
+                "summary": "Resume balance mutations for a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/workers/cancellation": {
+            "get": {
+                "description": "Returns whether automatic cancellations are paused, the interval and the last run of any instance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workers"
+                ],
+                "summary": "Get the cancellation worker status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.WorkerStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/workers/cancellation/interval": {
+            "put": {
+                "description": "Changes the interval between automatic runs on all instances, it replaces the configured schedule until reset",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workers"
+                ],
+                "summary": "Change the cancellation interval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Interval",
+                        "name": "interval",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.WorkerIntervalRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.WorkerStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the interval set through the API, all instances return to WORKER_CANCELLATION_INTERVAL or WORKER_CANCELLATION_CRON",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workers"
+                ],
+                "summary": "Restore the configured cancellation schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.WorkerStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/workers/cancellation/pause": {
+            "post": {
+                "description": "Stops automatic cancellation runs on all instances until resumed, a run in progress completes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workers"
+                ],
+                "summary": "Pause automatic cancellations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.WorkerStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/workers/cancellation/resume": {
+            "post": {
+                "description": "Restarts automatic cancellation runs, the next run follows the current interval",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workers"
+                ],
+                "summary": "Resume automatic cancellations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.WorkerStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/workers/cancellation/run": {
+            "post": {
+                "description": "Runs the odd-record cancellation immediately on this instance and returns the worker status with its result",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workers"
+                ],
+                "summary": "Run a cancellation now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator",
+                        "name": "X-Operator-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.WorkerStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Worker paused or already running",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/callback": {
+            "post": {
+                "description": "Completes or fails an approved payment. The body must be signed by the provider, retries of the same outcome are acknowledged without changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Payment provider callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hex encoded HMAC-SHA256 of the body",
+                        "name": "X-Payment-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Outcome",
+                        "name": "callback",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.PaymentCallback"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not approved",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Mutations paused, retry later",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "post": {
+                "description": "Process a win/lost transaction from third-party provider",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Process a transaction",
+                "parameters": [
+                    {
+                        "enum": [
+                            "game",
+                            "server",
+                            "payment"
+                        ],
+                        "type": "string",
+                        "description": "Source type",
+                        "name": "Source-Type",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provider ID, used for per-provider amount limits",
+                        "name": "Provider-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction details",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Already processed",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending review",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Blocked by risk rules",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/user/{id}": {
+            "get": {
+                "description": "Returns a paginated list of transactions for a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get user transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/balance": {
+            "get": {
+                "description": "Returns the current balance for a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BalanceResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/balance/stream": {
+            "get": {
+                "description": "Server-sent events with the user's balance, sent on connect and after every change.\nEach event id is the balance version; reconnecting with Last-Event-ID skips the initial event if nothing changed.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Stream user balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last received event id",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event: balance",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BalanceEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/bonus": {
+            "get": {
+                "description": "Returns the bonus balance of a user, the amount left to wager before it is released and its expiry",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bonus"
+                ],
+                "summary": "Get user bonus balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BonusWallet"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/deposits": {
+            "post": {
+                "description": "Records a pending deposit, the balance is credited once an operator approves it and the payment provider completes it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Request a deposit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Deposit",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.PaymentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/limits": {
+            "get": {
+                "description": "Returns the responsible-gaming loss limits of a user with the net loss of the current windows",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user loss limits",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.LossLimitsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Sets the daily, weekly or monthly net loss limit. Decreases apply immediately, increases after a cooling-off period",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set a user loss limit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Loss limit",
+                        "name": "limit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.LossLimitRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.LossLimitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/payments": {
+            "get": {
+                "description": "Returns the deposits and withdrawals of a user, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "List user payments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.PaymentListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/withdrawals": {
+            "post": {
+                "description": "Moves the amount from the balance to the reserved funds and records a pending withdrawal. The funds go back to the balance if the withdrawal fails",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Request a withdrawal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Withdrawal",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.PaymentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or insufficient balance",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Mutations paused",
+                        "schema": {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "transaction-processor_internal_model.ActorType": {
+            "type": "string",
+            "enum": [
+                "provider",
+                "operator",
+                "worker"
+            ],
+            "x-enum-varnames": [
+                "ActorProvider",
+                "ActorOperator",
+                "ActorWorker"
+            ]
+        },
+        "transaction-processor_internal_model.AdjustmentListResponse": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transaction-processor_internal_model.BalanceAdjustment"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transaction-processor_internal_model.AdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "reason",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "-25.00"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Goodwill credit for outage"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "transaction-processor_internal_model.AdjustmentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "AdjustmentPending",
+                "AdjustmentApproved",
+                "AdjustmentRejected"
+            ]
+        },
+        "transaction-processor_internal_model.BalanceAdjustment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "string"
+                },
+                "decision_note": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/transaction-processor_internal_model.AdjustmentStatus"
+                },
+                "transaction_id": {
+                    "description": "TransactionID is the id of the 'adjustment' transaction recorded on approval",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transaction-processor_internal_model.BalanceDrift": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "drift": {
+                    "type": "number"
+                },
+                "expected": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transaction-processor_internal_model.BalanceEvent": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "transaction-processor_internal_model.BalanceResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance is real money, Bonus the bonus balance still to be wagered and Total their sum",
+                    "type": "string",
+                    "example": "100.50"
+                },
+                "bonus": {
+                    "type": "string",
+                    "example": "20.00"
+                },
+                "debt": {
+                    "description": "Debt is repaid from the wins of source types in debt mode before they reach the balance",
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "reserved": {
+                    "description": "Reserved is held for withdrawals in progress and not included in Balance",
+                    "type": "string",
+                    "example": "0.00"
+                },
+                "total": {
+                    "type": "string",
+                    "example": "120.50"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "transaction-processor_internal_model.BonusEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor is the granting operator, or the expiry job",
+                    "type": "string",
+                    "example": "alice"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/transaction-processor_internal_model.BonusEntryKind"
+                        }
+                    ],
+                    "example": "granted"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "wagering": {
+                    "type": "number"
+                }
+            }
+        },
+        "transaction-processor_internal_model.BonusEntryKind": {
+            "type": "string",
+            "enum": [
+                "granted",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "BonusGranted",
+                "BonusExpired"
+            ]
+        },
+        "transaction-processor_internal_model.BonusEntryListResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transaction-processor_internal_model.BonusEntry"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transaction-processor_internal_model.BonusGrantRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "20.00"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Welcome bonus"
+                },
+                "wagering": {
+                    "type": "string",
+                    "example": "600.00"
+                }
+            }
+        },
+        "transaction-processor_internal_model.BonusWallet": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "wagering": {
+                    "type": "number"
+                }
+            }
+        },
+        "transaction-processor_internal_model.CancelRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Player disputed the round, ticket #4411"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "provider_error",
+                        "duplicate",
+                        "fraud",
+                        "customer_request",
+                        "other"
+                    ],
+                    "example": "customer_request"
+                }
+            }
+        },
+        "transaction-processor_internal_model.CancellationResult": {
+            "type": "object",
+            "properties": {
+                "cancellation_failed": {
+                    "description": "CancellationFailed could not be reversed because of the balance and were marked cancellation_failed",
+                    "type": "integer"
+                },
+                "cancelled": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "requested": {
+                    "type": "integer"
+                },
+                "shortfalls": {
+                    "description": "Shortfalls were cancelled although the balance could not cover the reversal (partial or negative balance)",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Skipped were claimed by another run, or could not be cancelled because of paused mutations",
+                    "type": "integer"
+                }
+            }
+        },
+        "transaction-processor_internal_model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "INSUFFICIENT_BALANCE"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "insufficient balance"
+                },
+                "mismatches": {
+                    "description": "Mismatches lists the fields that differ from the stored transaction for TRANSACTION_MISMATCH",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transaction-processor_internal_model.FieldMismatch"
+                    }
+                }
+            }
+        },
+        "transaction-processor_internal_model.FieldMismatch": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "amount"
+                },
+                "received": {
+                    "type": "string",
+                    "example": "99"
+                },
+                "stored": {
+                    "type": "string",
+                    "example": "10.5"
+                }
+            }
+        },
+        "transaction-processor_internal_model.IntegrityReport": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "drifts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transaction-processor_internal_model.BalanceDrift"
+                    }
+                },
+                "paused_users": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "total_drift": {
+                    "description": "TotalDrift is the sum of absolute drifts",
+                    "type": "string"
+                }
+            }
+        },
+        "transaction-processor_internal_model.LossLimitRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "period"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly"
+                    ],
+                    "example": "daily"
+                }
+            }
+        },
+        "transaction-processor_internal_model.LossLimitResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "net_loss": {
+                    "type": "string",
+                    "example": "25.00"
+                },
+                "pending_amount": {
+                    "type": "string",
+                    "example": "200.00"
+                },
+                "pending_effective_at": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string",
+                    "example": "daily"
+                },
+                "remaining": {
+                    "type": "string",
+                    "example": "75.00"
+                }
+            }
+        },
+        "transaction-processor_internal_model.LossLimitsResponse": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "type": "string",
-                    "example": "100.50"
+                "limits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transaction-processor_internal_model.LossLimitResponse"
+                    }
                 },
                 "user_id": {
                     "type": "integer",
@@ -185,33 +2343,198 @@ const docTemplate = `{
                 }
             }
         },
-        "transaction-processor_internal_model.ErrorResponse": {
+        "transaction-processor_internal_model.Payment": {
             "type": "object",
             "properties": {
-                "code": {
+                "amount": {
+                    "type": "number"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "description": "DecidedBy is the operator who approved or rejected the payment",
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/transaction-processor_internal_model.PaymentKind"
+                        }
+                    ],
+                    "example": "withdrawal"
+                },
+                "provider": {
+                    "description": "Provider and ProviderReference identify the payment at the provider once it is submitted",
                     "type": "string",
-                    "example": "INSUFFICIENT_BALANCE"
+                    "example": "stub"
                 },
-                "details": {
+                "provider_reference": {
                     "type": "string"
                 },
-                "error": {
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/transaction-processor_internal_model.PaymentStatus"
+                        }
+                    ],
+                    "example": "pending"
+                },
+                "transaction_id": {
+                    "description": "TransactionID is the id of the 'payment' transaction recorded on completion",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transaction-processor_internal_model.PaymentCallback": {
+            "type": "object",
+            "required": [
+                "reference",
+                "status"
+            ],
+            "properties": {
+                "reason": {
                     "type": "string",
-                    "example": "insufficient balance"
+                    "example": "card declined"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "stub-6f1c2a"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "completed",
+                        "failed"
+                    ],
+                    "example": "completed"
+                }
+            }
+        },
+        "transaction-processor_internal_model.PaymentKind": {
+            "type": "string",
+            "enum": [
+                "deposit",
+                "withdrawal"
+            ],
+            "x-enum-varnames": [
+                "PaymentDeposit",
+                "PaymentWithdrawal"
+            ]
+        },
+        "transaction-processor_internal_model.PaymentListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transaction-processor_internal_model.Payment"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transaction-processor_internal_model.PaymentRejectRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Account under KYC review"
+                }
+            }
+        },
+        "transaction-processor_internal_model.PaymentRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "50.00"
+                }
+            }
+        },
+        "transaction-processor_internal_model.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "submitting",
+                "approved",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "PaymentPending",
+                "PaymentSubmitting",
+                "PaymentApproved",
+                "PaymentCompleted",
+                "PaymentFailed"
+            ]
+        },
+        "transaction-processor_internal_model.ReviewDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Verified with provider"
                 }
             }
         },
+        "transaction-processor_internal_model.ShortfallPolicy": {
+            "type": "string",
+            "enum": [
+                "skip",
+                "debt",
+                "partial"
+            ],
+            "x-enum-varnames": [
+                "ShortfallSkip",
+                "ShortfallDebt",
+                "ShortfallPartial"
+            ]
+        },
         "transaction-processor_internal_model.SourceType": {
             "type": "string",
             "enum": [
                 "game",
                 "server",
-                "payment"
+                "payment",
+                "adjustment",
+                "settlement"
             ],
             "x-enum-varnames": [
                 "SourceGame",
                 "SourceServer",
-                "SourcePayment"
+                "SourcePayment",
+                "SourceAdjustment",
+                "SourceSettlement"
             ]
         },
         "transaction-processor_internal_model.State": {
@@ -231,6 +2554,24 @@ const docTemplate = `{
                 "amount": {
                     "type": "number"
                 },
+                "bonus_amount": {
+                    "description": "BonusAmount is the part of Amount taken from or credited to the bonus balance",
+                    "type": "number"
+                },
+                "cancel_policy": {
+                    "description": "CancelPolicy and CancelShortfall are set when the balance could not cover the reversal:\nthe policy applied and the amount that was missing",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ShortfallPolicy"
+                        }
+                    ]
+                },
+                "cancel_reason": {
+                    "type": "string"
+                },
+                "cancel_shortfall": {
+                    "type": "number"
+                },
                 "cancelled_at": {
                     "type": "string"
                 },
@@ -240,6 +2581,30 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "provider_id": {
+                    "type": "string"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "review_reason": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "risk_decision": {
+                    "type": "string"
+                },
+                "risk_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "source_type": {
                     "$ref": "#/definitions/transaction-processor_internal_model.SourceType"
                 },
@@ -260,6 +2625,78 @@ const docTemplate = `{
                 }
             }
         },
+        "transaction-processor_internal_model.TransactionEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor is the provider ID, operator or worker name",
+                    "type": "string",
+                    "example": "alice"
+                },
+                "actor_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/transaction-processor_internal_model.ActorType"
+                        }
+                    ],
+                    "example": "operator"
+                },
+                "balance_after": {
+                    "type": "number"
+                },
+                "balance_before": {
+                    "description": "Balances are set when the change moved the balance or was made with the user locked",
+                    "type": "number"
+                },
+                "bonus_after": {
+                    "type": "number"
+                },
+                "bonus_before": {
+                    "description": "Bonuses are set when the change moved the bonus balance",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "debt_after": {
+                    "type": "number"
+                },
+                "debt_before": {
+                    "description": "Debts are set when the change created or repaid debt",
+                    "type": "number"
+                },
+                "from_status": {
+                    "description": "FromStatus is empty for the event creating the transaction",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/transaction-processor_internal_model.TransactionStatus"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "customer_request"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "$ref": "#/definitions/transaction-processor_internal_model.TransactionStatus"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "transaction-processor_internal_model.TransactionListResponse": {
             "type": "object",
             "properties": {
@@ -280,6 +2717,33 @@ const docTemplate = `{
                 }
             }
         },
+        "transaction-processor_internal_model.TransactionPartition": {
+            "type": "object",
+            "properties": {
+                "archive_path": {
+                    "type": "string"
+                },
+                "archived_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "transactions_p2026_10"
+                },
+                "range_end": {
+                    "type": "string"
+                },
+                "range_start": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                }
+            }
+        },
         "transaction-processor_internal_model.TransactionRequest": {
             "type": "object",
             "required": [
@@ -327,12 +2791,87 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "processed",
-                "cancelled"
+                "cancelled",
+                "pending_review",
+                "rejected",
+                "blocked",
+                "cancellation_failed"
             ],
             "x-enum-varnames": [
                 "StatusProcessed",
-                "StatusCancelled"
+                "StatusCancelled",
+                "StatusPendingReview",
+                "StatusRejected",
+                "StatusBlocked",
+                "StatusCancellationFailed"
             ]
+        },
+        "transaction-processor_internal_model.UserProviderRequest": {
+            "type": "object",
+            "required": [
+                "provider_id"
+            ],
+            "properties": {
+                "provider_id": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "acme"
+                }
+            }
+        },
+        "transaction-processor_internal_model.WorkerIntervalRequest": {
+            "type": "object",
+            "required": [
+                "interval"
+            ],
+            "properties": {
+                "interval": {
+                    "description": "Interval is a Go duration, e.g. \"30s\" or \"5m\"",
+                    "type": "string",
+                    "example": "5m"
+                }
+            }
+        },
+        "transaction-processor_internal_model.WorkerStatus": {
+            "type": "object",
+            "properties": {
+                "interval": {
+                    "description": "Interval is the duration between runs, or the cron expression when the worker runs on one",
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_result": {
+                    "$ref": "#/definitions/transaction-processor_internal_model.CancellationResult"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "last_run_duration": {
+                    "type": "string"
+                },
+                "leader": {
+                    "description": "Leader is true if scheduled runs happen on this instance",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "paused_by": {
+                    "type": "string"
+                },
+                "running": {
+                    "description": "Running is true while a run is in progress on any instance",
+                    "type": "boolean"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,132 @@
+// Package archive reads and writes exported transaction partitions as gzip-compressed JSON lines.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"transaction-processor/internal/model"
+)
+
+// Extension of archive files, one file per partition
+const Extension = ".jsonl.gz"
+
+// Path returns the archive file of a partition in dir
+func Path(dir, partition string) string {
+	return filepath.Join(dir, partition+Extension)
+}
+
+// Writer writes transactions to a temporary file that replaces the archive on Commit,
+// so a failed export never leaves a partial archive behind
+type Writer struct {
+	path  string
+	file  *os.File
+	gz    *gzip.Writer
+	buf   *bufio.Writer
+	enc   *json.Encoder
+	count int64
+}
+
+func Create(path string) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create archive dir: %w", err)
+	}
+
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, fmt.Errorf("create archive file: %w", err)
+	}
+
+	gz := gzip.NewWriter(file)
+	buf := bufio.NewWriter(gz)
+	return &Writer{
+		path: path,
+		file: file,
+		gz:   gz,
+		buf:  buf,
+		enc:  json.NewEncoder(buf),
+	}, nil
+}
+
+func (w *Writer) Write(trans *model.Transaction) error {
+	if err := w.enc.Encode(trans); err != nil {
+		return fmt.Errorf("write archived transaction: %w", err)
+	}
+	w.count++
+	return nil
+}
+
+// Count returns the number of transactions written so far
+func (w *Writer) Count() int64 {
+	return w.count
+}
+
+// Commit flushes and syncs the file and moves it to its final path
+func (w *Writer) Commit() error {
+	if err := w.buf.Flush(); err != nil {
+		w.Abort()
+		return fmt.Errorf("flush archive: %w", err)
+	}
+	if err := w.gz.Close(); err != nil {
+		w.Abort()
+		return fmt.Errorf("close archive: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		w.Abort()
+		return fmt.Errorf("sync archive: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("close archive file: %w", err)
+	}
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("rename archive: %w", err)
+	}
+	return nil
+}
+
+// Abort discards the temporary file
+func (w *Writer) Abort() {
+	w.file.Close()
+	os.Remove(w.file.Name())
+}
+
+// Find scans an archive for a transaction, returning model.ErrTransactionNotFound if it is not there
+// and model.ErrArchiveUnavailable if the archive file is missing, e.g. on an instance without the archive storage
+func Find(path, transactionID string) (*model.Transaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s not found", model.ErrArchiveUnavailable, path)
+		}
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	defer gz.Close()
+
+	dec := json.NewDecoder(gz)
+	for {
+		var trans model.Transaction
+		if err := dec.Decode(&trans); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, model.ErrTransactionNotFound
+			}
+			return nil, fmt.Errorf("decode archived transaction: %w", err)
+		}
+		if trans.TransactionID == transactionID {
+			return &trans, nil
+		}
+	}
+}
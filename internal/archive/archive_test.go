@@ -0,0 +1,66 @@
+package archive
+
+import (
+	"os"
+	"testing"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndFind(t *testing.T) {
+	path := Path(t.TempDir(), "transactions_p2025_01")
+
+	w, err := Create(path)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(&model.Transaction{
+		ID:            1,
+		TransactionID: "550e8400-e29b-41d4-a716-446655440000",
+		UserID:        1,
+		SourceType:    model.SourceGame,
+		State:         model.StateWin,
+		Amount:        decimal.RequireFromString("10.50"),
+		Status:        model.StatusProcessed,
+	}))
+	require.NoError(t, w.Write(&model.Transaction{
+		ID:            2,
+		TransactionID: "550e8400-e29b-41d4-a716-446655440001",
+		UserID:        2,
+		SourceType:    model.SourcePayment,
+		State:         model.StateLost,
+		Amount:        decimal.RequireFromString("3.00"),
+		Status:        model.StatusCancelled,
+	}))
+	require.NoError(t, w.Commit())
+	assert.Equal(t, int64(2), w.Count())
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err), "temporary file must be renamed")
+
+	trans, err := Find(path, "550e8400-e29b-41d4-a716-446655440001")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), trans.UserID)
+	assert.Equal(t, model.StatusCancelled, trans.Status)
+	assert.True(t, trans.Amount.Equal(decimal.RequireFromString("3")))
+
+	_, err = Find(path, "550e8400-e29b-41d4-a716-446655440099")
+	assert.ErrorIs(t, err, model.ErrTransactionNotFound)
+
+	_, err = Find(Path(t.TempDir(), "transactions_p2025_01"), "550e8400-e29b-41d4-a716-446655440001")
+	assert.ErrorIs(t, err, model.ErrArchiveUnavailable)
+}
+
+func TestAbort_RemovesTemporaryFile(t *testing.T) {
+	path := Path(t.TempDir(), "transactions_p2025_02")
+
+	w, err := Create(path)
+	require.NoError(t, err)
+	w.Abort()
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
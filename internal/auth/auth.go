@@ -0,0 +1,106 @@
+// Package auth authenticates API callers with bearer tokens (JWT) issued by an OIDC identity provider and
+// decides which routes their roles allow.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"transaction-processor/internal/model"
+)
+
+// Role is granted to a caller by the identity provider, see config.AuthConfig.RolesClaim
+type Role string
+
+const (
+	// RoleProvider posts transactions and reads balances for the users of its tenant, its tokens must name the
+	// provider, see config.AuthConfig.ProviderClaim
+	RoleProvider Role = "provider"
+	// RoleSupport reads balances, history and back-office queues without changing them
+	RoleSupport Role = "support"
+	// RoleOperator cancels transactions, adjusts balances and decides reviews and payments
+	RoleOperator Role = "operator"
+	// RoleAdmin configures the service, e.g. the workers and partitions, and may do everything the other roles may
+	RoleAdmin Role = "admin"
+)
+
+var roles = map[string]Role{
+	string(RoleProvider): RoleProvider,
+	string(RoleSupport):  RoleSupport,
+	string(RoleOperator): RoleOperator,
+	string(RoleAdmin):    RoleAdmin,
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Subject is the sub claim, recorded as the operator of back-office changes
+	Subject string
+	Roles   []Role
+	// TenantID is empty if the token does not name a tenant
+	TenantID string
+	// ProviderID is the only Provider-ID the caller may post transactions with and the provider of the users it may
+	// post for, any when empty. Provider tokens always have one.
+	ProviderID string
+}
+
+// HasAnyRole reports whether the principal has one of the roles, admins have them all
+func (p *Principal) HasAnyRole(allowed ...Role) bool {
+	for _, role := range p.Roles {
+		if role == RoleAdmin {
+			return true
+		}
+		for _, a := range allowed {
+			if role == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RoleNames returns the roles as strings, e.g. for logging
+func (p *Principal) RoleNames() []string {
+	names := make([]string, len(p.Roles))
+	for i, role := range p.Roles {
+		names[i] = string(role)
+	}
+	return names
+}
+
+type principalKey struct{}
+
+// WithPrincipal attaches the authenticated caller to the context
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the authenticated caller, nil when tokens are disabled and outside requests
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// ProviderOf returns the provider the caller's token is restricted to, empty if it is not
+func ProviderOf(ctx context.Context) string {
+	if p := FromContext(ctx); p != nil {
+		return p.ProviderID
+	}
+	return ""
+}
+
+// CheckProvider returns ErrProviderNotAllowed if the caller's token restricts it to a different Provider-ID
+func CheckProvider(ctx context.Context, providerID string) error {
+	restricted := ProviderOf(ctx)
+	if restricted == "" || restricted == providerID {
+		return nil
+	}
+	return fmt.Errorf("%w: token is restricted to provider %s", model.ErrProviderNotAllowed, restricted)
+}
+
+// CheckUser returns ErrProviderNotAllowed if the caller's token restricts it to a provider the user does not belong to
+func CheckUser(ctx context.Context, user *model.User) error {
+	restricted := ProviderOf(ctx)
+	if restricted == "" || restricted == user.ProviderID {
+		return nil
+	}
+	return fmt.Errorf("%w: user %d does not belong to provider %s", model.ErrProviderNotAllowed, user.ID, restricted)
+}
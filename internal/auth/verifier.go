@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+
+	"github.com/MicahParks/jwkset"
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits rejects keys too short to be trusted
+const minRSABits = 2048
+
+// signingMethods are the accepted JWS algorithms, symmetric ones and none are refused as the keys are public
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Verifier validates bearer tokens against the keys of a JWKS file. A nil *Verifier accepts no tokens and
+// authentication is disabled, see Enabled.
+type Verifier struct {
+	keys   keyfunc.Keyfunc
+	parser *jwt.Parser
+	cfg    config.AuthConfig
+}
+
+// LoadVerifier reads the key set named by cfg.JWKSFile
+func LoadVerifier(cfg config.AuthConfig) (*Verifier, error) {
+	data, err := os.ReadFile(cfg.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	return NewVerifier(data, cfg)
+}
+
+// NewVerifier builds a verifier from a JSON Web Key Set document. Only RSA and EC signing keys are kept, RSA keys
+// shorter than minRSABits are refused.
+func NewVerifier(jwks []byte, cfg config.AuthConfig) (*Verifier, error) {
+	var set jwkset.JWKSMarshal
+	if err := json.Unmarshal(jwks, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	parsed, err := set.ToStorage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	ctx := context.Background()
+	all, err := parsed.KeyReadAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	signing := jwkset.NewMemoryStorage()
+	for _, key := range all {
+		m := key.Marshal()
+		if (m.KTY != jwkset.KtyRSA && m.KTY != jwkset.KtyEC) || (m.USE != "" && m.USE != jwkset.UseSig) {
+			continue
+		}
+		if pub, ok := key.Key().(*rsa.PublicKey); ok && pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("JWKS key %s has %d bits, at least %d are required", m.KID, pub.N.BitLen(), minRSABits)
+		}
+		if err := signing.KeyWrite(ctx, key); err != nil {
+			return nil, fmt.Errorf("failed to store JWKS key %s: %w", m.KID, err)
+		}
+	}
+	if keys, _ := signing.KeyReadAll(ctx); len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS has no RSA or EC signing keys")
+	}
+
+	keys, err := keyfunc.New(keyfunc.Options{Storage: signing})
+	if err != nil {
+		return nil, err
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.ClockSkew),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	return &Verifier{keys: keys, parser: jwt.NewParser(options...), cfg: cfg}, nil
+}
+
+// Enabled reports whether callers must present a bearer token
+func (v *Verifier) Enabled() bool {
+	return v != nil
+}
+
+// Verify checks the signature and claims of a compact JWS token and returns its principal,
+// errors wrap model.ErrInvalidToken
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidToken, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: sub claim is required", model.ErrInvalidToken)
+	}
+
+	p := &Principal{Subject: subject}
+	// Identity providers share role claims between applications, roles of other applications are ignored
+	for _, name := range stringList(claims[v.cfg.RolesClaim]) {
+		if role, ok := roles[name]; ok {
+			p.Roles = append(p.Roles, role)
+		}
+	}
+	p.TenantID, _ = claims[v.cfg.TenantClaim].(string)
+	p.ProviderID, _ = claims[v.cfg.ProviderClaim].(string)
+	// A provider token without a provider would act for every provider of its tenant
+	if p.ProviderID == "" && slices.Contains(p.Roles, RoleProvider) {
+		return nil, fmt.Errorf("%w: provider tokens need the %s claim", model.ErrInvalidToken, v.cfg.ProviderClaim)
+	}
+	return p, nil
+}
+
+// key returns the keys a token may be signed with. Tokens with critical headers are refused, none are supported.
+func (v *Verifier) key(token *jwt.Token) (any, error) {
+	if _, ok := token.Header["crit"]; ok {
+		return nil, fmt.Errorf("unsupported critical header")
+	}
+	return v.keys.Keyfunc(token)
+}
+
+// stringList reads a claim that is either a string array or a single, space separated string like OAuth scopes
+func stringList(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
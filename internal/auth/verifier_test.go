@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = config.AuthConfig{
+	Issuer:        "https://idp.example.com",
+	Audience:      "transaction-processor",
+	RolesClaim:    "roles",
+	TenantClaim:   "tenant_id",
+	ProviderClaim: "provider_id",
+	ClockSkew:     30 * time.Second,
+}
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func ecJWKS(t *testing.T, kid string, key *ecdsa.PrivateKey) []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+	data, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		// Encryption keys are skipped
+		{"kty": "RSA", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		{"kty": "EC", "kid": kid, "crv": "P-256", "x": base64.RawURLEncoding.EncodeToString(x), "y": base64.RawURLEncoding.EncodeToString(y)},
+	}})
+	require.NoError(t, err)
+	return data
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, header, claims map[string]any) string {
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return fmt.Sprintf("%s.%s", signingInput, base64.RawURLEncoding.EncodeToString(signature))
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":         "https://idp.example.com",
+		"aud":         []string{"other-app", "transaction-processor"},
+		"sub":         "alice",
+		"exp":         time.Now().Add(time.Hour).Unix(),
+		"roles":       []string{"operator", "offline_access"},
+		"tenant_id":   "brand-a",
+		"provider_id": "acme",
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	key := newECKey(t)
+	verifier, err := NewVerifier(ecJWKS(t, "k1", key), testConfig)
+	require.NoError(t, err)
+
+	p, err := verifier.Verify(signES256(t, key, map[string]any{"alg": "ES256", "kid": "k1"}, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Subject: "alice", Roles: []Role{RoleOperator}, TenantID: "brand-a", ProviderID: "acme"}, p)
+
+	// A token without kid is checked against every key
+	_, err = verifier.Verify(signES256(t, key, map[string]any{"alg": "ES256"}, validClaims()))
+	assert.NoError(t, err)
+}
+
+func TestVerifier_Verify_Invalid(t *testing.T) {
+	key := newECKey(t)
+	verifier, err := NewVerifier(ecJWKS(t, "k1", key), testConfig)
+	require.NoError(t, err)
+	header := map[string]any{"alg": "ES256", "kid": "k1"}
+
+	with := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "not-a-token"},
+		{"alg none", signES256(t, key, map[string]any{"alg": "none"}, validClaims())},
+		{"symmetric alg", signES256(t, key, map[string]any{"alg": "HS256"}, validClaims())},
+		{"wrong curve for alg", signES256(t, key, map[string]any{"alg": "ES384", "kid": "k1"}, validClaims())},
+		{"unknown kid", signES256(t, key, map[string]any{"alg": "ES256", "kid": "k2"}, validClaims())},
+		{"other key", signES256(t, newECKey(t), header, validClaims())},
+		{"critical header", signES256(t, key, map[string]any{"alg": "ES256", "crit": []string{"b64"}}, validClaims())},
+		{"expired", signES256(t, key, header, with("exp", time.Now().Add(-time.Minute).Unix()))},
+		{"missing exp", signES256(t, key, header, with("exp", nil))},
+		{"not yet valid", signES256(t, key, header, with("nbf", time.Now().Add(time.Minute).Unix()))},
+		{"wrong issuer", signES256(t, key, header, with("iss", "https://evil.example.com"))},
+		{"wrong audience", signES256(t, key, header, with("aud", "other-app"))},
+		{"missing subject", signES256(t, key, header, with("sub", nil))},
+		{"provider without provider claim", signES256(t, key, header, map[string]any{
+			"iss": "https://idp.example.com", "aud": "transaction-processor", "sub": "acme-games",
+			"exp": time.Now().Add(time.Hour).Unix(), "roles": []string{"provider"},
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token)
+			assert.ErrorIs(t, err, model.ErrInvalidToken)
+		})
+	}
+}
+
+func TestVerifier_Verify_ClockSkew(t *testing.T) {
+	key := newECKey(t)
+	verifier, err := NewVerifier(ecJWKS(t, "k1", key), testConfig)
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	claims["roles"] = "support provider"
+
+	p, err := verifier.Verify(signES256(t, key, map[string]any{"alg": "ES256"}, claims))
+	require.NoError(t, err)
+	assert.Equal(t, []Role{RoleSupport, RoleProvider}, p.Roles)
+}
+
+func TestNewVerifier_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		jwks string
+	}{
+		{"not json", "keys"},
+		{"no signing keys", `{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`},
+		{"short RSA key", `{"keys": [{"kty": "RSA", "n": "AQAB", "e": "AQAB"}]}`},
+		{"unknown curve", `{"keys": [{"kty": "EC", "crv": "P-192", "x": "AA", "y": "AA"}]}`},
+		{"point not on curve", fmt.Sprintf(`{"keys": [{"kty": "EC", "crv": "P-256", "x": "%s", "y": "%s"}]}`,
+			base64.RawURLEncoding.EncodeToString(make([]byte, 32)), base64.RawURLEncoding.EncodeToString(make([]byte, 32)))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewVerifier([]byte(tt.jwks), testConfig)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPrincipal_HasAnyRole(t *testing.T) {
+	support := &Principal{Roles: []Role{RoleSupport}}
+	assert.True(t, support.HasAnyRole(RoleSupport, RoleOperator))
+	assert.False(t, support.HasAnyRole(RoleOperator))
+
+	admin := &Principal{Roles: []Role{RoleAdmin}}
+	assert.True(t, admin.HasAnyRole(RoleProvider))
+	assert.False(t, (&Principal{}).HasAnyRole(RoleSupport))
+}
+
+func TestCheckProvider(t *testing.T) {
+	assert.NoError(t, CheckProvider(context.Background(), "acme"))
+
+	ctx := WithPrincipal(context.Background(), &Principal{Subject: "acme-games", ProviderID: "acme"})
+	assert.NoError(t, CheckProvider(ctx, "acme"))
+	assert.ErrorIs(t, CheckProvider(ctx, "other"), model.ErrProviderNotAllowed)
+	assert.ErrorIs(t, CheckProvider(ctx, ""), model.ErrProviderNotAllowed)
+
+	unrestricted := WithPrincipal(context.Background(), &Principal{Subject: "ops"})
+	assert.NoError(t, CheckProvider(unrestricted, "other"))
+}
+
+func TestCheckUser(t *testing.T) {
+	ctx := WithPrincipal(context.Background(), &Principal{Subject: "acme-games", ProviderID: "acme"})
+	assert.NoError(t, CheckUser(ctx, &model.User{ID: 1, ProviderID: "acme"}))
+	assert.ErrorIs(t, CheckUser(ctx, &model.User{ID: 1, ProviderID: "other"}), model.ErrProviderNotAllowed)
+	assert.ErrorIs(t, CheckUser(ctx, &model.User{ID: 1}), model.ErrProviderNotAllowed)
+
+	unrestricted := WithPrincipal(context.Background(), &Principal{Subject: "ops"})
+	assert.NoError(t, CheckUser(unrestricted, &model.User{ID: 1, ProviderID: "other"}))
+	assert.NoError(t, CheckUser(context.Background(), &model.User{ID: 1}))
+}
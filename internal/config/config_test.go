@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, 2*time.Minute, cfg.Worker.CancellationInterval)
+	assert.Equal(t, "debug", cfg.Log.Level)
+}
+
+func TestLoad_FileThenEnv(t *testing.T) {
+	writeConfigFile(t, `
+server:
+  port: "8081"
+worker:
+  cancellation_interval: 30s
+  integrity_interval: 5m
+limits:
+  source_max_amount:
+    game: 500
+`)
+	t.Setenv("WORKER_INTEGRITY_INTERVAL", "1m")
+	t.Setenv("DB_REPLICA_DSN", "")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "8081", cfg.Server.Port)
+	assert.Equal(t, "9090", cfg.Server.GRPCPort)
+	assert.Equal(t, 30*time.Second, cfg.Worker.CancellationInterval)
+	assert.Equal(t, time.Minute, cfg.Worker.IntegrityInterval)
+	assert.True(t, decimal.NewFromInt(500).Equal(cfg.Limits.SourceMaxAmount["game"]))
+}
+
+func TestLoad_UnknownFileKey(t *testing.T) {
+	writeConfigFile(t, `
+worker:
+  cancelation_interval: 30s
+`)
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "cancelation_interval")
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("WORKER_CANCELLATION_INTERVAL", "-1s")
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("LIMITS_SOURCE_MAX_AMOUNT", "casino:10")
+	t.Setenv("CANCELLATION_SHORTFALL_POLICY", "forgive")
+	t.Setenv("DEBT_SOURCE_TYPES", "game,casino")
+	t.Setenv("BONUS_SPEND_ORDER", "random")
+	t.Setenv("PAYMENT_PROVIDER", "paypal")
+	t.Setenv("AUTH_JWKS_FILE", "jwks.json")
+	t.Setenv("AUTH_DISABLED", "true")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "WORKER_CANCELLATION_INTERVAL must be positive")
+	assert.ErrorContains(t, err, "LOG_LEVEL must be one of")
+	assert.ErrorContains(t, err, "LIMITS_SOURCE_MAX_AMOUNT has unknown source type casino")
+	assert.ErrorContains(t, err, "CANCELLATION_SHORTFALL_POLICY must be one of")
+	assert.ErrorContains(t, err, "DEBT_SOURCE_TYPES has unknown source type casino")
+	assert.ErrorContains(t, err, "BONUS_SPEND_ORDER must be one of")
+	assert.ErrorContains(t, err, "PAYMENT_PROVIDER must be stub")
+	assert.ErrorContains(t, err, "AUTH_DISABLED must not be set together with AUTH_JWKS_FILE")
+}
+
+func TestReloader_AppliesOnlyReloadableSettings(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  port: "8080"
+worker:
+  cancellation_interval: 2m
+`)
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	reloader := NewReloader(cfg, zerolog.Nop())
+	var applied *Config
+	reloader.OnReload(func(cfg *Config) error {
+		applied = cfg
+		return nil
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  port: "9999"
+worker:
+  cancellation_interval: 10s
+`), 0o600))
+	require.NoError(t, reloader.Reload())
+
+	require.NotNil(t, applied)
+	assert.Equal(t, 10*time.Second, applied.Worker.CancellationInterval)
+	assert.Equal(t, "8080", applied.Server.Port)
+}
+
+func TestReloader_RejectsInvalidConfig(t *testing.T) {
+	path := writeConfigFile(t, "")
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	reloader := NewReloader(cfg, zerolog.Nop())
+	called := false
+	reloader.OnReload(func(*Config) error {
+		called = true
+		return nil
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("worker:\n  cancellation_interval: 0s\n"), 0o600))
+	assert.Error(t, reloader.Reload())
+	assert.False(t, called)
+}
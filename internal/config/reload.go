@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+
+	"github.com/rs/zerolog"
+)
+
+// Reloader reloads the configuration on SIGHUP and passes it to the registered handlers.
+// Only fields tagged reload:"true" change, other changed settings are reported and keep their running value.
+type Reloader struct {
+	mu       sync.Mutex
+	current  *Config
+	handlers []func(cfg *Config) error
+	logger   zerolog.Logger
+}
+
+func NewReloader(cfg *Config, logger zerolog.Logger) *Reloader {
+	return &Reloader{
+		current: cfg,
+		logger:  logger,
+	}
+}
+
+// OnReload registers a handler applying the reloadable settings of a new configuration
+func (r *Reloader) OnReload(fn func(cfg *Config) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, fn)
+}
+
+// Run reloads on every SIGHUP until the context is cancelled
+func (r *Reloader) Run(ctx context.Context) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-sig:
+			if err := r.Reload(); err != nil {
+				r.logger.Error().Err(err).Msg("Failed to reload config, keeping the current one")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Reload loads and validates the configuration, then applies its reloadable settings.
+// An invalid configuration is rejected as a whole.
+func (r *Reloader) Reload() error {
+	loaded, err := Load()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := *r.current
+	var restart []string
+	eachField(&next, loaded, func(field reflect.StructField, running, loaded reflect.Value, key string) {
+		if reflect.DeepEqual(running.Interface(), loaded.Interface()) {
+			return
+		}
+		if field.Tag.Get("reload") == "true" {
+			running.Set(loaded)
+			return
+		}
+		restart = append(restart, key)
+	})
+	if len(restart) > 0 {
+		r.logger.Warn().Strs("settings", restart).Msg("Changed settings require a restart, keeping their running values")
+	}
+
+	var failed int
+	for _, fn := range r.handlers {
+		if err := fn(&next); err != nil {
+			r.logger.Error().Err(err).Msg("Failed to apply reloaded config")
+			failed++
+		}
+	}
+	r.current = &next
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d config handlers failed", failed, len(r.handlers))
+	}
+	r.logger.Info().Msg("Config reloaded")
+	return nil
+}
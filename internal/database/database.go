@@ -8,11 +8,50 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewPool(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+// Pools are the primary pool used for writes and the optional read replica pool
+type Pools struct {
+	Primary *pgxpool.Pool
+	// Replica is nil when no replica DSN is configured
+	Replica *pgxpool.Pool
+}
+
+func (p *Pools) Close() {
+	if p.Replica != nil {
+		p.Replica.Close()
+	}
+	p.Primary.Close()
+}
+
+// NewPool connects to the primary and, if configured, creates the read replica pool.
+// The replica is not pinged: an unreachable replica must not prevent startup, reads fall back to the primary.
+func NewPool(ctx context.Context, cfg config.DatabaseConfig) (*Pools, error) {
 	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
 
-	poolConfig, err := pgxpool.ParseConfig(dbURL)
+	pool, err := newPool(ctx, dbURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	pools := &Pools{Primary: pool}
+	if cfg.ReplicaDSN != "" {
+		pools.Replica, err = newPool(ctx, cfg.ReplicaDSN, cfg)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("replica: %w", err)
+		}
+	}
+
+	return pools, nil
+}
+
+func newPool(ctx context.Context, dsn string, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse database config: %w", err)
 	}
@@ -26,11 +65,5 @@ func NewPool(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
-
-	if err := pool.Ping(ctx); err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
 	return pool, nil
 }
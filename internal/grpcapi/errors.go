@@ -0,0 +1,89 @@
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"transaction-processor/internal/handler"
+	"transaction-processor/internal/model"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const errorDomain = "transaction-processor"
+
+// Error codes that have a more specific gRPC code than the one derived from their HTTP status
+var codeOverrides = map[string]codes.Code{
+	"INSUFFICIENT_BALANCE":  codes.FailedPrecondition,
+	"LOSS_LIMIT_EXCEEDED":   codes.FailedPrecondition,
+	"AMOUNT_OUT_OF_RANGE":   codes.OutOfRange,
+	"DUPLICATE_TRANSACTION": codes.AlreadyExists,
+	"TRANSACTION_MISMATCH":  codes.AlreadyExists,
+}
+
+var httpToGRPC = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.Aborted,
+	http.StatusLocked:              codes.FailedPrecondition,
+	http.StatusInternalServerError: codes.Internal,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+}
+
+// toStatus converts a service error to a gRPC status carrying the REST error code as ErrorInfo reason
+func toStatus(err error) error {
+	httpStatus, code := handler.ErrorStatus(err)
+
+	grpcCode, ok := codeOverrides[code]
+	if !ok {
+		grpcCode, ok = httpToGRPC[httpStatus]
+		if !ok {
+			grpcCode = codes.Unknown
+		}
+	}
+
+	message := err.Error()
+	if grpcCode == codes.Internal {
+		// Do not leak internal errors to clients
+		message = "internal server error"
+	}
+
+	var mismatch *model.TransactionMismatchError
+	if errors.As(err, &mismatch) {
+		return withReason(grpcCode, code, message, mismatchViolations(mismatch))
+	}
+
+	return withReason(grpcCode, code, message)
+}
+
+// mismatchViolations reports the fields of a TRANSACTION_MISMATCH as a BadRequest detail
+func mismatchViolations(mismatch *model.TransactionMismatchError) *errdetails.BadRequest {
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(mismatch.Mismatches))
+	for i, m := range mismatch.Mismatches {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       m.Field,
+			Description: fmt.Sprintf("stored %q, received %q", m.Stored, m.Received),
+		}
+	}
+	return &errdetails.BadRequest{FieldViolations: violations}
+}
+
+// invalidRequest returns an InvalidArgument status with the INVALID_REQUEST code used by the REST API
+func invalidRequest(message string) error {
+	return withReason(codes.InvalidArgument, "INVALID_REQUEST", message)
+}
+
+func withReason(grpcCode codes.Code, reason, message string, details ...protoadapt.MessageV1) error {
+	st := status.New(grpcCode, message)
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}}, details...)
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
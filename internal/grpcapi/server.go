@@ -0,0 +1,226 @@
+// Package grpcapi serves the transaction API over gRPC for internal game servers.
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/grpcapi/transactionpb"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+	"transaction-processor/internal/service"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	grpcServer *grpc.Server
+	health     *health.Server
+	logger     zerolog.Logger
+}
+
+// NewServer registers the transaction service together with health checking and reflection.
+// Calls to the transaction service need a bearer token in their authorization metadata when the verifier is set,
+// and act for the tenant of the token or of their x-api-key metadata, see service.Tenants.
+func NewServer(txService service.TransactionService, tenants *service.Tenants, verifier *auth.Verifier, logger zerolog.Logger) *Server {
+	callers := &callerResolver{tenants: tenants, verifier: verifier, logger: logger}
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLoggingInterceptor(logger), unaryCallerInterceptor(callers)),
+		grpc.ChainStreamInterceptor(streamLoggingInterceptor(logger), streamCallerInterceptor(callers)),
+	)
+
+	transactionpb.RegisterTransactionServiceServer(grpcServer, &transactionServer{
+		transactionService: txService,
+		logger:             logger,
+	})
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	healthServer.SetServingStatus(transactionpb.TransactionService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	reflection.Register(grpcServer)
+
+	return &Server{
+		grpcServer: grpcServer,
+		health:     healthServer,
+		logger:     logger,
+	}
+}
+
+// Serve accepts connections on the listener until Shutdown is called
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpcServer.Serve(lis)
+}
+
+// Shutdown reports NOT_SERVING to health checks and waits for in-flight calls,
+// closing remaining connections when the context expires
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.logger.Warn().Msg("gRPC graceful shutdown timed out, closing connections")
+		s.grpcServer.Stop()
+	}
+}
+
+func unaryLoggingInterceptor(logger zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		// Carry the caller's request ID into the audit trail like the X-Request-ID header does over HTTP
+		if ids := metadata.ValueFromIncomingContext(ctx, "x-request-id"); len(ids) > 0 {
+			ctx = repository.WithRequestID(ctx, ids[0])
+		}
+		resp, err := handler(ctx, req)
+		logCall(logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func streamLoggingInterceptor(logger zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// methodRoles are the roles allowed to call each transaction service method, like the matching REST routes
+var methodRoles = map[string][]auth.Role{
+	transactionpb.TransactionService_ProcessTransaction_FullMethodName: {auth.RoleProvider},
+	transactionpb.TransactionService_GetBalance_FullMethodName:         {auth.RoleProvider, auth.RoleSupport, auth.RoleOperator},
+	transactionpb.TransactionService_ListTransactions_FullMethodName:   {auth.RoleProvider, auth.RoleSupport, auth.RoleOperator},
+}
+
+// unaryCallerInterceptor authenticates calls to the transaction service and scopes them to the caller's tenant,
+// health checks and reflection need no credentials
+func unaryCallerInterceptor(callers *callerResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isTransactionMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := callers.resolve(ctx, info.FullMethod)
+		if err != nil {
+			callers.audit(ctx, info.FullMethod, err)
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		callers.audit(ctx, info.FullMethod, err)
+		return resp, err
+	}
+}
+
+func streamCallerInterceptor(callers *callerResolver) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isTransactionMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := callers.resolve(ss.Context(), info.FullMethod)
+		if err != nil {
+			callers.audit(ctx, info.FullMethod, err)
+			return err
+		}
+		err = handler(srv, &callerStream{ServerStream: ss, ctx: ctx})
+		callers.audit(ctx, info.FullMethod, err)
+		return err
+	}
+}
+
+// callerStream replaces the context of a stream with one carrying the caller
+type callerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *callerStream) Context() context.Context {
+	return s.ctx
+}
+
+// callerResolver reads the caller from the metadata like the REST middlewares read the Authorization and
+// X-API-Key headers
+type callerResolver struct {
+	tenants  *service.Tenants
+	verifier *auth.Verifier
+	logger   zerolog.Logger
+}
+
+// resolve returns the context carrying the caller and its tenant, or the context as far as the caller was
+// resolved with a status error
+func (r *callerResolver) resolve(ctx context.Context, method string) (context.Context, error) {
+	var principal *auth.Principal
+	if r.verifier.Enabled() {
+		token, ok := strings.CutPrefix(firstValue(ctx, "authorization"), "Bearer ")
+		if !ok {
+			return ctx, toStatus(model.ErrInvalidToken)
+		}
+		var err error
+		principal, err = r.verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			return ctx, toStatus(err)
+		}
+		if !principal.HasAnyRole(methodRoles[method]...) {
+			return ctx, toStatus(model.ErrForbidden)
+		}
+		ctx = auth.WithPrincipal(ctx, principal)
+	}
+
+	tenantID, err := r.tenants.Resolve(firstValue(ctx, "x-api-key"))
+	if principal != nil && principal.TenantID != "" {
+		tenantID, err = principal.TenantID, r.tenants.Check(principal.TenantID)
+	}
+	if err != nil {
+		return ctx, toStatus(err)
+	}
+	return repository.WithTenant(ctx, tenantID), nil
+}
+
+// audit logs who called which method and the outcome when tokens are enabled, like the REST API's AuditMiddleware
+func (r *callerResolver) audit(ctx context.Context, method string, err error) {
+	if !r.verifier.Enabled() {
+		return
+	}
+	event := r.logger.Info().
+		Str("request_id", repository.RequestID(ctx)).
+		Str("tenant_id", repository.TenantID(ctx)).
+		Str("method", method).
+		Str("code", status.Code(err).String())
+	if p := auth.FromContext(ctx); p != nil {
+		event = event.Str("subject", p.Subject).Strs("roles", p.RoleNames())
+	}
+	event.Msg("gRPC audit")
+}
+
+func firstValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func isTransactionMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+transactionpb.TransactionService_ServiceDesc.ServiceName+"/")
+}
+
+func logCall(logger zerolog.Logger, method string, start time.Time, err error) {
+	logger.Info().
+		Str("method", method).
+		Str("code", status.Code(err).String()).
+		Dur("latency", time.Since(start)).
+		Msg("gRPC Request")
+}
@@ -0,0 +1,267 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"testing"
+	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/auth/authtest"
+	"transaction-processor/internal/grpcapi/transactionpb"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+	"transaction-processor/internal/service"
+	"transaction-processor/mocks/service"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func startServer(t *testing.T, svc *mocks.TransactionService) *grpc.ClientConn {
+	return startSecuredServer(t, svc, nil, nil)
+}
+
+func startSecuredServer(t *testing.T, svc *mocks.TransactionService, tenants *service.Tenants, verifier *auth.Verifier) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := NewServer(svc, tenants, verifier, zerolog.Nop())
+	go srv.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	})
+	return conn
+}
+
+func errorReason(t *testing.T, err error) string {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestServer_ProcessTransaction_Success(t *testing.T) {
+	svc := mocks.NewTransactionService(t)
+	client := transactionpb.NewTransactionServiceClient(startServer(t, svc))
+
+	svc.On("ProcessTransaction", mock.Anything, mock.MatchedBy(func(req *model.TransactionRequest) bool {
+		return req.TransactionID == "550e8400-e29b-41d4-a716-446655440000" && req.ProviderID == "acme"
+	}), model.SourceGame, int64(1)).Return(&model.TransactionResponse{
+		Status:  "success",
+		Balance: "110.00",
+		Message: "Transaction processed successfully",
+	}, nil)
+
+	resp, err := client.ProcessTransaction(context.Background(), &transactionpb.ProcessTransactionRequest{
+		UserId:        1,
+		SourceType:    "game",
+		ProviderId:    "acme",
+		TransactionId: "550e8400-e29b-41d4-a716-446655440000",
+		State:         "win",
+		Amount:        "10.00",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "success", resp.GetStatus())
+	assert.Equal(t, "110.00", resp.GetBalance())
+}
+
+func TestServer_ProcessTransaction_ErrorCodes(t *testing.T) {
+	svc := mocks.NewTransactionService(t)
+	client := transactionpb.NewTransactionServiceClient(startServer(t, svc))
+
+	svc.On("ProcessTransaction", mock.Anything, mock.Anything, model.SourceGame, int64(1)).Return(nil, model.ErrInsufficientBalance)
+
+	_, err := client.ProcessTransaction(context.Background(), &transactionpb.ProcessTransactionRequest{
+		UserId:        1,
+		SourceType:    "game",
+		TransactionId: "550e8400-e29b-41d4-a716-446655440000",
+		State:         "lost",
+		Amount:        "1000.00",
+	})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, "INSUFFICIENT_BALANCE", errorReason(t, err))
+}
+
+func TestServer_ProcessTransaction_Mismatch(t *testing.T) {
+	svc := mocks.NewTransactionService(t)
+	client := transactionpb.NewTransactionServiceClient(startServer(t, svc))
+
+	svc.On("ProcessTransaction", mock.Anything, mock.Anything, model.SourceGame, int64(1)).Return(nil, &model.TransactionMismatchError{
+		TransactionID: "550e8400-e29b-41d4-a716-446655440000",
+		Mismatches:    []model.FieldMismatch{{Field: "amount", Stored: "10", Received: "20"}},
+	})
+
+	_, err := client.ProcessTransaction(context.Background(), &transactionpb.ProcessTransactionRequest{
+		UserId:        1,
+		SourceType:    "game",
+		TransactionId: "550e8400-e29b-41d4-a716-446655440000",
+		State:         "win",
+		Amount:        "20.00",
+	})
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Equal(t, "TRANSACTION_MISMATCH", errorReason(t, err))
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			violations = br.GetFieldViolations()
+		}
+	}
+	require.Len(t, violations, 1)
+	assert.Equal(t, "amount", violations[0].GetField())
+}
+
+func TestServer_ProcessTransaction_InvalidRequest(t *testing.T) {
+	svc := mocks.NewTransactionService(t)
+	client := transactionpb.NewTransactionServiceClient(startServer(t, svc))
+
+	_, err := client.ProcessTransaction(context.Background(), &transactionpb.ProcessTransactionRequest{
+		UserId:        1,
+		SourceType:    "game",
+		TransactionId: "not-a-uuid",
+		State:         "win",
+		Amount:        "10.00",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "INVALID_REQUEST", errorReason(t, err))
+
+	_, err = client.ProcessTransaction(context.Background(), &transactionpb.ProcessTransactionRequest{
+		UserId:     1,
+		SourceType: "casino",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "INVALID_SOURCE_TYPE", errorReason(t, err))
+}
+
+func TestServer_GetBalance_NotFound(t *testing.T) {
+	svc := mocks.NewTransactionService(t)
+	client := transactionpb.NewTransactionServiceClient(startServer(t, svc))
+
+	svc.On("GetBalance", mock.Anything, int64(42)).Return(nil, model.ErrUserNotFound)
+
+	_, err := client.GetBalance(context.Background(), &transactionpb.GetBalanceRequest{UserId: 42})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "USER_NOT_FOUND", errorReason(t, err))
+}
+
+func TestServer_Tenant(t *testing.T) {
+	sum := sha256.Sum256([]byte("key-a"))
+	tenants, err := service.ParseTenants([]byte("tenants:\n  - id: brand-a\n    api_keys_sha256: [" + hex.EncodeToString(sum[:]) + "]\n"))
+	require.NoError(t, err)
+
+	svc := mocks.NewTransactionService(t)
+	conn := startSecuredServer(t, svc, tenants, nil)
+	client := transactionpb.NewTransactionServiceClient(conn)
+
+	inTenant := mock.MatchedBy(func(ctx context.Context) bool { return repository.TenantID(ctx) == "brand-a" })
+	svc.On("GetBalance", inTenant, int64(1)).Return(&model.BalanceResponse{UserID: 1, Balance: "10.00"}, nil)
+
+	_, err = client.GetBalance(context.Background(), &transactionpb.GetBalanceRequest{UserId: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "UNAUTHENTICATED", errorReason(t, err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "key-a")
+	resp, err := client.GetBalance(ctx, &transactionpb.GetBalanceRequest{UserId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "10.00", resp.GetBalance())
+
+	// Health checks need no API key
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestServer_Auth(t *testing.T) {
+	issuer := authtest.NewIssuer(t)
+	svc := mocks.NewTransactionService(t)
+	conn := startSecuredServer(t, svc, nil, issuer.Verifier())
+	client := transactionpb.NewTransactionServiceClient(conn)
+
+	asCaller := mock.MatchedBy(func(ctx context.Context) bool {
+		p := auth.FromContext(ctx)
+		return p != nil && p.Subject == "bob"
+	})
+	svc.On("GetBalance", asCaller, int64(1)).Return(&model.BalanceResponse{UserID: 1, Balance: "10.00"}, nil)
+
+	_, err := client.GetBalance(context.Background(), &transactionpb.GetBalanceRequest{UserId: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "INVALID_TOKEN", errorReason(t, err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+issuer.Token("bob", auth.RoleSupport))
+	resp, err := client.GetBalance(ctx, &transactionpb.GetBalanceRequest{UserId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "10.00", resp.GetBalance())
+
+	// Support may not post transactions
+	_, err = client.ProcessTransaction(ctx, &transactionpb.ProcessTransactionRequest{UserId: 1})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, "FORBIDDEN", errorReason(t, err))
+
+	// Health checks need no token
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestServer_ListTransactions_Streams(t *testing.T) {
+	svc := mocks.NewTransactionService(t)
+	client := transactionpb.NewTransactionServiceClient(startServer(t, svc))
+
+	svc.On("GetTransactionsByUser", mock.Anything, int64(1), 10, 0).Return([]*model.Transaction{
+		{ID: 2, TransactionID: "b", UserID: 1, State: model.StateLost, Amount: decimal.NewFromInt(5), Status: model.StatusProcessed},
+		{ID: 1, TransactionID: "a", UserID: 1, State: model.StateWin, Amount: decimal.NewFromInt(10), Status: model.StatusProcessed},
+	}, nil)
+
+	stream, err := client.ListTransactions(context.Background(), &transactionpb.ListTransactionsRequest{UserId: 1})
+	require.NoError(t, err)
+
+	var ids []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, resp.GetTransaction().GetTransactionId())
+	}
+	assert.Equal(t, []string{"b", "a"}, ids)
+}
+
+func TestServer_HealthCheck(t *testing.T) {
+	conn := startServer(t, mocks.NewTransactionService(t))
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: transactionpb.TransactionService_ServiceDesc.ServiceName,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
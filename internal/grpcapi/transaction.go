@@ -0,0 +1,111 @@
+package grpcapi
+
+import (
+	"context"
+	"transaction-processor/internal/grpcapi/transactionpb"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type transactionServer struct {
+	transactionpb.UnimplementedTransactionServiceServer
+	transactionService service.TransactionService
+	logger             zerolog.Logger
+}
+
+func (s *transactionServer) ProcessTransaction(ctx context.Context, req *transactionpb.ProcessTransactionRequest) (*transactionpb.ProcessTransactionResponse, error) {
+	sourceType, err := model.ParseSourceType(req.GetSourceType())
+	if err != nil {
+		return nil, s.handleError(err)
+	}
+
+	if req.GetUserId() <= 0 {
+		return nil, invalidRequest("user_id must be a positive integer")
+	}
+	if _, err := uuid.Parse(req.GetTransactionId()); err != nil {
+		return nil, invalidRequest("transaction_id must be a UUID")
+	}
+	if req.GetAmount() == "" {
+		return nil, invalidRequest("amount is required")
+	}
+
+	resp, err := s.transactionService.ProcessTransaction(ctx, &model.TransactionRequest{
+		State:         req.GetState(),
+		Amount:        req.GetAmount(),
+		TransactionID: req.GetTransactionId(),
+		ProviderID:    req.GetProviderId(),
+	}, sourceType, req.GetUserId())
+	if err != nil {
+		return nil, s.handleError(err)
+	}
+
+	return &transactionpb.ProcessTransactionResponse{
+		Status:  resp.Status,
+		Balance: resp.Balance,
+		Message: resp.Message,
+	}, nil
+}
+
+func (s *transactionServer) GetBalance(ctx context.Context, req *transactionpb.GetBalanceRequest) (*transactionpb.GetBalanceResponse, error) {
+	resp, err := s.transactionService.GetBalance(ctx, req.GetUserId())
+	if err != nil {
+		return nil, s.handleError(err)
+	}
+
+	return &transactionpb.GetBalanceResponse{
+		UserId:  resp.UserID,
+		Balance: resp.Balance,
+	}, nil
+}
+
+func (s *transactionServer) ListTransactions(req *transactionpb.ListTransactionsRequest, stream transactionpb.TransactionService_ListTransactionsServer) error {
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = 10
+	}
+
+	transactions, err := s.transactionService.GetTransactionsByUser(stream.Context(), req.GetUserId(), limit, int(req.GetOffset()))
+	if err != nil {
+		return s.handleError(err)
+	}
+
+	for _, t := range transactions {
+		if err := stream.Send(&transactionpb.ListTransactionsResponse{Transaction: toProtoTransaction(t)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleError converts a service error to a gRPC status, logging unexpected errors
+func (s *transactionServer) handleError(err error) error {
+	st := toStatus(err)
+	if status.Code(st) == codes.Internal {
+		s.logger.Error().Err(err).Msg("internal server error")
+	}
+	return st
+}
+
+func toProtoTransaction(t *model.Transaction) *transactionpb.Transaction {
+	pt := &transactionpb.Transaction{
+		Id:            t.ID,
+		TransactionId: t.TransactionID,
+		UserId:        t.UserID,
+		SourceType:    t.SourceType.String(),
+		ProviderId:    t.ProviderID,
+		State:         t.State.String(),
+		Amount:        t.Amount.StringFixed(2),
+		Status:        string(t.Status),
+		CreatedAt:     timestamppb.New(t.CreatedAt),
+	}
+	if t.CancelledAt != nil {
+		pt.CancelledAt = timestamppb.New(*t.CancelledAt)
+	}
+	return pt
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// CreateAdjustment
+// @Summary Request a balance adjustment
+// @Description Creates a pending credit (positive amount) or debit (negative amount), applied once a second operator approves it
+// @Tags adjustments
+// @Accept json
+// @Produce json
+// @Param X-Operator-ID header string true "Requesting operator"
+// @Param adjustment body model.AdjustmentRequest true "Adjustment"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 201 {object} model.BalanceAdjustment
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /admin/adjustments [post]
+func (h *Handler) CreateAdjustment(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	var req model.AdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid amount",
+			Code:  "INVALID_AMOUNT",
+		})
+		return
+	}
+
+	adj, err := h.adjustmentService.CreateAdjustment(c.Request.Context(), req.UserID, amount, req.Reason, operator)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, adj)
+}
+
+// ListAdjustments
+// @Summary List balance adjustments
+// @Description Returns adjustments with the given status, oldest first
+// @Tags adjustments
+// @Produce json
+// @Param status query string false "Status (pending, approved, rejected)" default(pending)
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} model.AdjustmentListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid status"
+// @Router /admin/adjustments [get]
+func (h *Handler) ListAdjustments(c *gin.Context) {
+	status, err := model.ParseAdjustmentStatus(c.DefaultQuery("status", string(model.AdjustmentPending)))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	adjustments, err := h.adjustmentService.ListAdjustments(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.AdjustmentListResponse{
+		Adjustments: adjustments,
+		Total:       len(adjustments),
+		Limit:       limit,
+		Offset:      offset,
+	})
+}
+
+// ApproveAdjustment
+// @Summary Approve a balance adjustment
+// @Description Applies a pending adjustment to the user's balance, must be called by a different operator than the requester
+// @Tags adjustments
+// @Accept json
+// @Produce json
+// @Param X-Operator-ID header string true "Approving operator"
+// @Param id path int true "Adjustment ID"
+// @Param decision body model.ReviewDecisionRequest false "Decision note"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.BalanceAdjustment
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "Self approval"
+// @Failure 404 {object} model.ErrorResponse "Adjustment not found"
+// @Failure 409 {object} model.ErrorResponse "Not pending"
+// @Router /admin/adjustments/{id}/approve [post]
+func (h *Handler) ApproveAdjustment(c *gin.Context) {
+	h.adjustmentDecision(c, true)
+}
+
+// RejectAdjustment
+// @Summary Reject a balance adjustment
+// @Description Declines a pending adjustment, the balance is not changed
+// @Tags adjustments
+// @Accept json
+// @Produce json
+// @Param X-Operator-ID header string true "Rejecting operator"
+// @Param id path int true "Adjustment ID"
+// @Param decision body model.ReviewDecisionRequest false "Decision note"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.BalanceAdjustment
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "Self approval"
+// @Failure 404 {object} model.ErrorResponse "Adjustment not found"
+// @Failure 409 {object} model.ErrorResponse "Not pending"
+// @Router /admin/adjustments/{id}/reject [post]
+func (h *Handler) RejectAdjustment(c *gin.Context) {
+	h.adjustmentDecision(c, false)
+}
+
+func (h *Handler) adjustmentDecision(c *gin.Context, approve bool) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrAdjustmentNotFound)
+		return
+	}
+
+	var req model.ReviewDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{
+				Error: "Invalid request body",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+
+	var adj *model.BalanceAdjustment
+	if approve {
+		adj, err = h.adjustmentService.ApproveAdjustment(c.Request.Context(), id, operator, req.Note)
+	} else {
+		adj, err = h.adjustmentService.RejectAdjustment(c.Request.Context(), id, operator, req.Note)
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adj)
+}
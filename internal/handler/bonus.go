@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// GetBonusWallet
+// @Summary Get user bonus balance
+// @Description Returns the bonus balance of a user, the amount left to wager before it is released and its expiry
+// @Tags bonus
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} model.BonusWallet
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /users/{id}/bonus [get]
+func (h *Handler) GetBonusWallet(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+
+	wallet, err := h.bonusService.GetBonusWallet(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// GrantBonus
+// @Summary Grant bonus funds
+// @Description Credits bonus funds that must be wagered before they become real money. The grant adds to the active bonus and restarts its expiry
+// @Tags bonus
+// @Accept json
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param id path int true "User ID"
+// @Param grant body model.BonusGrantRequest true "Grant"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 201 {object} model.BonusWallet
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Failure 423 {object} model.ErrorResponse "Mutations paused"
+// @Router /admin/users/{id}/bonus [post]
+func (h *Handler) GrantBonus(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+
+	var req model.BonusGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid amount",
+			Code:  "INVALID_AMOUNT",
+		})
+		return
+	}
+
+	var wagering *decimal.Decimal
+	if req.Wagering != "" {
+		value, err := decimal.NewFromString(req.Wagering)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{
+				Error: "Invalid wagering",
+				Code:  "INVALID_AMOUNT",
+			})
+			return
+		}
+		wagering = &value
+	}
+
+	wallet, err := h.bonusService.GrantBonus(c.Request.Context(), userID, amount, wagering, req.Note, operator)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, wallet)
+}
+
+// ListBonusEntries
+// @Summary List bonus grants and expirations
+// @Description Returns a user's bonus grants and expirations, newest first
+// @Tags bonus
+// @Produce json
+// @Param id path int true "User ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} model.BonusEntryListResponse
+// @Router /admin/users/{id}/bonus/entries [get]
+func (h *Handler) ListBonusEntries(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	entries, err := h.bonusService.ListBonusEntries(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.BonusEntryListResponse{
+		Entries: entries,
+		Total:   len(entries),
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
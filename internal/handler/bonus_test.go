@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_GrantBonus_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBonus := mocks.NewBonusService(t)
+	h := NewHandler(Deps{
+		Bonuses: mockBonus,
+		Logger:  zerolog.Nop(),
+	})
+
+	mockBonus.On("GrantBonus", mock.Anything, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(20))
+	}), mock.MatchedBy(func(d *decimal.Decimal) bool {
+		return d != nil && d.Equal(decimal.NewFromInt(100))
+	}), "welcome", "alice").Return(&model.BonusWallet{UserID: 1, Balance: decimal.NewFromInt(20), Wagering: decimal.NewFromInt(100)}, nil)
+
+	body, _ := json.Marshal(model.BonusGrantRequest{Amount: "20", Wagering: "100", Note: "welcome"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/users/1/bonus", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Operator-ID", "alice")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp model.BonusWallet
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.True(t, decimal.NewFromInt(20).Equal(resp.Balance))
+}
+
+func TestHandler_GrantBonus_InvalidWagering(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockBonus := mocks.NewBonusService(t)
+	h := NewHandler(Deps{
+		Bonuses: mockBonus,
+		Logger:  zerolog.Nop(),
+	})
+
+	body, _ := json.Marshal(model.BonusGrantRequest{Amount: "20", Wagering: "lots"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/users/1/bonus", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Operator-ID", "alice")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockBonus.AssertNotCalled(t, "GrantBonus")
+}
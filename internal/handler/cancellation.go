@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancelTransaction
+// @Summary Cancel a processed transaction
+// @Description Reverses a processed transaction's balance change and records the operator, reason and note in its audit trail
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param transaction_id path string true "Transaction ID"
+// @Param cancel body model.CancelRequest true "Cancellation reason"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.Transaction
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "Transaction not found"
+// @Failure 409 {object} model.ErrorResponse "Not cancellable"
+// @Router /admin/transactions/{transaction_id}/cancel [post]
+func (h *Handler) CancelTransaction(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	var req model.CancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	reason, err := model.ParseCancelReason(req.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	trans, err := h.cancelService.CancelTransaction(c.Request.Context(), c.Param("transaction_id"), operator, reason, req.Note)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trans)
+}
+
+// GetTransactionEvents
+// @Summary Get the audit trail of a transaction
+// @Description Returns every status change of a transaction with its actor, reason and balances, oldest first
+// @Tags transactions
+// @Produce json
+// @Param transaction_id path string true "Transaction ID"
+// @Success 200 {array} model.TransactionEvent
+// @Failure 404 {object} model.ErrorResponse "Transaction not found"
+// @Router /admin/transactions/{transaction_id}/events [get]
+func (h *Handler) GetTransactionEvents(c *gin.Context) {
+	events, err := h.transactionService.GetTransactionEvents(c.Request.Context(), c.Param("transaction_id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// ListCancellationShortfalls
+// @Summary List cancellations the balance could not cover
+// @Description Returns transactions whose automatic cancellation failed (cancellation_failed) or was made with a shortfall (partial or debt), newest first
+// @Tags transactions
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} model.TransactionListResponse
+// @Router /admin/cancellations/shortfalls [get]
+func (h *Handler) ListCancellationShortfalls(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	transactions, err := h.cancelService.ListCancellationShortfalls(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.TransactionListResponse{
+		Transactions: transactions,
+		Total:        len(transactions),
+		Limit:        limit,
+		Offset:       offset,
+	})
+}
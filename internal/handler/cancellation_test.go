@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_CancelTransaction_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCancel := mocks.NewCancellationService(t)
+	h := NewHandler(Deps{
+		Cancellations: mockCancel,
+		Logger:        zerolog.Nop(),
+	})
+
+	mockCancel.On("CancelTransaction", mock.Anything, "tx-1", "alice", model.CancelReasonFraud, "chargeback ring").Return(&model.Transaction{
+		TransactionID: "tx-1",
+		Status:        model.StatusCancelled,
+		CancelReason:  string(model.CancelReasonFraud),
+	}, nil)
+
+	body, _ := json.Marshal(model.CancelRequest{Reason: "fraud", Note: "chargeback ring"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/transactions/tx-1/cancel", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Operator-ID", "alice")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp model.Transaction
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, model.StatusCancelled, resp.Status)
+	assert.Equal(t, "fraud", resp.CancelReason)
+}
+
+func TestHandler_CancelTransaction_InvalidReason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCancel := mocks.NewCancellationService(t)
+	h := NewHandler(Deps{
+		Cancellations: mockCancel,
+		Logger:        zerolog.Nop(),
+	})
+
+	body, _ := json.Marshal(model.CancelRequest{Reason: "bored"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/transactions/tx-1/cancel", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Operator-ID", "alice")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp model.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "INVALID_CANCEL_REASON", resp.Code)
+	mockCancel.AssertNotCalled(t, "CancelTransaction")
+}
+
+func TestHandler_CancelTransaction_NotCancellable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCancel := mocks.NewCancellationService(t)
+	h := NewHandler(Deps{
+		Cancellations: mockCancel,
+		Logger:        zerolog.Nop(),
+	})
+
+	mockCancel.On("CancelTransaction", mock.Anything, "tx-1", "alice", model.CancelReasonOther, "").Return(nil, model.ErrNotCancellable)
+
+	body, _ := json.Marshal(model.CancelRequest{Reason: "other"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/transactions/tx-1/cancel", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Operator-ID", "alice")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp model.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "NOT_CANCELLABLE", resp.Code)
+}
+
+func TestHandler_GetTransactionEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := mocks.NewTransactionService(t)
+	h := NewHandler(Deps{
+		Transactions: mockSvc,
+		Logger:       zerolog.Nop(),
+	})
+
+	mockSvc.On("GetTransactionEvents", mock.Anything, "tx-1").Return([]*model.TransactionEvent{
+		{TransactionID: "tx-1", ToStatus: model.StatusProcessed, ActorType: model.ActorProvider, Actor: "game", Reason: model.EventReasonProcessed},
+		{TransactionID: "tx-1", FromStatus: model.StatusProcessed, ToStatus: model.StatusCancelled, ActorType: model.ActorOperator, Actor: "alice", Reason: "fraud"},
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/transactions/tx-1/events", nil)
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var events []model.TransactionEvent
+	json.Unmarshal(w.Body.Bytes(), &events)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, model.StatusCancelled, events[1].ToStatus)
+		assert.Equal(t, "alice", events[1].Actor)
+	}
+}
+
+func TestHandler_ListCancellationShortfalls(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockCancel := mocks.NewCancellationService(t)
+	h := NewHandler(Deps{
+		Cancellations: mockCancel,
+		Logger:        zerolog.Nop(),
+	})
+
+	mockCancel.On("ListCancellationShortfalls", mock.Anything, 20, 0).Return([]*model.Transaction{
+		{TransactionID: "tx-1", Status: model.StatusCancellationFailed, CancelPolicy: model.ShortfallSkip},
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/cancellations/shortfalls?limit=20", nil)
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp model.TransactionListResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Transactions, 1) {
+		assert.Equal(t, model.StatusCancellationFailed, resp.Transactions[0].Status)
+	}
+}
@@ -3,10 +3,12 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"transaction-processor/internal/auth"
 	"transaction-processor/internal/model"
 	"transaction-processor/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -14,13 +16,65 @@ import (
 
 type Handler struct {
 	transactionService service.TransactionService
+	limitService       service.LimitService
+	reviewService      service.ReviewService
+	integrityService   service.IntegrityService
+	adjustmentService  service.AdjustmentService
+	bonusService       service.BonusService
+	paymentService     service.PaymentService
+	balanceStream      service.BalanceStreamService
+	idempotencyService service.IdempotencyService
+	partitionService   service.PartitionService
+	cancelService      service.CancellationService
+	cancellationWorker service.CancellationWorkerControl
+	leadership         service.Leadership
+	health             service.HealthService
+	tenants            *service.Tenants
+	auth               *auth.Verifier
 	logger             zerolog.Logger
 }
 
-func NewHandler(txService service.TransactionService, logger zerolog.Logger) *Handler {
+// Deps are the services the handler serves, those a handler does not need may be left nil
+type Deps struct {
+	Transactions       service.TransactionService
+	Limits             service.LimitService
+	Reviews            service.ReviewService
+	Integrity          service.IntegrityService
+	Adjustments        service.AdjustmentService
+	Bonuses            service.BonusService
+	Payments           service.PaymentService
+	BalanceStream      service.BalanceStreamService
+	Idempotency        service.IdempotencyService
+	Partitions         service.PartitionService
+	Cancellations      service.CancellationService
+	CancellationWorker service.CancellationWorkerControl
+	Leadership         service.Leadership
+	Health             service.HealthService
+	Tenants            *service.Tenants
+	// Verifier checks bearer tokens, nil disables authentication
+	Verifier *auth.Verifier
+	Logger   zerolog.Logger
+}
+
+func NewHandler(deps Deps) *Handler {
 	return &Handler{
-		transactionService: txService,
-		logger:             logger,
+		transactionService: deps.Transactions,
+		limitService:       deps.Limits,
+		reviewService:      deps.Reviews,
+		integrityService:   deps.Integrity,
+		adjustmentService:  deps.Adjustments,
+		bonusService:       deps.Bonuses,
+		paymentService:     deps.Payments,
+		balanceStream:      deps.BalanceStream,
+		idempotencyService: deps.Idempotency,
+		partitionService:   deps.Partitions,
+		cancelService:      deps.Cancellations,
+		cancellationWorker: deps.CancellationWorker,
+		leadership:         deps.Leadership,
+		health:             deps.Health,
+		tenants:            deps.Tenants,
+		auth:               deps.Verifier,
+		logger:             deps.Logger,
 	}
 }
 
@@ -32,58 +86,183 @@ func (h *Handler) SetupRoutes() *gin.Engine {
 		RequestIDMiddleware(),
 		LoggingMiddleware(),
 		gin.Recovery(),
+		ReadYourWritesMiddleware(),
 	)
 
-	// Swagger and health checks
+	// Swagger, metrics and health checks
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/health", h.Health)
+	router.GET("/livez", h.Health)
+	router.GET("/readyz", h.Ready)
 
 	// API routes
 	v1 := router.Group("/api/v1")
 
-	transactions := v1.Group("/transactions")
-	transactions.POST("", h.ProcessTransaction)
-	transactions.GET("/user/:id", h.GetTransactionsByUser)
+	// Signed by the payment provider, which retries until the outcome is acknowledged. It serves every tenant.
+	v1.POST("/payments/callback", h.PaymentCallback)
 
-	users := v1.Group("/users")
-	users.GET("/:id/balance", h.GetBalance)
+	// Every other route requires a bearer token with one of the route's roles when tokens are enabled,
+	// and acts for the caller's tenant
+	api := v1.Group("", h.AuditMiddleware(), h.AuthMiddleware(), h.TenantMiddleware())
+	reader := h.RequireRole(auth.RoleProvider, auth.RoleSupport, auth.RoleOperator)
+	playerAction := h.RequireRole(auth.RoleProvider, auth.RoleOperator)
+	support := h.RequireRole(auth.RoleSupport, auth.RoleOperator)
+	operator := h.RequireRole(auth.RoleOperator)
+	admin := h.RequireRole(auth.RoleAdmin)
+	// Replays come after the role check, so that a stored response is only returned to callers allowed to make the request
+	idempotent := h.IdempotencyMiddleware()
 
-	return router
-}
+	transactions := api.Group("/transactions")
+	transactions.POST("", h.RequireRole(auth.RoleProvider), h.ProcessTransaction)
+	transactions.GET("/user/:id", reader, h.GetTransactionsByUser)
 
-func (h *Handler) handleError(c *gin.Context, err error) {
-	status := http.StatusInternalServerError
-	code := "INTERNAL_SERVER_ERROR"
+	users := api.Group("/users")
+	users.GET("/:id/balance", reader, h.GetBalance)
+	users.GET("/:id/balance/stream", reader, h.StreamBalance)
+	users.GET("/:id/limits", reader, h.GetLossLimits)
+	users.PUT("/:id/limits", playerAction, idempotent, h.SetLossLimit)
+	users.GET("/:id/bonus", reader, h.GetBonusWallet)
+	users.POST("/:id/deposits", playerAction, idempotent, h.RequestDeposit)
+	users.POST("/:id/withdrawals", playerAction, idempotent, h.RequestWithdrawal)
+	users.GET("/:id/payments", reader, h.ListUserPayments)
+
+	// Back-office routes
+	backOffice := api.Group("/admin")
 
-	resp := model.ErrorResponse{Error: err.Error()}
+	reviews := backOffice.Group("/reviews")
+	reviews.GET("", support, h.ListPendingReviews)
+	reviews.POST("/:transaction_id/approve", operator, idempotent, h.ApproveTransaction)
+	reviews.POST("/:transaction_id/reject", operator, idempotent, h.RejectTransaction)
+
+	adjustments := backOffice.Group("/adjustments")
+	adjustments.GET("", support, h.ListAdjustments)
+	adjustments.POST("", operator, idempotent, h.CreateAdjustment)
+	adjustments.POST("/:id/approve", operator, idempotent, h.ApproveAdjustment)
+	adjustments.POST("/:id/reject", operator, idempotent, h.RejectAdjustment)
+
+	payments := backOffice.Group("/payments")
+	payments.GET("", support, h.ListPayments)
+	payments.POST("/:id/approve", operator, idempotent, h.ApprovePayment)
+	payments.POST("/:id/reject", operator, idempotent, h.RejectPayment)
+
+	backOffice.GET("/integrity", support, h.CheckIntegrity)
+	backOffice.POST("/users/:id/resume", operator, idempotent, h.ResumeUser)
+	backOffice.PUT("/users/:id/provider", operator, idempotent, h.SetUserProvider)
+	backOffice.POST("/users/:id/bonus", operator, idempotent, h.GrantBonus)
+	backOffice.GET("/users/:id/bonus/entries", support, h.ListBonusEntries)
+	backOffice.GET("/partitions", admin, h.PlatformOnlyMiddleware(), h.ListPartitions)
+	backOffice.GET("/transactions/:transaction_id/events", support, h.GetTransactionEvents)
+	backOffice.POST("/transactions/:transaction_id/cancel", operator, idempotent, h.CancelTransaction)
+	backOffice.GET("/cancellations/shortfalls", support, h.ListCancellationShortfalls)
+
+	// The worker serves every tenant, so only the platform controls it
+	cancellationWorker := backOffice.Group("/workers/cancellation", h.PlatformOnlyMiddleware())
+	cancellationWorker.GET("", support, h.GetCancellationWorker)
+	cancellationWorker.POST("/pause", admin, idempotent, h.PauseCancellationWorker)
+	cancellationWorker.POST("/resume", admin, idempotent, h.ResumeCancellationWorker)
+	cancellationWorker.POST("/run", admin, idempotent, h.RunCancellationWorker)
+	cancellationWorker.PUT("/interval", admin, idempotent, h.SetCancellationWorkerInterval)
+	cancellationWorker.DELETE("/interval", admin, idempotent, h.ResetCancellationWorkerInterval)
+
+	return router
+}
 
+// ErrorStatus maps a service error to the HTTP status and error code returned by the API.
+// The gRPC server derives its status codes from it so both transports report the same codes.
+func ErrorStatus(err error) (int, string) {
 	switch {
 	case errors.Is(err, model.ErrInsufficientBalance):
-		status = http.StatusBadRequest
-		code = "INSUFFICIENT_BALANCE"
+		return http.StatusBadRequest, "INSUFFICIENT_BALANCE"
 	case errors.Is(err, model.ErrInvalidAmount):
-		status = http.StatusBadRequest
-		code = "INVALID_AMOUNT"
+		return http.StatusBadRequest, "INVALID_AMOUNT"
 	case errors.Is(err, model.ErrInvalidState):
-		status = http.StatusBadRequest
-		code = "INVALID_STATE"
+		return http.StatusBadRequest, "INVALID_STATE"
 	case errors.Is(err, model.ErrInvalidSourceType):
-		status = http.StatusBadRequest
-		code = "INVALID_SOURCE_TYPE"
+		return http.StatusBadRequest, "INVALID_SOURCE_TYPE"
+	case errors.Is(err, model.ErrInvalidLimitPeriod):
+		return http.StatusBadRequest, "INVALID_LIMIT_PERIOD"
+	case errors.Is(err, model.ErrLossLimitExceeded):
+		return http.StatusBadRequest, "LOSS_LIMIT_EXCEEDED"
+	case errors.Is(err, model.ErrAmountOutOfRange):
+		return http.StatusBadRequest, "AMOUNT_OUT_OF_RANGE"
+	case errors.Is(err, model.ErrInvalidCancelReason):
+		return http.StatusBadRequest, "INVALID_CANCEL_REASON"
+	case errors.Is(err, model.ErrNotCancellable):
+		return http.StatusConflict, "NOT_CANCELLABLE"
+	case errors.Is(err, model.ErrNotPendingReview):
+		return http.StatusConflict, "NOT_PENDING_REVIEW"
+	case errors.Is(err, model.ErrTransactionBlocked):
+		return http.StatusForbidden, "TRANSACTION_BLOCKED"
+	case errors.Is(err, model.ErrMutationsPaused):
+		return http.StatusLocked, "MUTATIONS_PAUSED"
+	case errors.Is(err, model.ErrInvalidAdjustmentStatus):
+		return http.StatusBadRequest, "INVALID_ADJUSTMENT_STATUS"
+	case errors.Is(err, model.ErrAdjustmentNotPending):
+		return http.StatusConflict, "ADJUSTMENT_NOT_PENDING"
+	case errors.Is(err, model.ErrSelfApproval):
+		return http.StatusForbidden, "SELF_APPROVAL"
+	case errors.Is(err, model.ErrAdjustmentNotFound):
+		return http.StatusNotFound, "ADJUSTMENT_NOT_FOUND"
+	case errors.Is(err, model.ErrInvalidPaymentStatus):
+		return http.StatusBadRequest, "INVALID_PAYMENT_STATUS"
+	case errors.Is(err, model.ErrPaymentStatusConflict):
+		return http.StatusConflict, "PAYMENT_STATUS_CONFLICT"
+	case errors.Is(err, model.ErrPaymentNotFound):
+		return http.StatusNotFound, "PAYMENT_NOT_FOUND"
+	case errors.Is(err, model.ErrInvalidSignature):
+		return http.StatusUnauthorized, "INVALID_SIGNATURE"
+	case errors.Is(err, model.ErrUnauthenticated):
+		return http.StatusUnauthorized, "UNAUTHENTICATED"
+	case errors.Is(err, model.ErrSourceTypeNotAllowed):
+		return http.StatusForbidden, "SOURCE_TYPE_NOT_ALLOWED"
+	case errors.Is(err, model.ErrProviderNotAllowed):
+		return http.StatusForbidden, "PROVIDER_NOT_ALLOWED"
+	case errors.Is(err, model.ErrPlatformOnly):
+		return http.StatusForbidden, "PLATFORM_ONLY"
+	case errors.Is(err, model.ErrInvalidToken):
+		return http.StatusUnauthorized, "INVALID_TOKEN"
+	case errors.Is(err, model.ErrForbidden):
+		return http.StatusForbidden, "FORBIDDEN"
+	case errors.Is(err, model.ErrTransactionMismatch):
+		return http.StatusConflict, "TRANSACTION_MISMATCH"
+	case errors.Is(err, model.ErrWorkerBusy):
+		return http.StatusConflict, "WORKER_BUSY"
+	case errors.Is(err, model.ErrWorkerPaused):
+		return http.StatusConflict, "WORKER_PAUSED"
+	case errors.Is(err, model.ErrInvalidInterval):
+		return http.StatusBadRequest, "INVALID_INTERVAL"
+	case errors.Is(err, model.ErrIdempotencyMismatch):
+		return http.StatusConflict, "IDEMPOTENCY_MISMATCH"
+	case errors.Is(err, model.ErrIdempotencyInProgress):
+		return http.StatusConflict, "IDEMPOTENCY_IN_PROGRESS"
+	case errors.Is(err, model.ErrTransactionArchived):
+		return http.StatusConflict, "TRANSACTION_ARCHIVED"
+	case errors.Is(err, model.ErrArchiveUnavailable):
+		return http.StatusServiceUnavailable, "ARCHIVE_UNAVAILABLE"
 	case errors.Is(err, model.ErrUserNotFound):
-		status = http.StatusNotFound
-		code = "USER_NOT_FOUND"
+		return http.StatusNotFound, "USER_NOT_FOUND"
 	case errors.Is(err, model.ErrTransactionNotFound):
-		status = http.StatusNotFound
-		code = "TRANSACTION_NOT_FOUND"
+		return http.StatusNotFound, "TRANSACTION_NOT_FOUND"
 	case errors.Is(err, model.ErrDuplicateTransaction):
-		status = http.StatusConflict
-		code = "DUPLICATE_TRANSACTION"
+		return http.StatusConflict, "DUPLICATE_TRANSACTION"
+	}
+	return http.StatusInternalServerError, "INTERNAL_SERVER_ERROR"
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	status, code := ErrorStatus(err)
+
+	resp := model.ErrorResponse{Error: err.Error(), Code: code}
+	if errors.Is(err, model.ErrDuplicateTransaction) {
 		resp.Details = "Transaction ID already exists for a different user"
 	}
-	resp.Code = code
+
+	var mismatch *model.TransactionMismatchError
+	if errors.As(err, &mismatch) {
+		resp.Details = "Transaction ID already exists with different fields"
+		resp.Mismatches = mismatch.Mismatches
+	}
 
 	if status == http.StatusInternalServerError {
 		h.logger.Error().Err(err).Msg("internal server error")
@@ -91,3 +270,21 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 
 	c.JSON(status, resp)
 }
+
+// requireOperator returns the back-office operator performing the request, writing a 400 if it is missing.
+// With bearer tokens the operator is the token's subject and X-Operator-ID is ignored, so that a second
+// approval cannot be made under another operator's name.
+func (h *Handler) requireOperator(c *gin.Context) (string, bool) {
+	if p := auth.FromContext(c.Request.Context()); p != nil {
+		return p.Subject, true
+	}
+	operator := c.GetHeader("X-Operator-ID")
+	if operator == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "X-Operator-ID header is required",
+			Code:  "INVALID_REQUEST",
+		})
+		return "", false
+	}
+	return operator, true
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Health is the liveness probe (/livez, /health): the process is up and serving, whatever its dependencies.
+// It also reports whether this instance is the leader running scheduled background jobs.
+// Not in the API docs as it is served outside /api/v1.
+func (h *Handler) Health(c *gin.Context) {
+	resp := model.HealthResponse{Status: "ok"}
+	if h.leadership != nil {
+		leader := h.leadership.IsLeader()
+		resp.Leader = &leader
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Ready is the readiness probe (/readyz): 200 when the instance can serve traffic, 503 with the failing checks
+// when a dependency is down or the server is shutting down.
+// Not in the API docs as it is served outside /api/v1.
+func (h *Handler) Ready(c *gin.Context) {
+	resp := h.health.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if resp.Status != service.ReadinessOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resp)
+}
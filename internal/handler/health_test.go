@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealth_ReportsLeadership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockLeader := mocks.NewLeadership(t)
+	mockLeader.On("IsLeader").Return(true)
+
+	router := NewHandler(Deps{
+		Leadership: mockLeader,
+		Logger:     zerolog.Nop(),
+	}).SetupRoutes()
+
+	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok","leader":true}`, w.Body.String())
+}
+
+func TestHealth_WithoutElection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := NewHandler(Deps{
+		Logger: zerolog.Nop(),
+	}).SetupRoutes()
+
+	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockHealth := mocks.NewHealthService(t)
+	mockHealth.On("Readiness", mock.Anything).Return(&model.ReadinessResponse{
+		Status: "unavailable",
+		Checks: map[string]model.ReadinessCheck{"database": {Status: "failing", Message: "connection refused"}},
+	}).Once()
+	mockHealth.On("Readiness", mock.Anything).Return(&model.ReadinessResponse{
+		Status: "ok",
+		Checks: map[string]model.ReadinessCheck{"database": {Status: "ok"}},
+	}).Once()
+
+	router := NewHandler(Deps{
+		Health: mockHealth,
+		Logger: zerolog.Nop(),
+	}).SetupRoutes()
+
+	req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"unavailable","checks":{"database":{"status":"failing","message":"connection refused"}}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLivez_IgnoresDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := NewHandler(Deps{
+		Health: mocks.NewHealthService(t),
+		Logger: zerolog.Nop(),
+	}).SetupRoutes()
+
+	req, _ := http.NewRequest(http.MethodGet, "/livez", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
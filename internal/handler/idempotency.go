@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// responseRecorder keeps a copy of the response body so it can be stored for replays
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the stored response of a mutating request retried with the same Idempotency-Key.
+// Requests without the header are passed through. Reusing a key with a different method, path or body
+// is rejected with IDEMPOTENCY_MISMATCH, retrying while the first request runs with IDEMPOTENCY_IN_PROGRESS.
+func (h *Handler) IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if h.idempotencyService == nil || key == "" || c.Request.Method == http.MethodGet {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, model.ErrorResponse{
+				Error: "Idempotency-Key must be at most 255 characters",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, model.ErrorResponse{
+				Error: "Failed to read request body",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scope := c.Request.Method + " " + c.FullPath()
+		fingerprint := service.Fingerprint(c.Request.Method, c.Request.URL.Path, string(body))
+
+		rec, err := h.idempotencyService.Reserve(ctx, scope, key, fingerprint)
+		if err != nil {
+			h.handleError(c, err)
+			c.Abort()
+			return
+		}
+		if rec != nil {
+			c.Header(idempotentReplayedHeader, "true")
+			c.Data(rec.StatusCode, "application/json; charset=utf-8", rec.Response)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Server errors may be transient, let the client retry them with the same key
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := h.idempotencyService.Release(ctx, scope, key); err != nil {
+				h.logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to release idempotency key")
+			}
+			return
+		}
+
+		if err := h.idempotencyService.Complete(ctx, scope, key, fingerprint, status, recorder.body.Bytes()); err != nil {
+			h.logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to store idempotent response")
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newIdempotencyRouter(h *Handler, calls *int) *gin.Engine {
+	router := gin.New()
+	router.POST("/adjustments", h.IdempotencyMiddleware(), func(c *gin.Context) {
+		*calls++
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+	return router
+}
+
+func TestIdempotencyMiddleware_StoresFirstResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := mocks.NewIdempotencyService(t)
+	h := NewHandler(Deps{
+		Idempotency: mockSvc,
+		Logger:      zerolog.Nop(),
+	})
+
+	mockSvc.On("Reserve", mock.Anything, "POST /adjustments", "key-1", mock.Anything).Return(nil, nil)
+	mockSvc.On("Complete", mock.Anything, "POST /adjustments", "key-1", mock.Anything, http.StatusCreated, []byte(`{"id":1}`)).Return(nil)
+
+	calls := 0
+	router := newIdempotencyRouter(h, &calls)
+
+	req, _ := http.NewRequest(http.MethodPost, "/adjustments", strings.NewReader(`{"user_id":1}`))
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := mocks.NewIdempotencyService(t)
+	h := NewHandler(Deps{
+		Idempotency: mockSvc,
+		Logger:      zerolog.Nop(),
+	})
+
+	mockSvc.On("Reserve", mock.Anything, "POST /adjustments", "key-1", mock.Anything).Return(&model.IdempotencyRecord{
+		StatusCode: http.StatusCreated,
+		Response:   []byte(`{"id":1}`),
+	}, nil)
+
+	calls := 0
+	router := newIdempotencyRouter(h, &calls)
+
+	req, _ := http.NewRequest(http.MethodPost, "/adjustments", strings.NewReader(`{"user_id":1}`))
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.JSONEq(t, `{"id":1}`, w.Body.String())
+}
+
+func TestIdempotencyMiddleware_Mismatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := mocks.NewIdempotencyService(t)
+	h := NewHandler(Deps{
+		Idempotency: mockSvc,
+		Logger:      zerolog.Nop(),
+	})
+
+	mockSvc.On("Reserve", mock.Anything, "POST /adjustments", "key-1", mock.Anything).Return(nil, model.ErrIdempotencyMismatch)
+
+	calls := 0
+	router := newIdempotencyRouter(h, &calls)
+
+	req, _ := http.NewRequest(http.MethodPost, "/adjustments", strings.NewReader(`{"user_id":2}`))
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, calls)
+	assert.Contains(t, w.Body.String(), "IDEMPOTENCY_MISMATCH")
+}
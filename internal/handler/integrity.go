@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckIntegrity
+// @Summary Run a balance integrity check
+// @Description Recomputes every balance from transaction history and reports users that drifted.
+// @Description Drifting users are paused when INTEGRITY_PAUSE_ON_DRIFT is enabled.
+// @Tags integrity
+// @Produce json
+// @Success 200 {object} model.IntegrityReport
+// @Router /admin/integrity [get]
+func (h *Handler) CheckIntegrity(c *gin.Context) {
+	report, err := h.integrityService.RunCheck(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ResumeUser
+// @Summary Resume balance mutations for a user
+// @Description Lifts the pause set by the integrity check once the drift has been resolved
+// @Tags integrity
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param id path int true "User ID"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /admin/users/{id}/resume [post]
+func (h *Handler) ResumeUser(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+
+	if err := h.integrityService.ResumeUser(c.Request.Context(), userID, operator); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLossLimits
+// @Summary Get user loss limits
+// @Description Returns the responsible-gaming loss limits of a user with the net loss of the current windows
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} model.LossLimitsResponse
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /users/{id}/limits [get]
+func (h *Handler) GetLossLimits(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+
+	resp, err := h.limitService.GetLossLimits(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetLossLimit
+// @Summary Set a user loss limit
+// @Description Sets the daily, weekly or monthly net loss limit. Decreases apply immediately, increases after a cooling-off period
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param limit body model.LossLimitRequest true "Loss limit"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.LossLimitResponse
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /users/{id}/limits [put]
+func (h *Handler) SetLossLimit(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+
+	var req model.LossLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	resp, err := h.limitService.SetLossLimit(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"strings"
 	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,6 +20,7 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		}
 		c.Set("requestID", requestID)
 		c.Header("X-Request-ID", requestID)
+		c.Request = c.Request.WithContext(repository.WithRequestID(c.Request.Context(), requestID))
 		c.Next()
 	}
 }
@@ -35,6 +40,7 @@ func LoggingMiddleware() gin.HandlerFunc {
 
 		log.Info().
 			Str("request_id", requestID).
+			Str("tenant_id", c.GetString("tenantID")).
 			Int("status", status).
 			Str("method", c.Request.Method).
 			Str("path", path).
@@ -44,3 +50,113 @@ func LoggingMiddleware() gin.HandlerFunc {
 			Msg("HTTP Request")
 	}
 }
+
+// ReadYourWritesMiddleware sends the reads of a request to the primary when the client sets
+// X-Read-Your-Writes: true, e.g. to fetch a balance right after changing it
+func ReadYourWritesMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-Read-Your-Writes") == "true" {
+			c.Request = c.Request.WithContext(repository.WithReadYourWrites(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// AuthMiddleware authenticates the caller with the bearer token of the Authorization header unless authentication is
+// disabled (AUTH_DISABLED), the handlers find the caller with auth.FromContext
+func (h *Handler) AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.auth.Enabled() {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			h.handleError(c, model.ErrInvalidToken)
+			c.Abort()
+			return
+		}
+		principal, err := h.auth.Verify(strings.TrimSpace(token))
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			h.handleError(c, err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireRole rejects callers without one of the roles with FORBIDDEN, admins pass every check.
+// With authentication disabled every caller passes.
+func (h *Handler) RequireRole(roles ...auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p := auth.FromContext(c.Request.Context()); p != nil && !p.HasAnyRole(roles...) {
+			h.handleError(c, model.ErrForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AuditMiddleware logs who called which route and the outcome when tokens are enabled, including the calls
+// rejected for a missing token or role
+func (h *Handler) AuditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.auth.Enabled() {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		event := h.logger.Info().
+			Str("request_id", c.GetString("requestID")).
+			Str("tenant_id", c.GetString("tenantID")).
+			Str("method", c.Request.Method).
+			Str("route", c.FullPath()).
+			Str("path", c.Request.URL.Path).
+			Int("status", c.Writer.Status()).
+			Str("ip", c.ClientIP())
+		if p := auth.FromContext(c.Request.Context()); p != nil {
+			event = event.Str("subject", p.Subject).Strs("roles", p.RoleNames())
+		}
+		event.Msg("API audit")
+	}
+}
+
+// TenantMiddleware scopes the request to the tenant named by the caller's token or else the tenant of the
+// X-API-Key header, every repository query made with the request context only sees that tenant's users.
+// Without a tenants file all requests belong to the default tenant.
+func (h *Handler) TenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, err := h.tenants.Resolve(c.GetHeader("X-API-Key"))
+		if p := auth.FromContext(c.Request.Context()); p != nil && p.TenantID != "" {
+			tenantID, err = p.TenantID, h.tenants.Check(p.TenantID)
+		}
+		if err != nil {
+			h.handleError(c, err)
+			c.Abort()
+			return
+		}
+		c.Set("tenantID", tenantID)
+		c.Request = c.Request.WithContext(repository.WithTenant(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
+
+// PlatformOnlyMiddleware restricts routes acting on resources shared by all tenants to the default tenant
+func (h *Handler) PlatformOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("tenantID") != model.DefaultTenant {
+			h.handleError(c, model.ErrPlatformOnly)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
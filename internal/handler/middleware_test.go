@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/auth/authtest"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/payment"
+	"transaction-processor/internal/repository"
+	"transaction-processor/internal/service"
+	repomocks "transaction-processor/mocks/repository"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadYourWritesMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var primary bool
+	router := gin.New()
+	router.GET("/balance", ReadYourWritesMiddleware(), func(c *gin.Context) {
+		primary = repository.ReadYourWrites(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/balance", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, primary)
+
+	req.Header.Set("X-Read-Your-Writes", "true")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, primary)
+}
+
+func newTenantRouter(t *testing.T) (*gin.Engine, *mocks.TransactionService, *mocks.CancellationWorkerControl) {
+	gin.SetMode(gin.TestMode)
+	hash := func(key string) string {
+		sum := sha256.Sum256([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+	tenants, err := service.ParseTenants([]byte(fmt.Sprintf(
+		"tenants:\n  - id: default\n    api_keys_sha256: [%s]\n  - id: brand-a\n    api_keys_sha256: [%s]\n",
+		hash("platform-key"), hash("key-a"))))
+	require.NoError(t, err)
+
+	mockSvc := mocks.NewTransactionService(t)
+	mockWorker := mocks.NewCancellationWorkerControl(t)
+	h := NewHandler(Deps{
+		Transactions:       mockSvc,
+		CancellationWorker: mockWorker,
+		Tenants:            tenants,
+		Logger:             zerolog.Nop(),
+	})
+	return h.SetupRoutes(), mockSvc, mockWorker
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	router, mockSvc, _ := newTenantRouter(t)
+
+	inTenant := mock.MatchedBy(func(ctx context.Context) bool { return repository.TenantID(ctx) == "brand-a" })
+	mockSvc.On("GetBalance", inTenant, int64(1)).Return(&model.BalanceResponse{UserID: 1, Balance: "10.00"}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/users/1/balance", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "UNAUTHENTICATED")
+
+	req.Header.Set("X-API-Key", "key-a")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPlatformOnlyMiddleware(t *testing.T) {
+	router, _, mockWorker := newTenantRouter(t)
+
+	mockWorker.On("Status", mock.Anything).Return(&model.WorkerStatus{Name: "cancellation"}, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/workers/cancellation", nil)
+	req.Header.Set("X-API-Key", "key-a")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "PLATFORM_ONLY")
+
+	req.Header.Set("X-API-Key", "platform-key")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issuer := authtest.NewIssuer(t)
+	mockSvc := mocks.NewTransactionService(t)
+	h := NewHandler(Deps{
+		Transactions: mockSvc,
+		Verifier:     issuer.Verifier(),
+		Logger:       zerolog.Nop(),
+	})
+	router := h.SetupRoutes()
+
+	mockSvc.On("GetBalance", mock.Anything, int64(1)).Return(&model.BalanceResponse{UserID: 1, Balance: "10.00"}, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/users/1/balance", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_TOKEN")
+	assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+
+	req.Header.Set("Authorization", "Bearer "+authtest.NewIssuer(t).Token("bob", auth.RoleSupport))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req.Header.Set("Authorization", "Bearer "+issuer.Token("bob", auth.RoleSupport))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issuer := authtest.NewIssuer(t)
+	mockCancel := mocks.NewCancellationService(t)
+	h := NewHandler(Deps{
+		Cancellations: mockCancel,
+		Verifier:      issuer.Verifier(),
+		Logger:        zerolog.Nop(),
+	})
+	router := h.SetupRoutes()
+
+	// The operator is the token subject, not the X-Operator-ID header
+	mockCancel.On("CancelTransaction", mock.Anything, "tx-1", "alice", model.CancelReasonFraud, "").
+		Return(&model.Transaction{TransactionID: "tx-1", Status: model.StatusCancelled}, nil).Once()
+
+	cancel := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/transactions/tx-1/cancel", bytes.NewBufferString(`{"reason":"fraud"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Operator-ID", "mallory")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := cancel(issuer.Sign(map[string]any{
+		"sub":         "bob",
+		"roles":       []auth.Role{auth.RoleSupport, auth.RoleProvider},
+		"provider_id": "acme",
+		"exp":         time.Now().Add(time.Hour).Unix(),
+	}))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "FORBIDDEN")
+
+	w = cancel(issuer.Token("alice", auth.RoleOperator))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Only admins configure the workers
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/workers/cancellation/pause", nil)
+	req.Header.Set("Authorization", "Bearer "+issuer.Token("alice", auth.RoleOperator))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestTenantMiddleware_TokenTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issuer := authtest.NewIssuer(t)
+	tenants, err := service.ParseTenants([]byte("tenants:\n  - id: brand-a\n    api_keys_sha256: [" + strings.Repeat("ab", 32) + "]\n"))
+	require.NoError(t, err)
+	mockSvc := mocks.NewTransactionService(t)
+	h := NewHandler(Deps{
+		Transactions: mockSvc,
+		Tenants:      tenants,
+		Verifier:     issuer.Verifier(),
+		Logger:       zerolog.Nop(),
+	})
+	router := h.SetupRoutes()
+
+	inTenant := mock.MatchedBy(func(ctx context.Context) bool { return repository.TenantID(ctx) == "brand-a" })
+	mockSvc.On("GetBalance", inTenant, int64(1)).Return(&model.BalanceResponse{UserID: 1, Balance: "10.00"}, nil).Once()
+
+	balance := func(tenantID string) *httptest.ResponseRecorder {
+		token := issuer.Sign(map[string]any{
+			"sub":         "acme-games",
+			"roles":       []string{"provider"},
+			"tenant_id":   tenantID,
+			"provider_id": "acme",
+			"exp":         time.Now().Add(time.Hour).Unix(),
+		})
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/users/1/balance", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, balance("brand-a").Code)
+	w := balance("brand-z")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_TOKEN")
+}
+
+func TestProviderToken_OtherProvidersUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issuer := authtest.NewIssuer(t)
+	logger := zerolog.Nop()
+
+	// Real services over repositories holding a user of provider beta
+	userRepo := repomocks.NewUserRepository(t)
+	userRepo.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, ProviderID: "beta"}, nil).Maybe()
+	userRepo.On("GetUserForUpdate", mock.Anything, int64(1), mock.Anything).Return(&model.User{ID: 1, ProviderID: "beta"}, nil).Maybe()
+	dbManager := repomocks.NewDBManager(t)
+	dbManager.On("WithTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	}).Maybe()
+
+	h := NewHandler(Deps{
+		Transactions:  service.NewTransactionService(userRepo, nil, nil, nil, dbManager, nil, nil, nil, nil, nil, time.Hour, logger),
+		Limits:        service.NewLimitService(userRepo, nil, dbManager, time.Hour, logger),
+		Bonuses:       service.NewBonusService(userRepo, nil, dbManager, nil, logger),
+		Payments:      service.NewPaymentService(userRepo, nil, nil, dbManager, nil, nil, payment.NewStubProvider("secret"), logger),
+		BalanceStream: service.NewBalanceStreamService(userRepo, nil, logger),
+		Verifier:      issuer.Verifier(),
+		Logger:        logger,
+	})
+	router := h.SetupRoutes()
+
+	token := issuer.Sign(map[string]any{
+		"sub":         "alpha-games",
+		"roles":       []string{"provider"},
+		"provider_id": "alpha",
+		"exp":         time.Now().Add(time.Hour).Unix(),
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/api/v1/users/1/balance", ""},
+		{http.MethodGet, "/api/v1/users/1/balance/stream", ""},
+		{http.MethodGet, "/api/v1/transactions/user/1", ""},
+		{http.MethodGet, "/api/v1/users/1/limits", ""},
+		{http.MethodPut, "/api/v1/users/1/limits", `{"period":"daily","amount":"100.00"}`},
+		{http.MethodGet, "/api/v1/users/1/bonus", ""},
+		{http.MethodGet, "/api/v1/users/1/payments", ""},
+		{http.MethodPost, "/api/v1/users/1/deposits", `{"amount":"50.00"}`},
+		{http.MethodPost, "/api/v1/users/1/withdrawals", `{"amount":"30.00"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), "PROVIDER_NOT_ALLOWED")
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPartitions
+// @Summary List transaction partitions
+// @Description Monthly partitions of the transactions table, archived ones include their archive file and row count
+// @Tags partitions
+// @Produce json
+// @Success 200 {array} model.TransactionPartition
+// @Router /admin/partitions [get]
+func (h *Handler) ListPartitions(c *gin.Context) {
+	partitions, err := h.partitionService.ListPartitions(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if partitions == nil {
+		partitions = []*model.TransactionPartition{}
+	}
+
+	c.JSON(http.StatusOK, partitions)
+}
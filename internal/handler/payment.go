@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/shopspring/decimal"
+)
+
+// RequestDeposit
+// @Summary Request a deposit
+// @Description Records a pending deposit, the balance is credited once an operator approves it and the payment provider completes it
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param payment body model.PaymentRequest true "Deposit"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 201 {object} model.Payment
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /users/{id}/deposits [post]
+func (h *Handler) RequestDeposit(c *gin.Context) {
+	h.requestPayment(c, model.PaymentDeposit)
+}
+
+// RequestWithdrawal
+// @Summary Request a withdrawal
+// @Description Moves the amount from the balance to the reserved funds and records a pending withdrawal. The funds go back to the balance if the withdrawal fails
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param payment body model.PaymentRequest true "Withdrawal"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 201 {object} model.Payment
+// @Failure 400 {object} model.ErrorResponse "Bad request or insufficient balance"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Failure 423 {object} model.ErrorResponse "Mutations paused"
+// @Router /users/{id}/withdrawals [post]
+func (h *Handler) RequestWithdrawal(c *gin.Context) {
+	h.requestPayment(c, model.PaymentWithdrawal)
+}
+
+func (h *Handler) requestPayment(c *gin.Context, kind model.PaymentKind) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+
+	var req model.PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid amount",
+			Code:  "INVALID_AMOUNT",
+		})
+		return
+	}
+
+	var payment *model.Payment
+	if kind == model.PaymentDeposit {
+		payment, err = h.paymentService.RequestDeposit(c.Request.Context(), userID, amount)
+	} else {
+		payment, err = h.paymentService.RequestWithdrawal(c.Request.Context(), userID, amount)
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+// ListUserPayments
+// @Summary List user payments
+// @Description Returns the deposits and withdrawals of a user, newest first
+// @Tags payments
+// @Produce json
+// @Param id path int true "User ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} model.PaymentListResponse
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /users/{id}/payments [get]
+func (h *Handler) ListUserPayments(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	payments, err := h.paymentService.ListUserPayments(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.PaymentListResponse{
+		Payments: payments,
+		Total:    len(payments),
+		Limit:    limit,
+		Offset:   offset,
+	})
+}
+
+// ListPayments
+// @Summary List payments
+// @Description Returns deposits and withdrawals with the given status, oldest first
+// @Tags payments
+// @Produce json
+// @Param status query string false "Status (pending, submitting, approved, completed, failed)" default(pending)
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} model.PaymentListResponse
+// @Failure 400 {object} model.ErrorResponse "Invalid status"
+// @Router /admin/payments [get]
+func (h *Handler) ListPayments(c *gin.Context) {
+	status, err := model.ParsePaymentStatus(c.DefaultQuery("status", string(model.PaymentPending)))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	payments, err := h.paymentService.ListPayments(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.PaymentListResponse{
+		Payments: payments,
+		Total:    len(payments),
+		Limit:    limit,
+		Offset:   offset,
+	})
+}
+
+// ApprovePayment
+// @Summary Approve a payment
+// @Description Submits a pending deposit or withdrawal to the payment provider, which reports the outcome with a callback.
+// @Description A payment left submitting by a failed submission is submitted again, the provider recognizes it by its transaction ID
+// @Tags payments
+// @Produce json
+// @Param X-Operator-ID header string true "Approving operator"
+// @Param id path int true "Payment ID"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "Payment not found"
+// @Failure 409 {object} model.ErrorResponse "Not pending or submitting"
+// @Router /admin/payments/{id}/approve [post]
+func (h *Handler) ApprovePayment(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrPaymentNotFound)
+		return
+	}
+
+	payment, err := h.paymentService.ApprovePayment(c.Request.Context(), id, operator)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// RejectPayment
+// @Summary Reject a payment
+// @Description Fails a pending deposit or withdrawal, the reserved funds of a withdrawal go back to the balance
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Operator-ID header string true "Rejecting operator"
+// @Param id path int true "Payment ID"
+// @Param rejection body model.PaymentRejectRequest true "Reason"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "Payment not found"
+// @Failure 409 {object} model.ErrorResponse "Not pending"
+// @Router /admin/payments/{id}/reject [post]
+func (h *Handler) RejectPayment(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrPaymentNotFound)
+		return
+	}
+
+	var req model.PaymentRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	payment, err := h.paymentService.RejectPayment(c.Request.Context(), id, operator, req.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// PaymentCallback
+// @Summary Payment provider callback
+// @Description Completes or fails an approved payment. The body must be signed by the provider, retries of the same outcome are acknowledged without changes
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "Hex encoded HMAC-SHA256 of the body"
+// @Param callback body model.PaymentCallback true "Outcome"
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 401 {object} model.ErrorResponse "Invalid signature"
+// @Failure 404 {object} model.ErrorResponse "Payment not found"
+// @Failure 409 {object} model.ErrorResponse "Not approved"
+// @Failure 423 {object} model.ErrorResponse "Mutations paused, retry later"
+// @Router /payments/callback [post]
+func (h *Handler) PaymentCallback(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	// The signature covers the raw body, it is checked before anything in it is trusted
+	if err := h.paymentService.VerifyCallback(body, c.GetHeader("X-Payment-Signature")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	var req model.PaymentCallback
+	if err := json.Unmarshal(body, &req); err != nil || binding.Validator.ValidateStruct(&req) != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	status, err := model.ParsePaymentStatus(req.Status)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	payment, err := h.paymentService.HandleCallback(c.Request.Context(), req.Reference, status, req.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
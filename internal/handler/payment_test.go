@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_RequestWithdrawal_Created(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPayments := mocks.NewPaymentService(t)
+	h := NewHandler(Deps{
+		Payments: mockPayments,
+		Logger:   zerolog.Nop(),
+	})
+
+	mockPayments.On("RequestWithdrawal", mock.Anything, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(30))
+	})).Return(&model.Payment{ID: 7, UserID: 1, Kind: model.PaymentWithdrawal, Status: model.PaymentPending}, nil)
+
+	body, _ := json.Marshal(model.PaymentRequest{Amount: "30"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/users/1/withdrawals", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp model.Payment
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, model.PaymentPending, resp.Status)
+}
+
+func TestHandler_PaymentCallback_InvalidSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPayments := mocks.NewPaymentService(t)
+	h := NewHandler(Deps{
+		Payments: mockPayments,
+		Logger:   zerolog.Nop(),
+	})
+
+	body := []byte(`{"reference":"stub-1","status":"completed"}`)
+	mockPayments.On("VerifyCallback", body, "forged").Return(model.ErrInvalidSignature)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/payments/callback", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Payment-Signature", "forged")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockPayments.AssertNotCalled(t, "HandleCallback")
+}
+
+func TestHandler_PaymentCallback_Completed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPayments := mocks.NewPaymentService(t)
+	h := NewHandler(Deps{
+		Payments: mockPayments,
+		Logger:   zerolog.Nop(),
+	})
+
+	body := []byte(`{"reference":"stub-1","status":"completed"}`)
+	mockPayments.On("VerifyCallback", body, "signed").Return(nil)
+	mockPayments.On("HandleCallback", mock.Anything, "stub-1", model.PaymentCompleted, "").
+		Return(&model.Payment{ID: 7, Status: model.PaymentCompleted}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/payments/callback", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Payment-Signature", "signed")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHandler_PaymentCallback_InvalidStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPayments := mocks.NewPaymentService(t)
+	h := NewHandler(Deps{
+		Payments: mockPayments,
+		Logger:   zerolog.Nop(),
+	})
+
+	body := []byte(`{"reference":"stub-1","status":"approved"}`)
+	mockPayments.On("VerifyCallback", body, "signed").Return(nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/payments/callback", bytes.NewBuffer(body))
+	req.Header.Set("X-Payment-Signature", "signed")
+	w := httptest.NewRecorder()
+
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockPayments.AssertNotCalled(t, "HandleCallback")
+}
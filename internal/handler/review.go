@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPendingReviews
+// @Summary List transactions pending review
+// @Description Returns transactions held for manual review, oldest first
+// @Tags reviews
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} model.TransactionListResponse
+// @Router /admin/reviews [get]
+func (h *Handler) ListPendingReviews(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	transactions, err := h.reviewService.ListPendingReviews(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.TransactionListResponse{
+		Transactions: transactions,
+		Total:        len(transactions),
+		Limit:        limit,
+		Offset:       offset,
+	})
+}
+
+// ApproveTransaction
+// @Summary Approve a pending transaction
+// @Description Applies a transaction held for review to the user's balance
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param transaction_id path string true "Transaction ID"
+// @Param decision body model.ReviewDecisionRequest false "Review note"
+// @Param X-Operator-ID header string false "Reviewing operator, required when auth is disabled"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.Transaction
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "Transaction not found"
+// @Failure 409 {object} model.ErrorResponse "Not pending review"
+// @Router /admin/reviews/{transaction_id}/approve [post]
+func (h *Handler) ApproveTransaction(c *gin.Context) {
+	h.reviewDecision(c, true)
+}
+
+// RejectTransaction
+// @Summary Reject a pending transaction
+// @Description Declines a transaction held for review, the balance is not changed
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param transaction_id path string true "Transaction ID"
+// @Param decision body model.ReviewDecisionRequest false "Review note"
+// @Param X-Operator-ID header string false "Reviewing operator, required when auth is disabled"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.Transaction
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 404 {object} model.ErrorResponse "Transaction not found"
+// @Failure 409 {object} model.ErrorResponse "Not pending review"
+// @Router /admin/reviews/{transaction_id}/reject [post]
+func (h *Handler) RejectTransaction(c *gin.Context) {
+	h.reviewDecision(c, false)
+}
+
+func (h *Handler) reviewDecision(c *gin.Context, approve bool) {
+	reviewer, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	var req model.ReviewDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{
+				Error: "Invalid request body",
+				Code:  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+
+	transactionID := c.Param("transaction_id")
+
+	var (
+		trans *model.Transaction
+		err   error
+	)
+	if approve {
+		trans, err = h.reviewService.ApproveTransaction(c.Request.Context(), transactionID, reviewer, req.Note)
+	} else {
+		trans, err = h.reviewService.RejectTransaction(c.Request.Context(), transactionID, reviewer, req.Note)
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trans)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/auth/authtest"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_ApproveTransaction_ReviewerFromToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issuer := authtest.NewIssuer(t)
+	mockReview := mocks.NewReviewService(t)
+	h := NewHandler(Deps{
+		Reviews:  mockReview,
+		Verifier: issuer.Verifier(),
+		Logger:   zerolog.Nop(),
+	})
+
+	mockReview.On("ApproveTransaction", mock.Anything, "tx-1", "alice", "").
+		Return(&model.Transaction{TransactionID: "tx-1", Status: model.StatusProcessed, ReviewedBy: "alice"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/reviews/tx-1/approve", nil)
+	req.Header.Set("Authorization", "Bearer "+issuer.Token("alice", auth.RoleOperator))
+	req.Header.Set("X-Operator-ID", "mallory")
+	w := httptest.NewRecorder()
+	h.SetupRoutes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHandler_RejectTransaction_AuthDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockReview := mocks.NewReviewService(t)
+	h := NewHandler(Deps{
+		Reviews: mockReview,
+		Logger:  zerolog.Nop(),
+	})
+
+	mockReview.On("RejectTransaction", mock.Anything, "tx-1", "bob", "").
+		Return(&model.Transaction{TransactionID: "tx-1", Status: model.StatusRejected, ReviewedBy: "bob"}, nil)
+
+	// Without a verifier (AUTH_DISABLED) the reviewer comes from X-Operator-ID, like every other back-office decision
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/reviews/tx-1/reject", nil)
+	req.Header.Set("X-Operator-ID", "bob")
+	w := httptest.NewRecorder()
+	h.SetupRoutes().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/admin/reviews/tx-1/reject", nil)
+	w = httptest.NewRecorder()
+	h.SetupRoutes().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeatInterval keeps idle streams open through proxies and detects gone clients
+const streamHeartbeatInterval = 15 * time.Second
+
+// StreamBalance
+// @Summary Stream user balance
+// @Description Server-sent events with the user's balance, sent on connect and after every change.
+// @Description Each event id is the balance version; reconnecting with Last-Event-ID skips the initial event if nothing changed.
+// @Tags users
+// @Produce text/event-stream
+// @Param id path int true "User ID"
+// @Param Last-Event-ID header int false "Last received event id"
+// @Success 200 {object} model.BalanceEvent "event: balance"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /users/{id}/balance/stream [get]
+func (h *Handler) StreamBalance(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+
+	// Subscribe before reading the current balance so no change is lost in between
+	events, unsubscribe := h.balanceStream.Subscribe(userID)
+	defer unsubscribe()
+
+	current, err := h.balanceStream.Current(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	lastVersion := -1
+	if lastEventID, err := strconv.Atoi(c.GetHeader("Last-Event-ID")); err == nil && lastEventID <= current.Version {
+		lastVersion = lastEventID
+	}
+
+	// Streams outlive the server write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	send := func(event *model.BalanceEvent) error {
+		if event.Version <= lastVersion {
+			return nil
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: balance\ndata: %s\n\n", event.Version, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		lastVersion = event.Version
+		return nil
+	}
+
+	if err := send(current); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-events:
+			// Server is shutting down
+			if !ok {
+				return
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
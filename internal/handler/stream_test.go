@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_StreamBalance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStream := mocks.NewBalanceStreamService(t)
+	h := NewHandler(Deps{
+		BalanceStream: mockStream,
+		Logger:        zerolog.Nop(),
+	})
+
+	router := gin.New()
+	router.GET("/users/:id/balance/stream", h.StreamBalance)
+
+	events := make(chan *model.BalanceEvent)
+	mockStream.On("Subscribe", int64(1)).Return((<-chan *model.BalanceEvent)(events), func() {})
+	mockStream.On("Current", mock.Anything, int64(1)).Return(&model.BalanceEvent{UserID: 1, Balance: "100.00", Version: 3}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/users/1/balance/stream", nil)
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(w, req)
+		close(done)
+	}()
+
+	// Stale event from before the snapshot is skipped
+	events <- &model.BalanceEvent{UserID: 1, Balance: "90.00", Version: 2}
+	events <- &model.BalanceEvent{UserID: 1, Balance: "110.00", Version: 4}
+	cancel()
+	<-done
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t,
+		"id: 3\nevent: balance\ndata: {\"user_id\":1,\"balance\":\"100.00\",\"version\":3}\n\n"+
+			"id: 4\nevent: balance\ndata: {\"user_id\":1,\"balance\":\"110.00\",\"version\":4}\n\n",
+		w.Body.String())
+}
+
+func TestHandler_StreamBalance_ResumeSkipsUnchanged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStream := mocks.NewBalanceStreamService(t)
+	h := NewHandler(Deps{
+		BalanceStream: mockStream,
+		Logger:        zerolog.Nop(),
+	})
+
+	router := gin.New()
+	router.GET("/users/:id/balance/stream", h.StreamBalance)
+
+	events := make(chan *model.BalanceEvent)
+	close(events)
+	mockStream.On("Subscribe", int64(1)).Return((<-chan *model.BalanceEvent)(events), func() {})
+	mockStream.On("Current", mock.Anything, int64(1)).Return(&model.BalanceEvent{UserID: 1, Balance: "100.00", Version: 3}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/1/balance/stream", nil)
+	req.Header.Set("Last-Event-ID", "3")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestHandler_StreamBalance_UserNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockStream := mocks.NewBalanceStreamService(t)
+	h := NewHandler(Deps{
+		BalanceStream: mockStream,
+		Logger:        zerolog.Nop(),
+	})
+
+	router := gin.New()
+	router.GET("/users/:id/balance/stream", h.StreamBalance)
+
+	events := make(chan *model.BalanceEvent)
+	mockStream.On("Subscribe", int64(9)).Return((<-chan *model.BalanceEvent)(events), func() {})
+	mockStream.On("Current", mock.Anything, int64(9)).Return(nil, model.ErrUserNotFound)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/9/balance/stream", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
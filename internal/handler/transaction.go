@@ -15,11 +15,14 @@ import (
 // @Accept json
 // @Produce json
 // @Param Source-Type header string true "Source type" Enums(game, server, payment)
+// @Param Provider-ID header string false "Provider ID, used for per-provider amount limits"
 // @Param user_id query int true "User ID"
 // @Param transaction body model.TransactionRequest true "Transaction details"
 // @Success 200 {object} model.TransactionResponse "Already processed"
 // @Success 201 {object} model.TransactionResponse "Created"
+// @Success 202 {object} model.TransactionResponse "Pending review"
 // @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "Blocked by risk rules"
 // @Failure 409 {object} model.ErrorResponse "Conflict"
 // @Router /transactions [post]
 func (h *Handler) ProcessTransaction(c *gin.Context) {
@@ -57,6 +60,8 @@ func (h *Handler) ProcessTransaction(c *gin.Context) {
 		return
 	}
 
+	req.ProviderID = c.GetHeader("Provider-ID")
+
 	resp, err := h.transactionService.ProcessTransaction(c.Request.Context(), &req, sourceType, userID)
 	if err != nil {
 		h.handleError(c, err)
@@ -64,8 +69,11 @@ func (h *Handler) ProcessTransaction(c *gin.Context) {
 	}
 
 	statusCode := http.StatusCreated
-	if resp.Status == "already_processed" {
+	switch resp.Status {
+	case "already_processed":
 		statusCode = http.StatusOK
+	case "pending_review":
+		statusCode = http.StatusAccepted
 	}
 	c.JSON(statusCode, resp)
 }
@@ -131,3 +139,47 @@ func (h *Handler) GetTransactionsByUser(c *gin.Context) {
 		Offset:       offset,
 	})
 }
+
+// SetUserProvider
+// @Summary Bind a user to a provider
+// @Description Sets the game provider a user belongs to, the only provider whose tokens may reach the user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param id path int true "User ID"
+// @Param provider body model.UserProviderRequest true "Provider"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 403 {object} model.ErrorResponse "Provider not allowed for the tenant"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Router /admin/users/{id}/provider [put]
+func (h *Handler) SetUserProvider(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, model.ErrUserNotFound)
+		return
+	}
+
+	var req model.UserProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	if err := h.transactionService.SetUserProvider(c.Request.Context(), userID, req.ProviderID, operator); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,7 +20,10 @@ func TestHandler_ProcessTransaction_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := mocks.NewTransactionService(t)
 	logger := zerolog.Nop()
-	h := NewHandler(mockSvc, logger)
+	h := NewHandler(Deps{
+		Transactions: mockSvc,
+		Logger:       logger,
+	})
 
 	router := gin.New()
 	router.POST("/transactions", h.ProcessTransaction)
@@ -54,7 +58,10 @@ func TestHandler_ProcessTransaction_Success(t *testing.T) {
 func TestHandler_ProcessTransaction_InvalidUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := mocks.NewTransactionService(t)
-	h := NewHandler(mockSvc, zerolog.Nop())
+	h := NewHandler(Deps{
+		Transactions: mockSvc,
+		Logger:       zerolog.Nop(),
+	})
 
 	router := gin.New()
 	router.POST("/transactions", h.ProcessTransaction)
@@ -78,3 +85,94 @@ func TestHandler_ProcessTransaction_InvalidUUID(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Equal(t, "INVALID_REQUEST", resp.Code)
 }
+
+func TestHandler_ProcessTransaction_Mismatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := mocks.NewTransactionService(t)
+	h := NewHandler(Deps{
+		Transactions: mockSvc,
+		Logger:       zerolog.Nop(),
+	})
+
+	router := gin.New()
+	router.POST("/transactions", h.ProcessTransaction)
+
+	body, _ := json.Marshal(model.TransactionRequest{
+		TransactionID: "550e8400-e29b-41d4-a716-446655440000",
+		Amount:        "20.00",
+		State:         "win",
+	})
+
+	mockSvc.On("ProcessTransaction", mock.Anything, mock.Anything, model.SourceType("game"), int64(1)).Return(nil, &model.TransactionMismatchError{
+		TransactionID: "550e8400-e29b-41d4-a716-446655440000",
+		Mismatches:    []model.FieldMismatch{{Field: "amount", Stored: "10", Received: "20"}},
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/transactions?user_id=1", bytes.NewBuffer(body))
+	req.Header.Set("Source-Type", "game")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp model.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "TRANSACTION_MISMATCH", resp.Code)
+	assert.Equal(t, []model.FieldMismatch{{Field: "amount", Stored: "10", Received: "20"}}, resp.Mismatches)
+}
+
+func TestHandler_SetUserProvider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := mocks.NewTransactionService(t)
+	h := NewHandler(Deps{
+		Transactions: mockSvc,
+		Logger:       zerolog.Nop(),
+	})
+
+	router := gin.New()
+	router.PUT("/users/:id/provider", h.SetUserProvider)
+
+	mockSvc.On("SetUserProvider", mock.Anything, int64(1), "acme", "ops-1").Return(nil).Once()
+	mockSvc.On("SetUserProvider", mock.Anything, int64(2), "acme", "ops-1").Return(model.ErrUserNotFound).Once()
+
+	send := func(path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPut, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Operator-ID", "ops-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNoContent, send("/users/1/provider", `{"provider_id":"acme"}`).Code)
+	assert.Equal(t, http.StatusNotFound, send("/users/2/provider", `{"provider_id":"acme"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send("/users/1/provider", `{}`).Code)
+}
+
+func TestHandler_ProcessTransaction_ArchiveUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSvc := mocks.NewTransactionService(t)
+	h := NewHandler(Deps{
+		Transactions: mockSvc,
+		Logger:       zerolog.Nop(),
+	})
+
+	router := gin.New()
+	router.POST("/transactions", h.ProcessTransaction)
+
+	// A retry of an archived transaction on an instance without the archive file
+	mockSvc.On("ProcessTransaction", mock.Anything, mock.Anything, model.SourceType("game"), int64(1)).
+		Return(nil, fmt.Errorf("get transaction: %w", model.ErrArchiveUnavailable))
+
+	body := `{"transaction_id":"550e8400-e29b-41d4-a716-446655440000","amount":"10.00","state":"win"}`
+	req, _ := http.NewRequest(http.MethodPost, "/transactions?user_id=1", bytes.NewBufferString(body))
+	req.Header.Set("Source-Type", "game")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "ARCHIVE_UNAVAILABLE")
+}
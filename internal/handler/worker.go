@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"transaction-processor/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCancellationWorker
+// @Summary Get the cancellation worker status
+// @Description Returns whether automatic cancellations are paused, the interval and the last run of any instance
+// @Tags workers
+// @Produce json
+// @Success 200 {object} model.WorkerStatus
+// @Router /admin/workers/cancellation [get]
+func (h *Handler) GetCancellationWorker(c *gin.Context) {
+	status, err := h.cancellationWorker.Status(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// PauseCancellationWorker
+// @Summary Pause automatic cancellations
+// @Description Stops automatic cancellation runs on all instances until resumed, a run in progress completes
+// @Tags workers
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.WorkerStatus
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Router /admin/workers/cancellation/pause [post]
+func (h *Handler) PauseCancellationWorker(c *gin.Context) {
+	h.setCancellationWorkerPaused(c, true)
+}
+
+// ResumeCancellationWorker
+// @Summary Resume automatic cancellations
+// @Description Restarts automatic cancellation runs, the next run follows the current interval
+// @Tags workers
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.WorkerStatus
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Router /admin/workers/cancellation/resume [post]
+func (h *Handler) ResumeCancellationWorker(c *gin.Context) {
+	h.setCancellationWorkerPaused(c, false)
+}
+
+func (h *Handler) setCancellationWorkerPaused(c *gin.Context, paused bool) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	var err error
+	if paused {
+		err = h.cancellationWorker.Pause(ctx, operator)
+	} else {
+		err = h.cancellationWorker.Resume(ctx, operator)
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	status, err := h.cancellationWorker.Status(ctx)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// RunCancellationWorker
+// @Summary Run a cancellation now
+// @Description Runs the odd-record cancellation immediately on this instance and returns the worker status with its result
+// @Tags workers
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.WorkerStatus
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Failure 409 {object} model.ErrorResponse "Worker paused or already running"
+// @Router /admin/workers/cancellation/run [post]
+func (h *Handler) RunCancellationWorker(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	h.logger.Info().Str("operator", operator).Msg("cancellation run triggered")
+
+	status, err := h.cancellationWorker.Trigger(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// SetCancellationWorkerInterval
+// @Summary Change the cancellation interval
+// @Description Changes the interval between automatic runs on all instances, it replaces the configured schedule until reset
+// @Tags workers
+// @Accept json
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param interval body model.WorkerIntervalRequest true "Interval"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.WorkerStatus
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Router /admin/workers/cancellation/interval [put]
+func (h *Handler) SetCancellationWorkerInterval(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	var req model.WorkerIntervalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error: "Invalid request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	// Intervals are stored in milliseconds
+	interval, err := time.ParseDuration(req.Interval)
+	if err != nil || interval < time.Millisecond {
+		h.handleError(c, fmt.Errorf("%w: %q must be a duration of at least 1ms", model.ErrInvalidInterval, req.Interval))
+		return
+	}
+
+	h.setCancellationWorkerInterval(c, interval.Truncate(time.Millisecond), operator)
+}
+
+// ResetCancellationWorkerInterval
+// @Summary Restore the configured cancellation schedule
+// @Description Removes the interval set through the API, all instances return to WORKER_CANCELLATION_INTERVAL or WORKER_CANCELLATION_CRON
+// @Tags workers
+// @Produce json
+// @Param X-Operator-ID header string true "Operator"
+// @Param Idempotency-Key header string false "Replays the stored response when the request is retried with the same key"
+// @Success 200 {object} model.WorkerStatus
+// @Failure 400 {object} model.ErrorResponse "Bad request"
+// @Router /admin/workers/cancellation/interval [delete]
+func (h *Handler) ResetCancellationWorkerInterval(c *gin.Context) {
+	operator, ok := h.requireOperator(c)
+	if !ok {
+		return
+	}
+
+	h.setCancellationWorkerInterval(c, 0, operator)
+}
+
+func (h *Handler) setCancellationWorkerInterval(c *gin.Context, interval time.Duration, operator string) {
+	ctx := c.Request.Context()
+	if err := h.cancellationWorker.SetInterval(ctx, interval, operator); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	status, err := h.cancellationWorker.Status(ctx)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newWorkerRouter(t *testing.T) (*gin.Engine, *mocks.CancellationWorkerControl) {
+	gin.SetMode(gin.TestMode)
+	mockWorker := mocks.NewCancellationWorkerControl(t)
+	h := NewHandler(Deps{
+		CancellationWorker: mockWorker,
+		Logger:             zerolog.Nop(),
+	})
+	return h.SetupRoutes(), mockWorker
+}
+
+func TestPauseCancellationWorker(t *testing.T) {
+	router, mockWorker := newWorkerRouter(t)
+
+	mockWorker.On("Pause", mock.Anything, "ops").Return(nil)
+	mockWorker.On("Status", mock.Anything).Return(&model.WorkerStatus{Name: "cancellation", Paused: true, PausedBy: "ops"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/workers/cancellation/pause", nil)
+	req.Header.Set("X-Operator-ID", "ops")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"paused":true`)
+}
+
+func TestPauseCancellationWorker_MissingOperator(t *testing.T) {
+	router, _ := newWorkerRouter(t)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/workers/cancellation/pause", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRunCancellationWorker_Paused(t *testing.T) {
+	router, mockWorker := newWorkerRouter(t)
+
+	mockWorker.On("Trigger", mock.Anything).Return(nil, model.ErrWorkerPaused)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/workers/cancellation/run", nil)
+	req.Header.Set("X-Operator-ID", "ops")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "WORKER_PAUSED")
+}
+
+func TestSetCancellationWorkerInterval(t *testing.T) {
+	router, mockWorker := newWorkerRouter(t)
+
+	mockWorker.On("SetInterval", mock.Anything, 30*time.Second, "ops").Return(nil)
+	mockWorker.On("Status", mock.Anything).Return(&model.WorkerStatus{Name: "cancellation", Interval: "30s"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/admin/workers/cancellation/interval", strings.NewReader(`{"interval":"30s"}`))
+	req.Header.Set("X-Operator-ID", "ops")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"interval":"30s"`)
+}
+
+func TestResetCancellationWorkerInterval(t *testing.T) {
+	router, mockWorker := newWorkerRouter(t)
+
+	mockWorker.On("SetInterval", mock.Anything, time.Duration(0), "ops").Return(nil)
+	mockWorker.On("Status", mock.Anything).Return(&model.WorkerStatus{Name: "cancellation", Interval: "2m0s"}, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/admin/workers/cancellation/interval", nil)
+	req.Header.Set("X-Operator-ID", "ops")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"interval":"2m0s"`)
+}
+
+func TestSetCancellationWorkerInterval_Invalid(t *testing.T) {
+	router, _ := newWorkerRouter(t)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/admin/workers/cancellation/interval", strings.NewReader(`{"interval":"-5m"}`))
+	req.Header.Set("X-Operator-ID", "ops")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_INTERVAL")
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/rs/zerolog"
@@ -17,3 +18,13 @@ func New(pretty bool) zerolog.Logger {
 
 	return zerolog.New(os.Stdout).With().Timestamp().Logger()
 }
+
+// SetLevel sets the minimum level of all loggers, e.g. "info"
+func SetLevel(level string) error {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	zerolog.SetGlobalLevel(lvl)
+	return nil
+}
@@ -0,0 +1,77 @@
+// Package metrics holds the Prometheus collectors exposed on /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "transaction_processor"
+
+var (
+	// IntegrityChecksTotal counts balance integrity checks by result (ok, drift, error)
+	IntegrityChecksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "integrity",
+		Name:      "checks_total",
+		Help:      "Balance integrity checks by result.",
+	}, []string{"result"})
+
+	IntegrityDriftUsers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "integrity",
+		Name:      "drift_users",
+		Help:      "Users whose balance differed from transaction history in the last check.",
+	})
+
+	IntegrityDriftAmount = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "integrity",
+		Name:      "drift_amount",
+		Help:      "Sum of absolute balance drifts in the last check.",
+	})
+
+	IntegrityLastCheck = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "integrity",
+		Name:      "last_check_timestamp_seconds",
+		Help:      "Unix time of the last completed integrity check.",
+	})
+
+	IntegrityPausedUsersTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "integrity",
+		Name:      "paused_users_total",
+		Help:      "Users whose mutations were paused because of balance drift.",
+	})
+
+	// TransactionMismatchesTotal counts resent transaction_ids whose fields differ from the stored transaction
+	TransactionMismatchesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "transactions",
+		Name:      "mismatches_total",
+		Help:      "Resent transaction_ids that did not match the stored transaction, by source type.",
+	}, []string{"source_type"})
+
+	ReplicaLagSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "replica_lag_seconds",
+		Help:      "Replication lag of the read replica at the last check.",
+	})
+
+	// ReplicaHealthy is 1 while reads are routed to the replica, 0 while they fall back to the primary
+	ReplicaHealthy = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "replica_healthy",
+		Help:      "Whether reads are routed to the read replica.",
+	})
+
+	// Leader is 1 while this instance holds the leader lock and runs scheduled background jobs
+	Leader = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "leader",
+		Help:      "Whether this instance is the elected leader.",
+	})
+)
@@ -1,13 +1,68 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
-	ErrInsufficientBalance  = errors.New("insufficient balance")
-	ErrDuplicateTransaction = errors.New("duplicate transaction")
-	ErrInvalidState         = errors.New("invalid state")
-	ErrInvalidAmount        = errors.New("invalid amount")
-	ErrInvalidSourceType    = errors.New("invalid source type")
-	ErrUserNotFound         = errors.New("user not found")
-	ErrTransactionNotFound  = errors.New("transaction not found")
+	ErrInsufficientBalance     = errors.New("insufficient balance")
+	ErrDuplicateTransaction    = errors.New("duplicate transaction")
+	ErrInvalidState            = errors.New("invalid state")
+	ErrInvalidAmount           = errors.New("invalid amount")
+	ErrInvalidSourceType       = errors.New("invalid source type")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrTransactionNotFound     = errors.New("transaction not found")
+	ErrInvalidLimitPeriod      = errors.New("invalid limit period")
+	ErrLossLimitExceeded       = errors.New("loss limit exceeded")
+	ErrAmountOutOfRange        = errors.New("amount out of range")
+	ErrNotPendingReview        = errors.New("transaction is not pending review")
+	ErrTransactionBlocked      = errors.New("transaction blocked by risk rules")
+	ErrNotCancellable          = errors.New("transaction cannot be cancelled")
+	ErrMutationsPaused         = errors.New("balance mutations are paused for user")
+	ErrAdjustmentNotFound      = errors.New("adjustment not found")
+	ErrAdjustmentNotPending    = errors.New("adjustment is not pending")
+	ErrSelfApproval            = errors.New("adjustment must be approved by a different operator")
+	ErrInvalidAdjustmentStatus = errors.New("invalid adjustment status")
+	ErrIdempotencyMismatch     = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyInProgress   = errors.New("request with this idempotency key is in progress")
+	ErrIdempotencyNotFound     = errors.New("idempotency record not found")
+	ErrTransactionMismatch     = errors.New("transaction does not match the stored transaction")
+	ErrTransactionArchived     = errors.New("transaction is archived")
+	ErrArchiveUnavailable      = errors.New("transaction archive is unavailable")
+	ErrWorkerBusy              = errors.New("worker run already in progress")
+	ErrWorkerPaused            = errors.New("worker is paused")
+	ErrInvalidInterval         = errors.New("invalid interval")
+	ErrInvalidCancelReason     = errors.New("invalid cancel reason")
+	ErrInvalidShortfallPolicy  = errors.New("invalid shortfall policy")
+	ErrInvalidBonusSpendOrder  = errors.New("invalid bonus spend order")
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrInvalidPaymentStatus    = errors.New("invalid payment status")
+	ErrPaymentStatusConflict   = errors.New("payment is not in the status required for this change")
+	ErrInvalidSignature        = errors.New("invalid callback signature")
+	ErrUnauthenticated         = errors.New("missing or unknown API key")
+	ErrSourceTypeNotAllowed    = errors.New("source type is not allowed for the tenant")
+	ErrProviderNotAllowed      = errors.New("provider does not belong to the tenant")
+	ErrPlatformOnly            = errors.New("operation is restricted to the platform tenant")
+	ErrInvalidToken            = errors.New("missing or invalid bearer token")
+	ErrForbidden               = errors.New("caller's roles do not allow the operation")
 )
+
+// TransactionMismatchError is returned when a known transaction_id is resent with different fields
+type TransactionMismatchError struct {
+	TransactionID string
+	Mismatches    []FieldMismatch
+}
+
+func (e *TransactionMismatchError) Error() string {
+	fields := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		fields[i] = m.Field
+	}
+	return fmt.Sprintf("%s: transaction %s differs in %s", ErrTransactionMismatch, e.TransactionID, strings.Join(fields, ", "))
+}
+
+func (e *TransactionMismatchError) Unwrap() error {
+	return ErrTransactionMismatch
+}
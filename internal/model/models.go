@@ -7,11 +7,27 @@ import (
 )
 
 type User struct {
-	ID        int64           `json:"id"`
-	Balance   decimal.Decimal `json:"balance"`
-	Version   int             `json:"version"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID int64 `json:"id"`
+	// TenantID is the brand the user belongs to, the tenant's API keys are the only ones that can reach the user
+	TenantID string `json:"tenant_id"`
+	// ProviderID is the game provider the user belongs to, the only one whose tokens may reach the user.
+	// Empty until an operator binds the user, provider tokens cannot reach unbound users.
+	ProviderID string          `json:"provider_id,omitempty"`
+	Balance    decimal.Decimal `json:"balance"`
+	// Debt is owed by the user after a cancellation the balance could not cover, repaid from later wins
+	Debt decimal.Decimal `json:"debt"`
+	// BonusBalance must be wagered: lost transactions reduce BonusWagering and the bonus becomes real money once it
+	// reaches zero. BonusExpiresAt is set while a bonus is active, the expiry job removes it afterwards.
+	BonusBalance   decimal.Decimal `json:"bonus_balance"`
+	BonusWagering  decimal.Decimal `json:"bonus_wagering"`
+	BonusExpiresAt *time.Time      `json:"bonus_expires_at,omitempty"`
+	// Reserved holds the amount of withdrawals in progress, it is no longer part of Balance
+	Reserved decimal.Decimal `json:"reserved"`
+	Version  int             `json:"version"`
+	// MutationsPaused blocks balance changes from transactions and cancellations until an operator resumes the user
+	MutationsPaused bool      `json:"mutations_paused"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 type Transaction struct {
@@ -19,18 +35,66 @@ type Transaction struct {
 	TransactionID string            `json:"transaction_id"`
 	UserID        int64             `json:"user_id"`
 	SourceType    SourceType        `json:"source_type"`
+	ProviderID    string            `json:"provider_id,omitempty"`
 	State         State             `json:"state"`
 	Amount        decimal.Decimal   `json:"amount"`
 	Status        TransactionStatus `json:"status"`
+	ReviewReason  string            `json:"review_reason,omitempty"`
+	ReviewedBy    string            `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time        `json:"reviewed_at,omitempty"`
+	ReviewNote    string            `json:"review_note,omitempty"`
+	RiskDecision  string            `json:"risk_decision,omitempty"`
+	RiskRules     []string          `json:"risk_rules,omitempty"`
 	CancelledAt   *time.Time        `json:"cancelled_at,omitempty"`
-	CreatedAt     time.Time         `json:"created_at"`
-	UpdatedAt     time.Time         `json:"updated_at"`
+	CancelReason  string            `json:"cancel_reason,omitempty"`
+	// CancelPolicy and CancelShortfall are set when the balance could not cover the reversal:
+	// the policy applied and the amount that was missing
+	CancelPolicy    ShortfallPolicy  `json:"cancel_policy,omitempty"`
+	CancelShortfall *decimal.Decimal `json:"cancel_shortfall,omitempty"`
+	// BonusAmount is the part of Amount taken from or credited to the bonus balance
+	BonusAmount decimal.Decimal `json:"bonus_amount"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// TransactionEvent is an audit record of a status change of a transaction, written with the change itself
+type TransactionEvent struct {
+	ID            int64  `json:"id"`
+	TransactionID string `json:"transaction_id"`
+	UserID        int64  `json:"user_id"`
+	// FromStatus is empty for the event creating the transaction
+	FromStatus TransactionStatus `json:"from_status,omitempty"`
+	ToStatus   TransactionStatus `json:"to_status"`
+	ActorType  ActorType         `json:"actor_type" example:"operator"`
+	// Actor is the provider ID, operator or worker name
+	Actor     string `json:"actor,omitempty" example:"alice"`
+	Reason    string `json:"reason" example:"customer_request"`
+	Note      string `json:"note,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Balances are set when the change moved the balance or was made with the user locked
+	BalanceBefore *decimal.Decimal `json:"balance_before,omitempty"`
+	BalanceAfter  *decimal.Decimal `json:"balance_after,omitempty"`
+	// Debts are set when the change created or repaid debt
+	DebtBefore *decimal.Decimal `json:"debt_before,omitempty"`
+	DebtAfter  *decimal.Decimal `json:"debt_after,omitempty"`
+	// Bonuses are set when the change moved the bonus balance
+	BonusBefore *decimal.Decimal `json:"bonus_before,omitempty"`
+	BonusAfter  *decimal.Decimal `json:"bonus_after,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// CancelRequest is an operator's cancellation of a processed transaction
+type CancelRequest struct {
+	Reason string `json:"reason" binding:"required" example:"customer_request" enums:"provider_error,duplicate,fraud,customer_request,other"`
+	Note   string `json:"note" example:"Player disputed the round, ticket #4411"`
 }
 
 type TransactionRequest struct {
 	State         string `json:"state" binding:"required,oneof=win lost" example:"win" enums:"win,lost"`
 	Amount        string `json:"amount" binding:"required" example:"10.15"`
 	TransactionID string `json:"transaction_id" binding:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// ProviderID is taken from the Provider-ID header
+	ProviderID string `json:"-"`
 }
 
 type TransactionResponse struct {
@@ -43,11 +107,76 @@ type ErrorResponse struct {
 	Error   string `json:"error" example:"insufficient balance"`
 	Code    string `json:"code,omitempty" example:"INSUFFICIENT_BALANCE"`
 	Details string `json:"details,omitempty"`
+	// Mismatches lists the fields that differ from the stored transaction for TRANSACTION_MISMATCH
+	Mismatches []FieldMismatch `json:"mismatches,omitempty"`
 }
 
-type BalanceResponse struct {
+// FieldMismatch is a field of a resent transaction that differs from the stored one
+type FieldMismatch struct {
+	Field    string `json:"field" example:"amount"`
+	Stored   string `json:"stored" example:"10.5"`
+	Received string `json:"received" example:"99"`
+}
+
+// BalanceEvent is a balance change pushed to stream subscribers, Version is the user's row version used as event id
+type BalanceEvent struct {
 	UserID  int64  `json:"user_id" example:"1"`
 	Balance string `json:"balance" example:"100.50"`
+	Version int    `json:"version" example:"7"`
+}
+
+type BalanceResponse struct {
+	UserID int64 `json:"user_id" example:"1"`
+	// Balance is real money, Bonus the bonus balance still to be wagered and Total their sum
+	Balance string `json:"balance" example:"100.50"`
+	Bonus   string `json:"bonus" example:"20.00"`
+	Total   string `json:"total" example:"120.50"`
+	// Debt is repaid from the wins of source types in debt mode before they reach the balance
+	Debt string `json:"debt" example:"0.00"`
+	// Reserved is held for withdrawals in progress and not included in Balance
+	Reserved string `json:"reserved" example:"0.00"`
+}
+
+// BonusGrantRequest credits bonus funds to a user, Wagering defaults to the amount times BONUS_WAGERING_MULTIPLIER
+type BonusGrantRequest struct {
+	Amount   string `json:"amount" binding:"required" example:"20.00"`
+	Wagering string `json:"wagering" example:"600.00"`
+	Note     string `json:"note" example:"Welcome bonus"`
+}
+
+// BonusWallet is a user's bonus balance and what is left to wager before it is released
+type BonusWallet struct {
+	UserID    int64           `json:"user_id" example:"1"`
+	Balance   decimal.Decimal `json:"balance"`
+	Wagering  decimal.Decimal `json:"wagering"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+}
+
+// BonusEntry records a grant or expiry of bonus funds
+type BonusEntry struct {
+	ID       int64           `json:"id"`
+	UserID   int64           `json:"user_id"`
+	Kind     BonusEntryKind  `json:"kind" example:"granted"`
+	Amount   decimal.Decimal `json:"amount"`
+	Wagering decimal.Decimal `json:"wagering"`
+	// Actor is the granting operator, or the expiry job
+	Actor     string     `json:"actor" example:"alice"`
+	Note      string     `json:"note,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type BonusEntryListResponse struct {
+	Entries []*BonusEntry `json:"entries"`
+	Total   int           `json:"total"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+}
+
+// BonusExpiryResult is the outcome of an expiry run
+type BonusExpiryResult struct {
+	Expired int             `json:"expired"`
+	Amount  decimal.Decimal `json:"amount"`
 }
 
 type TransactionListResponse struct {
@@ -56,3 +185,260 @@ type TransactionListResponse struct {
 	Limit        int            `json:"limit"`
 	Offset       int            `json:"offset"`
 }
+
+type ReviewDecisionRequest struct {
+	Note string `json:"note" example:"Verified with provider"`
+}
+
+// LossLimit is a responsible-gaming limit on a user's net loss within a period window.
+// Increases are staged in PendingAmount until PendingEffectiveAt passes.
+type LossLimit struct {
+	UserID             int64            `json:"user_id"`
+	Period             LimitPeriod      `json:"period"`
+	Amount             decimal.Decimal  `json:"amount"`
+	PendingAmount      *decimal.Decimal `json:"pending_amount,omitempty"`
+	PendingEffectiveAt *time.Time       `json:"pending_effective_at,omitempty"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+}
+
+// EffectiveAmount returns the limit in force at the given time
+func (l *LossLimit) EffectiveAmount(now time.Time) decimal.Decimal {
+	if l.PendingAmount != nil && l.PendingEffectiveAt != nil && !now.Before(*l.PendingEffectiveAt) {
+		return *l.PendingAmount
+	}
+	return l.Amount
+}
+
+// LossCounter is the aggregated net loss (lost minus win) of a user in one period window
+type LossCounter struct {
+	UserID      int64           `json:"user_id"`
+	Period      LimitPeriod     `json:"period"`
+	WindowStart time.Time       `json:"window_start"`
+	NetLoss     decimal.Decimal `json:"net_loss"`
+}
+
+type LossLimitRequest struct {
+	Period string `json:"period" binding:"required,oneof=daily weekly monthly" example:"daily" enums:"daily,weekly,monthly"`
+	Amount string `json:"amount" binding:"required" example:"100.00"`
+}
+
+type LossLimitResponse struct {
+	Period             string     `json:"period" example:"daily"`
+	Amount             string     `json:"amount" example:"100.00"`
+	PendingAmount      string     `json:"pending_amount,omitempty" example:"200.00"`
+	PendingEffectiveAt *time.Time `json:"pending_effective_at,omitempty"`
+	NetLoss            string     `json:"net_loss" example:"25.00"`
+	Remaining          string     `json:"remaining" example:"75.00"`
+}
+
+type LossLimitsResponse struct {
+	UserID int64               `json:"user_id" example:"1"`
+	Limits []LossLimitResponse `json:"limits"`
+}
+
+// BalanceAdjustment is a manual credit (positive amount) or debit (negative amount) requested by one operator
+// and applied once a second operator approves it
+type BalanceAdjustment struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"user_id"`
+	Amount    decimal.Decimal  `json:"amount"`
+	Reason    string           `json:"reason"`
+	Status    AdjustmentStatus `json:"status"`
+	CreatedBy string           `json:"created_by"`
+	// TransactionID is the id of the 'adjustment' transaction recorded on approval
+	TransactionID string     `json:"transaction_id"`
+	DecidedBy     string     `json:"decided_by,omitempty"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+	DecisionNote  string     `json:"decision_note,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type AdjustmentRequest struct {
+	UserID int64  `json:"user_id" binding:"required" example:"1"`
+	Amount string `json:"amount" binding:"required" example:"-25.00"`
+	Reason string `json:"reason" binding:"required" example:"Goodwill credit for outage"`
+}
+
+type AdjustmentListResponse struct {
+	Adjustments []*BalanceAdjustment `json:"adjustments"`
+	Total       int                  `json:"total"`
+	Limit       int                  `json:"limit"`
+	Offset      int                  `json:"offset"`
+}
+
+// Payment is a deposit or withdrawal, approved by an operator and completed or failed by the payment provider
+type Payment struct {
+	ID     int64           `json:"id"`
+	UserID int64           `json:"user_id"`
+	Kind   PaymentKind     `json:"kind" example:"withdrawal"`
+	Amount decimal.Decimal `json:"amount"`
+	Status PaymentStatus   `json:"status" example:"pending"`
+	// TransactionID is the id of the 'payment' transaction recorded on completion
+	TransactionID string `json:"transaction_id"`
+	// Provider and ProviderReference identify the payment at the provider once it is submitted
+	Provider          string `json:"provider,omitempty" example:"stub"`
+	ProviderReference string `json:"provider_reference,omitempty"`
+	// DecidedBy is the operator who approved or rejected the payment
+	DecidedBy     string     `json:"decided_by,omitempty"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+type PaymentRequest struct {
+	Amount string `json:"amount" binding:"required" example:"50.00"`
+}
+
+// UserProviderRequest binds a user to the game provider it belongs to
+type UserProviderRequest struct {
+	ProviderID string `json:"provider_id" binding:"required,max=100" example:"acme"`
+}
+
+// PaymentRejectRequest is an operator's rejection of a pending payment
+type PaymentRejectRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Account under KYC review"`
+}
+
+// PaymentCallback is the payment provider's report of the outcome of a submitted payment
+type PaymentCallback struct {
+	Reference string `json:"reference" binding:"required" example:"stub-6f1c2a"`
+	Status    string `json:"status" binding:"required,oneof=completed failed" example:"completed" enums:"completed,failed"`
+	Reason    string `json:"reason" example:"card declined"`
+}
+
+type PaymentListResponse struct {
+	Payments []*Payment `json:"payments"`
+	Total    int        `json:"total"`
+	Limit    int        `json:"limit"`
+	Offset   int        `json:"offset"`
+}
+
+// TransactionPartition is a monthly partition of the transactions table.
+// RangeStart is nil for the partition holding everything before partitioning was introduced.
+type TransactionPartition struct {
+	Name        string     `json:"name" example:"transactions_p2026_10"`
+	RangeStart  *time.Time `json:"range_start,omitempty"`
+	RangeEnd    time.Time  `json:"range_end"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	ArchivePath string     `json:"archive_path,omitempty"`
+	RowCount    int64      `json:"row_count,omitempty"`
+}
+
+// BalanceDrift is a user whose stored balance differs from the one recomputed from transaction history.
+// Balance and Expected are net of debt.
+type BalanceDrift struct {
+	UserID   int64           `json:"user_id"`
+	Balance  decimal.Decimal `json:"balance"`
+	Expected decimal.Decimal `json:"expected"`
+	Drift    decimal.Decimal `json:"drift"`
+}
+
+// IntegrityReport is the result of a balance integrity check
+type IntegrityReport struct {
+	CheckedAt time.Time       `json:"checked_at"`
+	Drifts    []*BalanceDrift `json:"drifts"`
+	// TotalDrift is the sum of absolute drifts
+	TotalDrift  string  `json:"total_drift"`
+	PausedUsers []int64 `json:"paused_users"`
+}
+
+// IdempotencyRecord is the stored outcome of a mutating request, replayed when the same key is retried.
+// A record without StatusCode is reserved by a request that is still in progress.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// CancellationResult counts the outcome of an automatic cancellation run
+type CancellationResult struct {
+	Requested int `json:"requested"`
+	Cancelled int `json:"cancelled"`
+	// Shortfalls were cancelled although the balance could not cover the reversal (partial or negative balance)
+	Shortfalls int `json:"shortfalls"`
+	// CancellationFailed could not be reversed because of the balance and were marked cancellation_failed
+	CancellationFailed int `json:"cancellation_failed"`
+	// Skipped were claimed by another run, or could not be cancelled because of paused mutations
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// WorkerState is the runtime state of a background worker set by operators
+type WorkerState struct {
+	Name      string
+	Paused    bool
+	UpdatedBy string
+	UpdatedAt time.Time
+	// Interval replaces the configured schedule on every instance, zero keeps the configured schedule
+	Interval time.Duration
+}
+
+// WorkerStatus reports the state and last run of a background worker, the last run is the latest recorded
+// by any instance.
+type WorkerStatus struct {
+	Name     string `json:"name"`
+	Paused   bool   `json:"paused"`
+	PausedBy string `json:"paused_by,omitempty"`
+	// Interval is the duration between runs, or the cron expression when the worker runs on one
+	Interval string `json:"interval"`
+	// Leader is true if scheduled runs happen on this instance
+	Leader bool `json:"leader"`
+	// Running is true while a run is in progress on any instance
+	Running         bool                `json:"running"`
+	LastRunAt       *time.Time          `json:"last_run_at,omitempty"`
+	LastRunDuration string              `json:"last_run_duration,omitempty"`
+	LastResult      *CancellationResult `json:"last_result,omitempty"`
+	LastError       string              `json:"last_error,omitempty"`
+	NextRunAt       *time.Time          `json:"next_run_at,omitempty"`
+}
+
+type WorkerIntervalRequest struct {
+	// Interval is a Go duration, e.g. "30s" or "5m"
+	Interval string `json:"interval" binding:"required" example:"5m"`
+}
+
+type HealthResponse struct {
+	Status string `json:"status"`
+	// Leader is set when leader election runs, true if this instance runs scheduled background jobs
+	Leader *bool `json:"leader,omitempty"`
+}
+
+// JobRun is one run of a background job, Result is job specific (e.g. CancellationResult)
+type JobRun struct {
+	ID int64 `json:"id"`
+	// Trigger is "schedule" or "manual"
+	Trigger    string       `json:"trigger"`
+	Job        string       `json:"job"`
+	Instance   string       `json:"instance"`
+	Status     JobRunStatus `json:"status"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Result     any          `json:"result,omitempty"`
+}
+
+// PoolStats is the usage of a database connection pool
+type PoolStats struct {
+	Acquired int32
+	Idle     int32
+	Total    int32
+	Max      int32
+}
+
+// ReadinessResponse reports whether the instance should receive traffic and the result of each check
+type ReadinessResponse struct {
+	Status string                    `json:"status" example:"ok"`
+	Checks map[string]ReadinessCheck `json:"checks"`
+}
+
+type ReadinessCheck struct {
+	Status  string `json:"status" example:"ok"`
+	Message string `json:"message,omitempty"`
+}
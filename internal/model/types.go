@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type State string
 
 const (
@@ -7,12 +9,21 @@ const (
 	StateLost State = "lost"
 )
 
+// DefaultTenant owns every user of a deployment without a tenants file, and in one with a tenants file the
+// platform-wide operations such as partitions and worker control
+const DefaultTenant = "default"
+
 type SourceType string
 
 const (
 	SourceGame    SourceType = "game"
 	SourceServer  SourceType = "server"
 	SourcePayment SourceType = "payment"
+	// SourceAdjustment marks manual balance corrections made by operators, never accepted from providers
+	SourceAdjustment SourceType = "adjustment"
+	// SourceSettlement marks deposits and withdrawals settled by the payment provider, never accepted from providers
+	// and never cancelled: the money has already moved
+	SourceSettlement SourceType = "settlement"
 )
 
 type TransactionStatus string
@@ -20,8 +31,129 @@ type TransactionStatus string
 const (
 	StatusProcessed TransactionStatus = "processed"
 	StatusCancelled TransactionStatus = "cancelled"
+	// StatusPendingReview transactions are stored but not applied to the balance
+	StatusPendingReview TransactionStatus = "pending_review"
+	// StatusRejected transactions were declined during review and never applied
+	StatusRejected TransactionStatus = "rejected"
+	// StatusBlocked transactions were refused by fraud rules and never applied, they are kept for review of the decision
+	StatusBlocked TransactionStatus = "blocked"
+	// StatusCancellationFailed transactions could not be reversed by the cancellation worker and stay applied
+	StatusCancellationFailed TransactionStatus = "cancellation_failed"
+)
+
+// AdjustmentStatus tracks the maker-checker workflow of a balance adjustment
+type AdjustmentStatus string
+
+const (
+	// AdjustmentPending adjustments wait for a second operator and do not affect the balance yet
+	AdjustmentPending  AdjustmentStatus = "pending"
+	AdjustmentApproved AdjustmentStatus = "approved"
+	AdjustmentRejected AdjustmentStatus = "rejected"
+)
+
+// PaymentKind is the direction of a payment: deposits credit the balance, withdrawals debit it
+type PaymentKind string
+
+const (
+	PaymentDeposit    PaymentKind = "deposit"
+	PaymentWithdrawal PaymentKind = "withdrawal"
+)
+
+// PaymentStatus tracks a payment from the request to the payment provider's callback
+type PaymentStatus string
+
+const (
+	// PaymentPending payments wait for an operator, withdrawals already hold their amount in the reserved funds
+	PaymentPending PaymentStatus = "pending"
+	// PaymentSubmitting payments were approved by an operator and are being submitted to the payment provider,
+	// approving them again retries the submission
+	PaymentSubmitting PaymentStatus = "submitting"
+	// PaymentApproved payments were submitted to the payment provider and wait for its callback
+	PaymentApproved  PaymentStatus = "approved"
+	PaymentCompleted PaymentStatus = "completed"
+	// PaymentFailed payments were rejected by an operator or the provider, reserved funds are back in the balance
+	PaymentFailed PaymentStatus = "failed"
+)
+
+// Reasons a transaction is held for manual review
+const (
+	ReviewReasonMaxAutoWin = "max_auto_win_exceeded"
+	ReviewReasonRiskRules  = "risk_rules"
+)
+
+// ActorType is the kind of caller behind a transaction event
+type ActorType string
+
+const (
+	ActorProvider ActorType = "provider"
+	ActorOperator ActorType = "operator"
+	ActorWorker   ActorType = "worker"
 )
 
+// Reason codes of transaction events. Holds for review use the review reasons, operator cancellations a CancelReason.
+const (
+	EventReasonProcessed      = "processed"
+	EventReasonAdjustment     = "adjustment"
+	EventReasonReviewApproved = "review_approved"
+	EventReasonReviewRejected = "review_rejected"
+	EventReasonDeposit        = "deposit"
+	EventReasonWithdrawal     = "withdrawal"
+	EventReasonRiskBlocked    = "risk_blocked"
+	// EventReasonOddRecord is the automatic cancellation of odd-numbered transactions
+	EventReasonOddRecord = "odd_record"
+)
+
+// CancelReason is the reason code an operator gives when cancelling a transaction
+type CancelReason string
+
+const (
+	CancelReasonProviderError   CancelReason = "provider_error"
+	CancelReasonDuplicate       CancelReason = "duplicate"
+	CancelReasonFraud           CancelReason = "fraud"
+	CancelReasonCustomerRequest CancelReason = "customer_request"
+	CancelReasonOther           CancelReason = "other"
+)
+
+// ShortfallPolicy decides what an automatic cancellation does when the balance cannot cover the reversal
+type ShortfallPolicy string
+
+const (
+	// ShortfallSkip leaves the balance unchanged and marks the transaction cancellation_failed
+	ShortfallSkip ShortfallPolicy = "skip"
+	// ShortfallDebt reverses the full amount, the part the balance cannot cover becomes the user's debt.
+	// Source types not in debt mode fall back to ShortfallSkip.
+	ShortfallDebt ShortfallPolicy = "debt"
+	// ShortfallPartial reverses what the balance covers and records the rest as the shortfall
+	ShortfallPartial ShortfallPolicy = "partial"
+)
+
+// BonusSpendOrder decides which balance a lost transaction is taken from first
+type BonusSpendOrder string
+
+const (
+	BonusSpendRealFirst  BonusSpendOrder = "real_first"
+	BonusSpendBonusFirst BonusSpendOrder = "bonus_first"
+)
+
+// BonusEntryKind is a change of the bonus balance outside of transactions
+type BonusEntryKind string
+
+const (
+	BonusGranted BonusEntryKind = "granted"
+	BonusExpired BonusEntryKind = "expired"
+)
+
+type LimitPeriod string
+
+const (
+	PeriodDaily   LimitPeriod = "daily"
+	PeriodWeekly  LimitPeriod = "weekly"
+	PeriodMonthly LimitPeriod = "monthly"
+)
+
+// LimitPeriods lists every period a loss limit can be configured for
+var LimitPeriods = []LimitPeriod{PeriodDaily, PeriodWeekly, PeriodMonthly}
+
 func ParseSourceType(s string) (SourceType, error) {
 	switch s {
 	case string(SourceGame):
@@ -53,3 +185,95 @@ func ParseState(s string) (State, error) {
 func (s State) String() string {
 	return string(s)
 }
+
+func ParseLimitPeriod(s string) (LimitPeriod, error) {
+	switch s {
+	case string(PeriodDaily):
+		return PeriodDaily, nil
+	case string(PeriodWeekly):
+		return PeriodWeekly, nil
+	case string(PeriodMonthly):
+		return PeriodMonthly, nil
+	default:
+		return "", ErrInvalidLimitPeriod
+	}
+}
+
+func (p LimitPeriod) String() string {
+	return string(p)
+}
+
+// WindowStart returns the UTC start of the period window containing t.
+// Weeks start on Monday.
+func (p LimitPeriod) WindowStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case PeriodWeekly:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case PeriodMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func ParseAdjustmentStatus(s string) (AdjustmentStatus, error) {
+	switch s {
+	case string(AdjustmentPending):
+		return AdjustmentPending, nil
+	case string(AdjustmentApproved):
+		return AdjustmentApproved, nil
+	case string(AdjustmentRejected):
+		return AdjustmentRejected, nil
+	default:
+		return "", ErrInvalidAdjustmentStatus
+	}
+}
+
+func ParsePaymentStatus(s string) (PaymentStatus, error) {
+	switch PaymentStatus(s) {
+	case PaymentPending, PaymentSubmitting, PaymentApproved, PaymentCompleted, PaymentFailed:
+		return PaymentStatus(s), nil
+	default:
+		return "", ErrInvalidPaymentStatus
+	}
+}
+
+func ParseCancelReason(s string) (CancelReason, error) {
+	switch CancelReason(s) {
+	case CancelReasonProviderError, CancelReasonDuplicate, CancelReasonFraud, CancelReasonCustomerRequest, CancelReasonOther:
+		return CancelReason(s), nil
+	default:
+		return "", ErrInvalidCancelReason
+	}
+}
+
+func ParseShortfallPolicy(s string) (ShortfallPolicy, error) {
+	switch ShortfallPolicy(s) {
+	case ShortfallSkip, ShortfallDebt, ShortfallPartial:
+		return ShortfallPolicy(s), nil
+	default:
+		return "", ErrInvalidShortfallPolicy
+	}
+}
+
+// JobRunStatus is the outcome of a scheduled background job run
+type JobRunStatus string
+
+const (
+	// JobRunRunning runs did not finish yet, or the instance stopped while they ran
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+func ParseBonusSpendOrder(s string) (BonusSpendOrder, error) {
+	switch BonusSpendOrder(s) {
+	case BonusSpendRealFirst, BonusSpendBonusFirst:
+		return BonusSpendOrder(s), nil
+	default:
+		return "", ErrInvalidBonusSpendOrder
+	}
+}
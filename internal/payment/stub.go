@@ -0,0 +1,55 @@
+// Package payment holds the integrations with payment providers that deposits and withdrawals are submitted to.
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"transaction-processor/internal/model"
+)
+
+// StubName is the provider name of the stand-in, recorded on its payments
+const StubName = "stub"
+
+// StubProvider stands in for a real payment provider: it accepts every payment it is given and expects the outcome
+// to be reported by a callback signed with the shared secret, see Sign
+type StubProvider struct {
+	secret []byte
+}
+
+func NewStubProvider(secret string) *StubProvider {
+	return &StubProvider{secret: []byte(secret)}
+}
+
+func (p *StubProvider) Name() string {
+	return StubName
+}
+
+// Submit accepts the payment and returns the reference its callback will carry, the same for every submission of it
+func (p *StubProvider) Submit(_ context.Context, payment *model.Payment) (string, error) {
+	return "stub-" + payment.TransactionID, nil
+}
+
+// VerifyCallback checks the signature of a callback body, every callback is refused while no secret is configured
+func (p *StubProvider) VerifyCallback(body []byte, signature string) error {
+	if len(p.secret) == 0 {
+		return model.ErrInvalidSignature
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, mac(p.secret, body)) {
+		return model.ErrInvalidSignature
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of a callback body, sent in the X-Payment-Signature header
+func Sign(secret string, body []byte) string {
+	return hex.EncodeToString(mac([]byte(secret), body))
+}
+
+func mac(secret, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,42 @@
+package payment
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"transaction-processor/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStubProvider_Submit(t *testing.T) {
+	provider := NewStubProvider("secret")
+
+	payment := &model.Payment{ID: 1, TransactionID: "550e8400-e29b-41d4-a716-446655440000"}
+	ref, err := provider.Submit(context.Background(), payment)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ref, "stub-"))
+	assert.Equal(t, StubName, provider.Name())
+
+	// Resubmissions are idempotent
+	again, err := provider.Submit(context.Background(), payment)
+	require.NoError(t, err)
+	assert.Equal(t, ref, again)
+}
+
+func TestStubProvider_VerifyCallback(t *testing.T) {
+	body := []byte(`{"reference":"stub-1","status":"completed"}`)
+	provider := NewStubProvider("secret")
+
+	assert.NoError(t, provider.VerifyCallback(body, Sign("secret", body)))
+	assert.ErrorIs(t, provider.VerifyCallback(body, Sign("other", body)), model.ErrInvalidSignature)
+	assert.ErrorIs(t, provider.VerifyCallback([]byte(`{"reference":"stub-1","status":"failed"}`), Sign("secret", body)), model.ErrInvalidSignature)
+	assert.ErrorIs(t, provider.VerifyCallback(body, "not-hex"), model.ErrInvalidSignature)
+}
+
+func TestStubProvider_VerifyCallback_RefusesWithoutSecret(t *testing.T) {
+	body := []byte(`{}`)
+
+	assert.ErrorIs(t, NewStubProvider("").VerifyCallback(body, Sign("", body)), model.ErrInvalidSignature)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import "context"
+
+type readYourWritesKey struct{}
+
+// WithReadYourWrites marks reads made with the returned context to go to the primary,
+// for callers that must see their own writes which the read replica may not have replayed yet
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, true)
+}
+
+// ReadYourWrites reports whether reads must go to the primary
+func ReadYourWrites(ctx context.Context) bool {
+	v, _ := ctx.Value(readYourWritesKey{}).(bool)
+	return v
+}
+
+type requestIDKey struct{}
+
+// WithRequestID attaches the ID of the API request to the context, it is recorded with the changes the request makes
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the API request, empty outside requests
+func RequestID(ctx context.Context) string {
+	v, _ := ctx.Value(requestIDKey{}).(string)
+	return v
+}
+
+type tenantKey struct{}
+
+// WithTenant scopes the repository queries made with the returned context to one tenant
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantID returns the tenant the queries are scoped to, empty for background jobs and tools which see every tenant
+func TenantID(ctx context.Context) string {
+	v, _ := ctx.Value(tenantKey{}).(string)
+	return v
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 	"transaction-processor/internal/model"
 
 	"github.com/jackc/pgx/v5"
@@ -22,8 +23,30 @@ type UserRepository interface {
 	// GetBalance get the current balance for a user (read-only)
 	GetBalance(ctx context.Context, userID int64, tx ...pgx.Tx) (decimal.Decimal, error)
 
+	// GetUser retrieves a user without locking (read-only)
+	GetUser(ctx context.Context, userID int64, tx ...pgx.Tx) (*model.User, error)
+
+	// SetProvider sets the provider a user belongs to, see model.User.ProviderID
+	SetProvider(ctx context.Context, userID int64, providerID string) error
+
 	// UpdateBalance update user balance
 	UpdateBalance(ctx context.Context, userID int64, balance decimal.Decimal, tx pgx.Tx) error
+
+	// UpdateDebt update user debt, in the transaction updating the balance
+	UpdateDebt(ctx context.Context, userID int64, debt decimal.Decimal, tx pgx.Tx) error
+
+	// UpdateBonus update the user's bonus balance, remaining wagering and expiry
+	UpdateBonus(ctx context.Context, userID int64, bonus, wagering decimal.Decimal, expiresAt *time.Time, tx pgx.Tx) error
+
+	// UpdateReserved update the funds held for withdrawals in progress, in the transaction updating the balance
+	UpdateReserved(ctx context.Context, userID int64, reserved decimal.Decimal, tx pgx.Tx) error
+
+	// GetBalanceDrifts recomputes every balance plus bonus and reserved funds net of debt from the opening balance, archived totals,
+	// processed transactions and bonus entries, returning the users whose stored total differs
+	GetBalanceDrifts(ctx context.Context) ([]*model.BalanceDrift, error)
+
+	// SetMutationsPaused pauses or resumes balance mutations for the given users, returning the ids that changed
+	SetMutationsPaused(ctx context.Context, userIDs []int64, paused bool) ([]int64, error)
 }
 
 // TransactionRepository defines operations for transaction management
@@ -40,9 +63,190 @@ type TransactionRepository interface {
 	// GetLatestOddProcessedTransactions retrieves latest odd-numbered processed transactions
 	GetLatestOddProcessedTransactions(ctx context.Context, limit int) ([]*model.Transaction, error)
 
-	// CancelTransactionIfProcessed cancels a transaction if status is processed
-	CancelTransactionIfProcessed(ctx context.Context, id int64, tx pgx.Tx) (bool, error)
+	// CancelTransactionIfProcessed cancels a transaction with a reason code if status is processed or cancellation_failed.
+	// The policy and shortfall are recorded when the balance could not cover the reversal.
+	CancelTransactionIfProcessed(ctx context.Context, id int64, reason string, policy model.ShortfallPolicy, shortfall *decimal.Decimal, tx pgx.Tx) (bool, error)
+
+	// MarkCancellationFailed records a cancellation the balance could not cover if status is processed
+	MarkCancellationFailed(ctx context.Context, id int64, reason string, shortfall decimal.Decimal, tx pgx.Tx) (bool, error)
+
+	// GetCancellationShortfalls retrieves transactions whose cancellation failed or left a shortfall, newest first
+	GetCancellationShortfalls(ctx context.Context, limit, offset int) ([]*model.Transaction, error)
 
 	// LockTransactionForCancellation locks a transaction row for cancellation if it's still processed
 	LockTransactionForCancellation(ctx context.Context, id int64, tx pgx.Tx) (bool, error)
+
+	// GetPendingReviewTransactions retrieves transactions waiting for manual review, oldest first
+	GetPendingReviewTransactions(ctx context.Context, limit, offset int) ([]*model.Transaction, error)
+
+	// GetTransactionForUpdate retrieves a transaction by its transaction ID with row-level lock (must be in transaction)
+	GetTransactionForUpdate(ctx context.Context, transactionID string, tx pgx.Tx) (*model.Transaction, error)
+
+	// CompleteReview records a review decision if the transaction is still pending review,
+	// with the part of an approved amount taken from or credited to the bonus balance
+	CompleteReview(ctx context.Context, id int64, status model.TransactionStatus, reviewer, note string, bonusAmount decimal.Decimal, tx pgx.Tx) (bool, error)
+
+	// GetRecentTransactionsByUser retrieves a user's transactions created since the given time, newest first,
+	// without rejected and blocked ones
+	GetRecentTransactionsByUser(ctx context.Context, userID int64, since time.Time, tx ...pgx.Tx) ([]*model.Transaction, error)
+
+	// GetWinStats returns the average amount and number of a user's latest processed game and server wins,
+	// at most limit of them
+	GetWinStats(ctx context.Context, userID int64, limit int, tx ...pgx.Tx) (decimal.Decimal, int, error)
+
+	// InsertTransactionEvent records a status change of a transaction (must be in the transaction making the change)
+	InsertTransactionEvent(ctx context.Context, event *model.TransactionEvent, tx pgx.Tx) error
+
+	// GetTransactionEvents retrieves the status changes of a transaction, oldest first
+	GetTransactionEvents(ctx context.Context, transactionID string) ([]*model.TransactionEvent, error)
+}
+
+// LimitRepository defines operations for responsible-gaming loss limits
+type LimitRepository interface {
+	// GetLossLimits retrieves all loss limits configured for a user
+	GetLossLimits(ctx context.Context, userID int64, tx ...pgx.Tx) ([]*model.LossLimit, error)
+
+	// UpsertLossLimit creates or replaces the loss limit of a user for a period
+	UpsertLossLimit(ctx context.Context, limit *model.LossLimit, tx pgx.Tx) error
+
+	// GetLossCounters retrieves the aggregated net loss counters of a user
+	GetLossCounters(ctx context.Context, userID int64, tx ...pgx.Tx) ([]*model.LossCounter, error)
+
+	// AddNetLoss adds delta to the user's counters of every period window containing at
+	AddNetLoss(ctx context.Context, userID int64, delta decimal.Decimal, at time.Time, tx pgx.Tx) error
+}
+
+// AdjustmentRepository defines operations for manual balance adjustments
+type AdjustmentRepository interface {
+	// InsertAdjustment creates a new balance adjustment record
+	InsertAdjustment(ctx context.Context, adj *model.BalanceAdjustment, tx ...pgx.Tx) error
+
+	// GetAdjustmentForUpdate retrieves an adjustment with row-level lock (must be in transaction)
+	GetAdjustmentForUpdate(ctx context.Context, id int64, tx pgx.Tx) (*model.BalanceAdjustment, error)
+
+	// GetAdjustments lists adjustments with the given status, oldest first
+	GetAdjustments(ctx context.Context, status model.AdjustmentStatus, limit, offset int) ([]*model.BalanceAdjustment, error)
+
+	// CompleteAdjustment records the decision on a pending adjustment, returns false if it was no longer pending
+	CompleteAdjustment(ctx context.Context, adj *model.BalanceAdjustment, tx pgx.Tx) (bool, error)
+}
+
+// BonusRepository records bonus grants and expirations
+type BonusRepository interface {
+	// InsertBonusEntry records a grant (must be in the transaction crediting it)
+	InsertBonusEntry(ctx context.Context, entry *model.BonusEntry, tx pgx.Tx) error
+
+	// GetBonusEntries retrieves a user's grants and expirations, newest first
+	GetBonusEntries(ctx context.Context, userID int64, limit, offset int) ([]*model.BonusEntry, error)
+
+	// ExpireBonuses removes up to limit bonuses that expired before now, skipping paused and locked users,
+	// and returns the recorded expirations
+	ExpireBonuses(ctx context.Context, now time.Time, limit int) ([]*model.BonusEntry, error)
+}
+
+// PaymentRepository defines operations for deposits and withdrawals
+type PaymentRepository interface {
+	// InsertPayment creates a new payment record
+	InsertPayment(ctx context.Context, payment *model.Payment, tx ...pgx.Tx) error
+
+	// GetPayment retrieves a payment by its ID
+	GetPayment(ctx context.Context, id int64) (*model.Payment, error)
+
+	// GetPaymentForUpdate retrieves a payment with row-level lock (must be in transaction)
+	GetPaymentForUpdate(ctx context.Context, id int64, tx pgx.Tx) (*model.Payment, error)
+
+	// GetPaymentByReferenceForUpdate retrieves a payment by the provider's reference with row-level lock
+	GetPaymentByReferenceForUpdate(ctx context.Context, provider, reference string, tx pgx.Tx) (*model.Payment, error)
+
+	// GetPayments lists payments with the given status, oldest first
+	GetPayments(ctx context.Context, status model.PaymentStatus, limit, offset int) ([]*model.Payment, error)
+
+	// GetPaymentsByUser lists a user's payments, newest first
+	GetPaymentsByUser(ctx context.Context, userID int64, limit, offset int) ([]*model.Payment, error)
+
+	// UpdatePayment stores the status, provider details and failure reason of a payment if it still has status from,
+	// returns false otherwise
+	UpdatePayment(ctx context.Context, payment *model.Payment, from model.PaymentStatus, tx pgx.Tx) (bool, error)
+}
+
+// BalanceListener receives balance changes committed by any API replica
+type BalanceListener interface {
+	// Listen calls ready once listening has started and fn for every balance change,
+	// until the context is cancelled or the connection fails
+	Listen(ctx context.Context, ready func(), fn func(*model.BalanceEvent)) error
+}
+
+// IdempotencyRepository stores responses of mutating requests so retries can be replayed
+type IdempotencyRepository interface {
+	// GetIdempotencyRecord retrieves a record that has not expired yet
+	GetIdempotencyRecord(ctx context.Context, scope, key string, tx ...pgx.Tx) (*model.IdempotencyRecord, error)
+
+	// SaveIdempotencyRecord inserts a record, or replaces it if the stored one reserved the key or expired
+	SaveIdempotencyRecord(ctx context.Context, rec *model.IdempotencyRecord, tx ...pgx.Tx) error
+
+	// ReserveIdempotencyKey claims a key for a request in progress, returns false if it is already used
+	ReserveIdempotencyKey(ctx context.Context, scope, key, fingerprint string, expiresAt time.Time) (bool, error)
+
+	// DeleteIdempotencyKey removes a record, letting the key be used again
+	DeleteIdempotencyKey(ctx context.Context, scope, key string) error
+
+	// DeleteExpiredIdempotencyKeys removes expired records, returning how many were deleted
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+}
+
+// PartitionRepository manages the monthly partitions of the transactions table
+type PartitionRepository interface {
+	// GetPartitions retrieves all partitions recorded by the app, archived ones included, oldest first
+	GetPartitions(ctx context.Context) ([]*model.TransactionPartition, error)
+
+	// CreatePartition creates and records the partition for [from, to), no-op if it already exists
+	CreatePartition(ctx context.Context, name string, from, to time.Time) error
+
+	// HasPendingReview reports whether a partition holds transactions waiting for manual review
+	HasPendingReview(ctx context.Context, name string, tx pgx.Tx) (bool, error)
+
+	// ExportPartition locks a partition against writes and passes each of its transactions to fn (must be in transaction)
+	ExportPartition(ctx context.Context, name string, fn func(*model.Transaction) error, tx pgx.Tx) error
+
+	// ArchivePartition points the transaction index at the archive file, moves the processed totals of the partition
+	// into users.archived_net, then detaches and drops it (must be in transaction)
+	ArchivePartition(ctx context.Context, name, archivePath string, rowCount int64, tx pgx.Tx) error
+}
+
+// WorkerStateRepository stores the runtime state of background workers
+type WorkerStateRepository interface {
+	// GetWorkerState retrieves the state of a worker, a worker without stored state is not paused
+	GetWorkerState(ctx context.Context, name string) (*model.WorkerState, error)
+
+	// SetWorkerPaused pauses or resumes a worker
+	SetWorkerPaused(ctx context.Context, name string, paused bool, operator string) error
+	// SetWorkerInterval stores the interval between runs of a worker, zero restores the configured schedule
+	SetWorkerInterval(ctx context.Context, name string, interval time.Duration) error
+}
+
+// HealthRepository reports the state of the database for readiness checks
+type HealthRepository interface {
+	// Ping checks that a connection can be acquired and answers
+	Ping(ctx context.Context) error
+
+	// PoolStats returns the usage of the connection pool
+	PoolStats() model.PoolStats
+
+	// SchemaVersion returns the latest applied migration, 0 if none is recorded
+	SchemaVersion(ctx context.Context) (int, error)
+}
+
+// JobRunRepository stores the run history of background jobs
+type JobRunRepository interface {
+	// CreateJobRun records the start of a run and sets its ID
+	CreateJobRun(ctx context.Context, run *model.JobRun) error
+
+	// FinishJobRun records the status, error and result of a run
+	FinishJobRun(ctx context.Context, run *model.JobRun) error
+
+	// GetJobRuns retrieves the latest runs, of one job if job is not empty, newest first
+	GetJobRuns(ctx context.Context, job string, limit int) ([]*model.JobRun, error)
+
+	// DeleteJobRunsBefore removes runs of a job started before the given time, returning how many were deleted
+	DeleteJobRunsBefore(ctx context.Context, job string, before time.Time) (int64, error)
 }
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.AdjustmentRepository = (*AdjustmentRepositoryImpl)(nil)
+
+// AdjustmentRepositoryImpl is the PostgreSQL implementation of AdjustmentRepository
+type AdjustmentRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewAdjustmentRepository(pool *pgxpool.Pool) repository.AdjustmentRepository {
+	return &AdjustmentRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+const adjustmentColumns = `id, user_id, amount, reason, status, created_by, transaction_id,
+        COALESCE(decided_by, ''), decided_at, COALESCE(decision_note, ''), created_at`
+
+// InsertAdjustment creates a new balance adjustment record
+func (r *AdjustmentRepositoryImpl) InsertAdjustment(ctx context.Context, adj *model.BalanceAdjustment, tx ...pgx.Tx) error {
+	query := `
+        INSERT INTO balance_adjustments (user_id, amount, reason, status, created_by, transaction_id)
+        SELECT id, $2, $3, $4, $5, $6
+        FROM users
+        WHERE id = $1 AND ($7 = '' OR tenant_id = $7)
+        RETURNING id, created_at`
+
+	executor := r.getExecutor(tx...)
+	err := executor.QueryRow(ctx, query, adj.UserID, adj.Amount, adj.Reason, string(adj.Status), adj.CreatedBy, adj.TransactionID, tenantOf(ctx)).
+		Scan(&adj.ID, &adj.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to insert balance adjustment: %w", err)
+	}
+	return nil
+}
+
+// GetAdjustmentForUpdate retrieves an adjustment with row-level lock
+func (r *AdjustmentRepositoryImpl) GetAdjustmentForUpdate(ctx context.Context, id int64, tx pgx.Tx) (*model.BalanceAdjustment, error) {
+	query := `
+        SELECT ` + adjustmentColumns + `
+        FROM balance_adjustments
+        WHERE id = $1 AND ($2 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $2))
+        FOR UPDATE`
+
+	adj, err := scanAdjustment(tx.QueryRow(ctx, query, id, tenantOf(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrAdjustmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get adjustment for update: %w", err)
+	}
+	return adj, nil
+}
+
+// GetAdjustments lists adjustments with the given status, oldest first
+func (r *AdjustmentRepositoryImpl) GetAdjustments(ctx context.Context, status model.AdjustmentStatus, limit, offset int) ([]*model.BalanceAdjustment, error) {
+	query := `
+        SELECT ` + adjustmentColumns + `
+        FROM balance_adjustments
+        WHERE status = $1 AND ($4 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $4))
+        ORDER BY created_at, id
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.pool.Query(ctx, query, string(status), limit, offset, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query adjustments: %w", err)
+	}
+	defer rows.Close()
+
+	adjustments := []*model.BalanceAdjustment{}
+	for rows.Next() {
+		adj, err := scanAdjustment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan adjustment: %w", err)
+		}
+		adjustments = append(adjustments, adj)
+	}
+	return adjustments, nil
+}
+
+// CompleteAdjustment records the decision on a pending adjustment, returns false if it was no longer pending
+func (r *AdjustmentRepositoryImpl) CompleteAdjustment(ctx context.Context, adj *model.BalanceAdjustment, tx pgx.Tx) (bool, error) {
+	query := `
+        UPDATE balance_adjustments
+        SET status = $1,
+            decided_by = $2,
+            decided_at = NOW(),
+            decision_note = NULLIF($3, '')
+        WHERE id = $4
+          AND status = $5
+          AND ($6 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $6))
+        RETURNING decided_at`
+
+	err := tx.QueryRow(ctx, query, string(adj.Status), adj.DecidedBy, adj.DecisionNote, adj.ID, string(model.AdjustmentPending), tenantOf(ctx)).
+		Scan(&adj.DecidedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to complete adjustment: %w", err)
+	}
+	return true, nil
+}
+
+// scanAdjustment scans a row selected with adjustmentColumns
+func scanAdjustment(row pgx.Row) (*model.BalanceAdjustment, error) {
+	adj := &model.BalanceAdjustment{}
+	var status string
+	err := row.Scan(&adj.ID, &adj.UserID, &adj.Amount, &adj.Reason, &status, &adj.CreatedBy, &adj.TransactionID,
+		&adj.DecidedBy, &adj.DecidedAt, &adj.DecisionNote, &adj.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	adj.Status = model.AdjustmentStatus(status)
+	return adj, nil
+}
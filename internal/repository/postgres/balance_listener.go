@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.BalanceListener = (*BalanceListenerImpl)(nil)
+
+// balanceChannel is notified by the users_notify_balance trigger
+const balanceChannel = "balance_changed"
+
+// BalanceListenerImpl receives balance changes through Postgres LISTEN/NOTIFY
+type BalanceListenerImpl struct {
+	pool *pgxpool.Pool
+}
+
+func NewBalanceListener(pool *pgxpool.Pool) repository.BalanceListener {
+	return &BalanceListenerImpl{pool: pool}
+}
+
+type balanceNotification struct {
+	UserID  int64           `json:"user_id"`
+	Balance decimal.Decimal `json:"balance"`
+	Version int             `json:"version"`
+}
+
+// Listen holds a dedicated connection listening for balance changes and calls fn for each one,
+// until the context is cancelled or the connection fails
+func (l *BalanceListenerImpl) Listen(ctx context.Context, ready func(), fn func(*model.BalanceEvent)) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire listen connection: %w", err)
+	}
+	// The connection is in LISTEN state, do not return it to the pool
+	defer conn.Hijack().Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+balanceChannel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	ready()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+
+		var n balanceNotification
+		if err := json.Unmarshal([]byte(notification.Payload), &n); err != nil {
+			continue
+		}
+
+		fn(&model.BalanceEvent{
+			UserID:  n.UserID,
+			Balance: n.Balance.StringFixed(2),
+			Version: n.Version,
+		})
+	}
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"transaction-processor/internal/repository"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -12,6 +13,8 @@ import (
 // TransactionManager provides common database functionality
 type TransactionManager struct {
 	pool *pgxpool.Pool
+	// replica serves reads routed with getReader, nil when no replica is configured
+	replica *ReplicaRouter
 }
 
 func NewTransactionManager(pool *pgxpool.Pool) *TransactionManager {
@@ -56,3 +59,22 @@ func (r *TransactionManager) getExecutor(tx ...pgx.Tx) Querier {
 	}
 	return r.pool
 }
+
+// getReader returns the executor for reads that tolerate replication lag: the provided tx, the replica while
+// it is healthy and the context has no read-your-writes hint, or the pool
+func (r *TransactionManager) getReader(ctx context.Context, tx ...pgx.Tx) Querier {
+	if len(tx) > 0 && tx[0] != nil {
+		return tx[0]
+	}
+	if r.replica != nil && r.replica.Healthy() && !repository.ReadYourWrites(ctx) {
+		return r.replica.pool
+	}
+	return r.pool
+}
+
+// tenantOf returns the tenant the queries of ctx are limited to, empty in system scope. Queries take it as a
+// parameter and match the rows whose tenant_id equals it, or every row when it is empty; tables without
+// tenant_id match through the tenant of their user.
+func tenantOf(ctx context.Context) string {
+	return repository.TenantID(ctx)
+}
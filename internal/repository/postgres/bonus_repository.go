@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.BonusRepository = (*BonusRepositoryImpl)(nil)
+
+// bonusExpiryActor is recorded as the actor of expirations
+const bonusExpiryActor = "bonus_expiry"
+
+// BonusRepositoryImpl is the PostgreSQL implementation of BonusRepository
+type BonusRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewBonusRepository(pool *pgxpool.Pool) repository.BonusRepository {
+	return &BonusRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+const bonusEntryColumns = `id, user_id, kind, amount, wagering, actor, COALESCE(note, ''), expires_at, created_at`
+
+// InsertBonusEntry records a grant (must be in the transaction crediting it)
+func (r *BonusRepositoryImpl) InsertBonusEntry(ctx context.Context, entry *model.BonusEntry, tx pgx.Tx) error {
+	query := `
+        INSERT INTO bonus_entries (user_id, kind, amount, wagering, actor, note, expires_at)
+        SELECT id, $2, $3, $4, $5, NULLIF($6, ''), $7
+        FROM users
+        WHERE id = $1 AND ($8 = '' OR tenant_id = $8)
+        RETURNING id, created_at`
+
+	err := tx.QueryRow(ctx, query, entry.UserID, string(entry.Kind), entry.Amount, entry.Wagering, entry.Actor, entry.Note, entry.ExpiresAt,
+		tenantOf(ctx)).
+		Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to insert bonus entry: %w", err)
+	}
+	return nil
+}
+
+// GetBonusEntries retrieves a user's grants and expirations, newest first
+func (r *BonusRepositoryImpl) GetBonusEntries(ctx context.Context, userID int64, limit, offset int) ([]*model.BonusEntry, error) {
+	query := `
+        SELECT ` + bonusEntryColumns + `
+        FROM bonus_entries
+        WHERE user_id = $1 AND ($4 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $4))
+        ORDER BY id DESC
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bonus entries: %w", err)
+	}
+	return scanBonusEntries(rows)
+}
+
+// ExpireBonuses clears the bonus of up to limit users whose bonus expired before now and records the expired amounts,
+// in one statement. Paused users keep their bonus until resumed, users locked by a transaction are left for the next run.
+func (r *BonusRepositoryImpl) ExpireBonuses(ctx context.Context, now time.Time, limit int) ([]*model.BonusEntry, error) {
+	query := `
+        WITH expired AS (
+            SELECT id, bonus_balance, bonus_wagering, bonus_expires_at
+            FROM users
+            WHERE bonus_expires_at <= $1 AND NOT mutations_paused AND ($5 = '' OR tenant_id = $5)
+            ORDER BY bonus_expires_at, id
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        ), cleared AS (
+            UPDATE users u
+            SET bonus_balance = 0, bonus_wagering = 0, bonus_expires_at = NULL, updated_at = NOW()
+            FROM expired e
+            WHERE u.id = e.id
+            RETURNING e.id, e.bonus_balance, e.bonus_wagering, e.bonus_expires_at
+        )
+        INSERT INTO bonus_entries (user_id, kind, amount, wagering, actor, expires_at)
+        SELECT id, $3, bonus_balance, bonus_wagering, $4, bonus_expires_at FROM cleared
+        RETURNING ` + bonusEntryColumns
+
+	rows, err := r.pool.Query(ctx, query, now, limit, string(model.BonusExpired), bonusExpiryActor, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire bonuses: %w", err)
+	}
+	return scanBonusEntries(rows)
+}
+
+// scanBonusEntries scans all rows selected with bonusEntryColumns and closes them
+func scanBonusEntries(rows pgx.Rows) ([]*model.BonusEntry, error) {
+	defer rows.Close()
+
+	entries := []*model.BonusEntry{}
+	for rows.Next() {
+		entry := &model.BonusEntry{}
+		err := rows.Scan(&entry.ID, &entry.UserID, &entry.Kind, &entry.Amount, &entry.Wagering, &entry.Actor, &entry.Note,
+			&entry.ExpiresAt, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bonus entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
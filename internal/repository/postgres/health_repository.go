@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RequiredSchemaVersion is the latest migration this code depends on, bump it with every new migration
+const RequiredSchemaVersion = 27
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.HealthRepository = (*HealthRepositoryImpl)(nil)
+
+// HealthRepositoryImpl is the PostgreSQL implementation of HealthRepository
+type HealthRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewHealthRepository(pool *pgxpool.Pool) repository.HealthRepository {
+	return &HealthRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+// Ping checks that a connection can be acquired and answers
+func (r *HealthRepositoryImpl) Ping(ctx context.Context) error {
+	if err := r.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// PoolStats returns the usage of the connection pool
+func (r *HealthRepositoryImpl) PoolStats() model.PoolStats {
+	stat := r.pool.Stat()
+	return model.PoolStats{
+		Acquired: stat.AcquiredConns(),
+		Idle:     stat.IdleConns(),
+		Total:    stat.TotalConns(),
+		Max:      stat.MaxConns(),
+	}
+}
+
+// SchemaVersion returns the latest applied migration, 0 if none is recorded
+func (r *HealthRepositoryImpl) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := r.pool.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		// Databases migrated before versions were recorded have no schema_migrations table
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UndefinedTable {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.IdempotencyRepository = (*IdempotencyRepositoryImpl)(nil)
+
+// IdempotencyRepositoryImpl is the PostgreSQL implementation of IdempotencyRepository
+type IdempotencyRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) repository.IdempotencyRepository {
+	return &IdempotencyRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+// GetIdempotencyRecord retrieves a record that has not expired yet
+func (r *IdempotencyRepositoryImpl) GetIdempotencyRecord(ctx context.Context, scope, key string, tx ...pgx.Tx) (*model.IdempotencyRecord, error) {
+	query := `
+        SELECT scope, key, fingerprint, COALESCE(status_code, 0), response, created_at, expires_at
+        FROM idempotency_keys
+        WHERE tenant_id = $3 AND scope = $1 AND key = $2 AND expires_at > NOW()`
+
+	rec := &model.IdempotencyRecord{}
+	executor := r.getExecutor(tx...)
+	err := executor.QueryRow(ctx, query, scope, key, keyTenant(ctx)).
+		Scan(&rec.Scope, &rec.Key, &rec.Fingerprint, &rec.StatusCode, &rec.Response, &rec.CreatedAt, &rec.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrIdempotencyNotFound
+		}
+		return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+	return rec, nil
+}
+
+// SaveIdempotencyRecord inserts a record, or replaces it if the stored one reserved the key or expired
+func (r *IdempotencyRepositoryImpl) SaveIdempotencyRecord(ctx context.Context, rec *model.IdempotencyRecord, tx ...pgx.Tx) error {
+	query := `
+        INSERT INTO idempotency_keys (tenant_id, scope, key, fingerprint, status_code, response, expires_at)
+        VALUES ($7, $1, $2, $3, NULLIF($4, 0), $5, $6)
+        ON CONFLICT (tenant_id, scope, key) DO UPDATE
+        SET fingerprint = EXCLUDED.fingerprint,
+            status_code = EXCLUDED.status_code,
+            response = EXCLUDED.response,
+            created_at = NOW(),
+            expires_at = EXCLUDED.expires_at
+        WHERE idempotency_keys.expires_at <= NOW()
+           OR (idempotency_keys.status_code IS NULL AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)`
+
+	executor := r.getExecutor(tx...)
+	_, err := executor.Exec(ctx, query, rec.Scope, rec.Key, rec.Fingerprint, rec.StatusCode, rec.Response, rec.ExpiresAt, keyTenant(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to save idempotency record: %w", err)
+	}
+	return nil
+}
+
+// ReserveIdempotencyKey claims a key for a request in progress, returns false if it is already used
+func (r *IdempotencyRepositoryImpl) ReserveIdempotencyKey(ctx context.Context, scope, key, fingerprint string, expiresAt time.Time) (bool, error) {
+	query := `
+        INSERT INTO idempotency_keys (tenant_id, scope, key, fingerprint, expires_at)
+        VALUES ($5, $1, $2, $3, $4)
+        ON CONFLICT (tenant_id, scope, key) DO UPDATE
+        SET fingerprint = EXCLUDED.fingerprint,
+            status_code = NULL,
+            response = NULL,
+            created_at = NOW(),
+            expires_at = EXCLUDED.expires_at
+        WHERE idempotency_keys.expires_at <= NOW()`
+
+	result, err := r.pool.Exec(ctx, query, scope, key, fingerprint, expiresAt, keyTenant(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// DeleteIdempotencyKey removes a record, letting the key be used again
+func (r *IdempotencyRepositoryImpl) DeleteIdempotencyKey(ctx context.Context, scope, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE tenant_id = $3 AND scope = $1 AND key = $2`
+
+	if _, err := r.pool.Exec(ctx, query, scope, key, keyTenant(ctx)); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes expired records, returning how many were deleted
+func (r *IdempotencyRepositoryImpl) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at <= NOW() AND ($1 = '' OR tenant_id = $1)`
+
+	result, err := r.pool.Exec(ctx, query, tenantOf(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// keyTenant returns the tenant owning the keys used with ctx: clients choose their keys, so each tenant has its own.
+// Keys used in system scope, e.g. by txctl, belong to the default tenant.
+func keyTenant(ctx context.Context) string {
+	if tenant := tenantOf(ctx); tenant != "" {
+		return tenant
+	}
+	return model.DefaultTenant
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.JobRunRepository = (*JobRunRepositoryImpl)(nil)
+
+// JobRunRepositoryImpl is the PostgreSQL implementation of JobRunRepository
+type JobRunRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewJobRunRepository(pool *pgxpool.Pool) repository.JobRunRepository {
+	return &JobRunRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+// CreateJobRun records the start of a run and sets its ID
+func (r *JobRunRepositoryImpl) CreateJobRun(ctx context.Context, run *model.JobRun) error {
+	query := `
+        INSERT INTO job_runs (job, trigger, instance, status, started_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id`
+
+	err := r.pool.QueryRow(ctx, query, run.Job, run.Trigger, run.Instance, string(run.Status), run.StartedAt).Scan(&run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create job run: %w", err)
+	}
+	return nil
+}
+
+// FinishJobRun records the status, error and result of a run
+func (r *JobRunRepositoryImpl) FinishJobRun(ctx context.Context, run *model.JobRun) error {
+	var result []byte
+	if run.Result != nil {
+		var err error
+		if result, err = json.Marshal(run.Result); err != nil {
+			return fmt.Errorf("failed to encode job run result: %w", err)
+		}
+	}
+
+	query := `
+        UPDATE job_runs
+        SET status = $2, finished_at = $3, error = NULLIF($4, ''), result = $5
+        WHERE id = $1`
+
+	_, err := r.pool.Exec(ctx, query, run.ID, string(run.Status), run.FinishedAt, run.Error, result)
+	if err != nil {
+		return fmt.Errorf("failed to finish job run: %w", err)
+	}
+	return nil
+}
+
+// GetJobRuns retrieves the latest runs, of one job if job is not empty, newest first
+func (r *JobRunRepositoryImpl) GetJobRuns(ctx context.Context, job string, limit int) ([]*model.JobRun, error) {
+	query := `
+        SELECT id, job, trigger, instance, status, started_at, finished_at, COALESCE(error, ''), result
+        FROM job_runs
+        WHERE $1 = '' OR job = $1
+        ORDER BY started_at DESC, id DESC
+        LIMIT $2`
+
+	rows, err := r.pool.Query(ctx, query, job, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*model.JobRun
+	for rows.Next() {
+		run := &model.JobRun{}
+		var status string
+		var result []byte
+		if err := rows.Scan(&run.ID, &run.Job, &run.Trigger, &run.Instance, &status, &run.StartedAt, &run.FinishedAt, &run.Error, &result); err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		run.Status = model.JobRunStatus(status)
+		if result != nil {
+			run.Result = json.RawMessage(result)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate job runs: %w", err)
+	}
+	return runs, nil
+}
+
+// DeleteJobRunsBefore removes runs of a job started before the given time, returning how many were deleted
+func (r *JobRunRepositoryImpl) DeleteJobRunsBefore(ctx context.Context, job string, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM job_runs WHERE job = $1 AND started_at < $2", job, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete job runs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
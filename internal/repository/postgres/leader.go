@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+	"transaction-processor/internal/metrics"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// LeaderElector elects one leader among the instances sharing the database with a session-level advisory lock.
+// The leader keeps the connection holding the lock and renews its lease by checking that connection, if it fails the
+// leader steps down. The lock is released when the leader stops or its session ends, then another instance takes over.
+type LeaderElector struct {
+	pool     *pgxpool.Pool
+	lockName string
+	interval time.Duration
+	leader   atomic.Bool
+	logger   zerolog.Logger
+
+	// conn holds the lock while leader, only used by the Run goroutine
+	conn *pgxpool.Conn
+}
+
+func NewLeaderElector(pool *pgxpool.Pool, lockName string, interval time.Duration, logger zerolog.Logger) *LeaderElector {
+	return &LeaderElector{
+		pool:     pool,
+		lockName: lockName,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run campaigns for leadership until the context is cancelled, then releases the lock
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.check(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			e.release()
+			return
+		}
+	}
+}
+
+// IsLeader reports whether this instance holds the leader lock
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// check renews the lease of the leader, or tries to acquire the lock
+func (e *LeaderElector) check(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, e.interval)
+	defer cancel()
+
+	if e.conn != nil {
+		_, err := e.conn.Exec(checkCtx, "SELECT 1")
+		if err == nil {
+			return
+		}
+
+		e.logger.Warn().Err(err).Msg("Lost the connection holding the leader lock, stepping down")
+		// Closing the connection ends the session, which releases the lock if the server still holds it
+		e.conn.Conn().Close(context.Background())
+		e.conn.Release()
+		e.conn = nil
+		e.setLeader(false)
+		return
+	}
+
+	conn, err := e.pool.Acquire(checkCtx)
+	if err != nil {
+		e.logger.Debug().Err(err).Msg("Failed to acquire connection for leader election")
+		return
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(checkCtx, "SELECT pg_try_advisory_lock(hashtext($1))", e.lockName).Scan(&acquired); err != nil || !acquired {
+		if err != nil {
+			e.logger.Debug().Err(err).Msg("Failed to try the leader lock")
+		}
+		conn.Release()
+		return
+	}
+
+	e.conn = conn
+	e.setLeader(true)
+}
+
+func (e *LeaderElector) release() {
+	if e.conn == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.interval)
+	defer cancel()
+	if _, err := e.conn.Exec(ctx, "SELECT pg_advisory_unlock(hashtext($1))", e.lockName); err != nil {
+		// The session still holds the lock, do not return it to the pool
+		e.conn.Conn().Close(ctx)
+	}
+	e.conn.Release()
+	e.conn = nil
+	e.setLeader(false)
+}
+
+func (e *LeaderElector) setLeader(leader bool) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+
+	if leader {
+		e.logger.Info().Str("lock", e.lockName).Msg("Elected leader, running scheduled background jobs")
+		metrics.Leader.Set(1)
+	} else {
+		e.logger.Info().Str("lock", e.lockName).Msg("No longer leader, scheduled background jobs stop")
+		metrics.Leader.Set(0)
+	}
+}
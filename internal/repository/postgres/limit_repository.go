@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.LimitRepository = (*LimitRepositoryImpl)(nil)
+
+// LimitRepositoryImpl is the PostgreSQL implementation of LimitRepository
+type LimitRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewLimitRepository(pool *pgxpool.Pool) repository.LimitRepository {
+	return &LimitRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+// GetLossLimits retrieves all loss limits configured for a user
+func (r *LimitRepositoryImpl) GetLossLimits(ctx context.Context, userID int64, tx ...pgx.Tx) ([]*model.LossLimit, error) {
+	query := `
+        SELECT user_id, period, amount, pending_amount, pending_effective_at, created_at, updated_at
+        FROM user_loss_limits
+        WHERE user_id = $1 AND ($2 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $2))`
+
+	executor := r.getExecutor(tx...)
+	rows, err := executor.Query(ctx, query, userID, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loss limits: %w", err)
+	}
+	defer rows.Close()
+
+	var limits []*model.LossLimit
+	for rows.Next() {
+		limit := &model.LossLimit{}
+		if err := rows.Scan(&limit.UserID, &limit.Period, &limit.Amount, &limit.PendingAmount, &limit.PendingEffectiveAt, &limit.CreatedAt, &limit.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan loss limit: %w", err)
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+// UpsertLossLimit creates or replaces the loss limit of a user for a period
+func (r *LimitRepositoryImpl) UpsertLossLimit(ctx context.Context, limit *model.LossLimit, tx pgx.Tx) error {
+	query := `
+        INSERT INTO user_loss_limits (user_id, period, amount, pending_amount, pending_effective_at)
+        SELECT id, $2, $3, $4, $5
+        FROM users
+        WHERE id = $1 AND ($6 = '' OR tenant_id = $6)
+        ON CONFLICT (user_id, period) DO UPDATE
+        SET amount = EXCLUDED.amount,
+            pending_amount = EXCLUDED.pending_amount,
+            pending_effective_at = EXCLUDED.pending_effective_at,
+            updated_at = NOW()
+        RETURNING created_at, updated_at`
+
+	err := tx.QueryRow(ctx, query, limit.UserID, limit.Period, limit.Amount, limit.PendingAmount, limit.PendingEffectiveAt, tenantOf(ctx)).
+		Scan(&limit.CreatedAt, &limit.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to upsert loss limit: %w", err)
+	}
+	return nil
+}
+
+// GetLossCounters retrieves the aggregated net loss counters of a user
+func (r *LimitRepositoryImpl) GetLossCounters(ctx context.Context, userID int64, tx ...pgx.Tx) ([]*model.LossCounter, error) {
+	query := `
+        SELECT user_id, period, window_start, net_loss
+        FROM user_loss_counters
+        WHERE user_id = $1 AND ($2 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $2))`
+
+	executor := r.getExecutor(tx...)
+	rows, err := executor.Query(ctx, query, userID, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loss counters: %w", err)
+	}
+	defer rows.Close()
+
+	var counters []*model.LossCounter
+	for rows.Next() {
+		counter := &model.LossCounter{}
+		if err := rows.Scan(&counter.UserID, &counter.Period, &counter.WindowStart, &counter.NetLoss); err != nil {
+			return nil, fmt.Errorf("failed to scan loss counter: %w", err)
+		}
+		counter.WindowStart = counter.WindowStart.UTC()
+		counters = append(counters, counter)
+	}
+	return counters, nil
+}
+
+// AddNetLoss adds delta to the user's counters of every period window containing at.
+// A counter belonging to an older window is reset; deltas for windows older than the stored one are ignored.
+func (r *LimitRepositoryImpl) AddNetLoss(ctx context.Context, userID int64, delta decimal.Decimal, at time.Time, tx pgx.Tx) error {
+	query := `
+        INSERT INTO user_loss_counters (user_id, period, window_start, net_loss)
+        SELECT $1, w.period, w.window_start, $2
+        FROM unnest($3::text[], $4::timestamp[]) AS w(period, window_start)
+        WHERE $5 = '' OR $1 IN (SELECT id FROM users WHERE tenant_id = $5)
+        ON CONFLICT (user_id, period) DO UPDATE
+        SET net_loss = CASE
+                WHEN user_loss_counters.window_start = EXCLUDED.window_start
+                THEN user_loss_counters.net_loss + EXCLUDED.net_loss
+                ELSE EXCLUDED.net_loss
+            END,
+            window_start = EXCLUDED.window_start,
+            updated_at = NOW()
+        WHERE user_loss_counters.window_start <= EXCLUDED.window_start`
+
+	periods := make([]string, 0, len(model.LimitPeriods))
+	windows := make([]time.Time, 0, len(model.LimitPeriods))
+	for _, period := range model.LimitPeriods {
+		periods = append(periods, period.String())
+		windows = append(windows, period.WindowStart(at))
+	}
+
+	if _, err := tx.Exec(ctx, query, userID, delta, periods, windows, tenantOf(ctx)); err != nil {
+		return fmt.Errorf("failed to update loss counters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.PartitionRepository = (*PartitionRepositoryImpl)(nil)
+
+// defaultPartition receives transactions no monthly partition covers, e.g. while the partition job is late
+const defaultPartition = "transactions_default"
+
+// partitionBoundLayout formats partition bounds, which cannot be passed as query parameters in DDL
+const partitionBoundLayout = "2006-01-02 15:04:05"
+
+// PartitionRepositoryImpl is the PostgreSQL implementation of PartitionRepository
+type PartitionRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewPartitionRepository(pool *pgxpool.Pool) repository.PartitionRepository {
+	return &PartitionRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+// GetPartitions retrieves all partitions recorded by the app, archived ones included, oldest first
+func (r *PartitionRepositoryImpl) GetPartitions(ctx context.Context) ([]*model.TransactionPartition, error) {
+	query := `
+        SELECT name, range_start, range_end, created_at, archived_at, COALESCE(archive_path, ''), COALESCE(row_count, 0)
+        FROM transaction_partitions
+        ORDER BY range_end`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []*model.TransactionPartition
+	for rows.Next() {
+		p := &model.TransactionPartition{}
+		if err := rows.Scan(&p.Name, &p.RangeStart, &p.RangeEnd, &p.CreatedAt, &p.ArchivedAt, &p.ArchivePath, &p.RowCount); err != nil {
+			return nil, fmt.Errorf("failed to scan partition: %w", err)
+		}
+		partitions = append(partitions, p)
+	}
+	return partitions, rows.Err()
+}
+
+// CreatePartition creates and records the partition for [from, to), no-op if it already exists.
+// Rows inserted into the default partition while the partition was missing are moved into it.
+func (r *PartitionRepositoryImpl) CreatePartition(ctx context.Context, name string, from, to time.Time) error {
+	table := pgx.Identifier{name}.Sanitize()
+	bounds := fmt.Sprintf(`FROM ('%s') TO ('%s')`, from.UTC().Format(partitionBoundLayout), to.UTC().Format(partitionBoundLayout))
+
+	record := `
+        INSERT INTO transaction_partitions (name, range_start, range_end)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (name) DO NOTHING`
+
+	return r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		// Writes wait until the partition exists, so no row lands in the default partition after the check below.
+		// The parent is locked first, as inserts do, a partition overlapping rows of the default one cannot be created.
+		if _, err := tx.Exec(ctx, `LOCK TABLE transactions IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return fmt.Errorf("failed to lock transactions: %w", err)
+		}
+		var stray bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM `+defaultPartition+` WHERE created_at >= $1 AND created_at < $2)`,
+			from.UTC(), to.UTC()).Scan(&stray)
+		if err != nil {
+			return fmt.Errorf("failed to check default partition: %w", err)
+		}
+
+		if !stray {
+			if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS `+table+` PARTITION OF transactions FOR VALUES `+bounds); err != nil {
+				return fmt.Errorf("failed to create partition %s: %w", name, err)
+			}
+		} else {
+			// Detached, the default partition no longer conflicts with the new range while its rows are moved
+			inRange := []any{from.UTC(), to.UTC()}
+			for _, step := range []struct {
+				query string
+				args  []any
+			}{
+				{`ALTER TABLE transactions DETACH PARTITION ` + defaultPartition, nil},
+				{`CREATE TABLE ` + table + ` PARTITION OF transactions FOR VALUES ` + bounds, nil},
+				{`INSERT INTO ` + table + ` SELECT * FROM ` + defaultPartition + ` WHERE created_at >= $1 AND created_at < $2`, inRange},
+				{`DELETE FROM ` + defaultPartition + ` WHERE created_at >= $1 AND created_at < $2`, inRange},
+				{`ALTER TABLE transactions ATTACH PARTITION ` + defaultPartition + ` DEFAULT`, nil},
+			} {
+				if _, err := tx.Exec(ctx, step.query, step.args...); err != nil {
+					return fmt.Errorf("failed to move default partition rows into %s: %w", name, err)
+				}
+			}
+		}
+
+		if _, err := tx.Exec(ctx, record, name, from.UTC(), to.UTC()); err != nil {
+			return fmt.Errorf("failed to record partition %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// HasPendingReview reports whether a partition holds transactions waiting for manual review
+func (r *PartitionRepositoryImpl) HasPendingReview(ctx context.Context, name string, tx pgx.Tx) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM ` + pgx.Identifier{name}.Sanitize() + ` WHERE status = $1)`
+
+	var pending bool
+	if err := tx.QueryRow(ctx, query, string(model.StatusPendingReview)).Scan(&pending); err != nil {
+		return false, fmt.Errorf("failed to check pending reviews in %s: %w", name, err)
+	}
+	return pending, nil
+}
+
+// ExportPartition locks a partition against writes and passes each of its transactions to fn
+func (r *PartitionRepositoryImpl) ExportPartition(ctx context.Context, name string, fn func(*model.Transaction) error, tx pgx.Tx) error {
+	table := pgx.Identifier{name}.Sanitize()
+
+	// Cancellations and reviews of the exported rows wait until the partition is dropped
+	if _, err := tx.Exec(ctx, `LOCK TABLE `+table+` IN SHARE MODE`); err != nil {
+		return fmt.Errorf("failed to lock partition %s: %w", name, err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT `+transactionColumns+` FROM `+table+` ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to query partition %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		trans, err := scanTransaction(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		if err := fn(trans); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ArchivePartition points the transaction index at the archive file, moves the processed totals of the partition
+// into users.archived_net, then detaches and drops it
+func (r *PartitionRepositoryImpl) ArchivePartition(ctx context.Context, name, archivePath string, rowCount int64, tx pgx.Tx) error {
+	table := pgx.Identifier{name}.Sanitize()
+
+	index := `
+        UPDATE transaction_index i
+        SET archive_path = $1
+        FROM ` + table + ` t
+        WHERE i.transaction_id = t.transaction_id`
+	if _, err := tx.Exec(ctx, index, archivePath); err != nil {
+		return fmt.Errorf("failed to update transaction index: %w", err)
+	}
+
+	// Keeps opening_balance + archived_net + applied transactions equal to the balance, see GetBalanceDrifts
+	totals := `
+        UPDATE users u
+        SET archived_net = u.archived_net + t.net
+        FROM (
+            SELECT user_id, SUM(CASE WHEN state = 'win' THEN 1 ELSE -1 END *
+                                CASE WHEN status = 'cancelled' THEN cancel_shortfall ELSE amount END) AS net
+            FROM ` + table + `
+            WHERE status IN ('processed', 'cancellation_failed') OR (status = 'cancelled' AND cancel_policy = 'partial')
+            GROUP BY user_id
+        ) t
+        WHERE u.id = t.user_id`
+	if _, err := tx.Exec(ctx, totals); err != nil {
+		return fmt.Errorf("failed to move archived totals: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `ALTER TABLE transactions DETACH PARTITION `+table); err != nil {
+		return fmt.Errorf("failed to detach partition %s: %w", name, err)
+	}
+	if _, err := tx.Exec(ctx, `DROP TABLE `+table); err != nil {
+		return fmt.Errorf("failed to drop partition %s: %w", name, err)
+	}
+
+	record := `
+        UPDATE transaction_partitions
+        SET archived_at = NOW(), archive_path = $1, row_count = $2
+        WHERE name = $3`
+	if _, err := tx.Exec(ctx, record, archivePath, rowCount, name); err != nil {
+		return fmt.Errorf("failed to record archived partition %s: %w", name, err)
+	}
+	return nil
+}
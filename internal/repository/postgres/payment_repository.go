@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.PaymentRepository = (*PaymentRepositoryImpl)(nil)
+
+// PaymentRepositoryImpl is the PostgreSQL implementation of PaymentRepository
+type PaymentRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewPaymentRepository(pool *pgxpool.Pool) repository.PaymentRepository {
+	return &PaymentRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+const paymentColumns = `id, user_id, kind, amount, status, transaction_id, COALESCE(provider, ''),
+        COALESCE(provider_reference, ''), COALESCE(decided_by, ''), COALESCE(failure_reason, ''),
+        created_at, updated_at, completed_at`
+
+// InsertPayment creates a new payment record
+func (r *PaymentRepositoryImpl) InsertPayment(ctx context.Context, payment *model.Payment, tx ...pgx.Tx) error {
+	query := `
+        INSERT INTO payments (user_id, kind, amount, status, transaction_id)
+        SELECT id, $2, $3, $4, $5
+        FROM users
+        WHERE id = $1 AND ($6 = '' OR tenant_id = $6)
+        RETURNING id, created_at, updated_at`
+
+	executor := r.getExecutor(tx...)
+	err := executor.QueryRow(ctx, query, payment.UserID, string(payment.Kind), payment.Amount, string(payment.Status), payment.TransactionID,
+		tenantOf(ctx)).
+		Scan(&payment.ID, &payment.CreatedAt, &payment.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to insert payment: %w", err)
+	}
+	return nil
+}
+
+// GetPayment retrieves a payment by its ID
+func (r *PaymentRepositoryImpl) GetPayment(ctx context.Context, id int64) (*model.Payment, error) {
+	query := `
+        SELECT ` + paymentColumns + `
+        FROM payments
+        WHERE id = $1 AND ($2 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $2))`
+
+	payment, err := scanPayment(r.pool.QueryRow(ctx, query, id, tenantOf(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	return payment, nil
+}
+
+// GetPaymentForUpdate retrieves a payment with row-level lock
+func (r *PaymentRepositoryImpl) GetPaymentForUpdate(ctx context.Context, id int64, tx pgx.Tx) (*model.Payment, error) {
+	query := `
+        SELECT ` + paymentColumns + `
+        FROM payments
+        WHERE id = $1 AND ($2 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $2))
+        FOR UPDATE`
+
+	payment, err := scanPayment(tx.QueryRow(ctx, query, id, tenantOf(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment for update: %w", err)
+	}
+	return payment, nil
+}
+
+// GetPaymentByReferenceForUpdate retrieves a payment by the provider's reference with row-level lock
+func (r *PaymentRepositoryImpl) GetPaymentByReferenceForUpdate(ctx context.Context, provider, reference string, tx pgx.Tx) (*model.Payment, error) {
+	query := `
+        SELECT ` + paymentColumns + `
+        FROM payments
+        WHERE provider = $1 AND provider_reference = $2
+          AND ($3 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $3))
+        FOR UPDATE`
+
+	payment, err := scanPayment(tx.QueryRow(ctx, query, provider, reference, tenantOf(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment by reference for update: %w", err)
+	}
+	return payment, nil
+}
+
+// GetPayments lists payments with the given status, oldest first
+func (r *PaymentRepositoryImpl) GetPayments(ctx context.Context, status model.PaymentStatus, limit, offset int) ([]*model.Payment, error) {
+	query := `
+        SELECT ` + paymentColumns + `
+        FROM payments
+        WHERE status = $1 AND ($4 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $4))
+        ORDER BY created_at, id
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.pool.Query(ctx, query, string(status), limit, offset, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payments: %w", err)
+	}
+	return scanPayments(rows)
+}
+
+// GetPaymentsByUser lists a user's payments, newest first
+func (r *PaymentRepositoryImpl) GetPaymentsByUser(ctx context.Context, userID int64, limit, offset int) ([]*model.Payment, error) {
+	query := `
+        SELECT ` + paymentColumns + `
+        FROM payments
+        WHERE user_id = $1 AND ($4 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $4))
+        ORDER BY id DESC
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user payments: %w", err)
+	}
+	return scanPayments(rows)
+}
+
+// UpdatePayment stores the new status of a payment if it still has status from, returns false otherwise.
+// Completed and failed payments get their completion time.
+func (r *PaymentRepositoryImpl) UpdatePayment(ctx context.Context, payment *model.Payment, from model.PaymentStatus, tx pgx.Tx) (bool, error) {
+	query := `
+        UPDATE payments
+        SET status = $1,
+            provider = NULLIF($2, ''),
+            provider_reference = NULLIF($3, ''),
+            decided_by = NULLIF($4, ''),
+            failure_reason = NULLIF($5, ''),
+            updated_at = NOW(),
+            completed_at = CASE WHEN $1 IN ('completed', 'failed') THEN NOW() END
+        WHERE id = $6
+          AND status = $7
+          AND ($8 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $8))
+        RETURNING updated_at, completed_at`
+
+	err := tx.QueryRow(ctx, query, string(payment.Status), payment.Provider, payment.ProviderReference, payment.DecidedBy,
+		payment.FailureReason, payment.ID, string(from), tenantOf(ctx)).
+		Scan(&payment.UpdatedAt, &payment.CompletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update payment: %w", err)
+	}
+	return true, nil
+}
+
+// scanPayments scans all rows selected with paymentColumns and closes them
+func scanPayments(rows pgx.Rows) ([]*model.Payment, error) {
+	defer rows.Close()
+
+	payments := []*model.Payment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+// scanPayment scans a row selected with paymentColumns
+func scanPayment(row pgx.Row) (*model.Payment, error) {
+	payment := &model.Payment{}
+	var kind, status string
+	err := row.Scan(&payment.ID, &payment.UserID, &kind, &payment.Amount, &status, &payment.TransactionID, &payment.Provider,
+		&payment.ProviderReference, &payment.DecidedBy, &payment.FailureReason, &payment.CreatedAt, &payment.UpdatedAt,
+		&payment.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	payment.Kind = model.PaymentKind(kind)
+	payment.Status = model.PaymentStatus(status)
+	return payment, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"transaction-processor/internal/metrics"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// ReplicaRouter tracks the replication lag of the read replica, reads go to it only while the lag is below maxLag
+type ReplicaRouter struct {
+	pool     *pgxpool.Pool
+	maxLag   time.Duration
+	interval time.Duration
+	healthy  atomic.Bool
+	logger   zerolog.Logger
+}
+
+func NewReplicaRouter(pool *pgxpool.Pool, maxLag, interval time.Duration, logger zerolog.Logger) *ReplicaRouter {
+	return &ReplicaRouter{
+		pool:     pool,
+		maxLag:   maxLag,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run checks the replication lag until the context is cancelled. The replica is unused until the first check passes.
+func (r *ReplicaRouter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.check(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Healthy reports whether reads may go to the replica
+func (r *ReplicaRouter) Healthy() bool {
+	return r.healthy.Load()
+}
+
+func (r *ReplicaRouter) check(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, r.interval)
+	defer cancel()
+
+	lag, err := r.lag(checkCtx)
+	healthy := err == nil && lag <= r.maxLag
+	if err == nil {
+		metrics.ReplicaLagSeconds.Set(lag.Seconds())
+	}
+
+	if r.healthy.Swap(healthy) != healthy {
+		if healthy {
+			r.logger.Info().Dur("lag", lag).Msg("read replica healthy, routing reads to replica")
+		} else {
+			r.logger.Warn().Err(err).Dur("lag", lag).Dur("max_lag", r.maxLag).Msg("read replica unhealthy, routing reads to primary")
+		}
+	}
+	if healthy {
+		metrics.ReplicaHealthy.Set(1)
+	} else {
+		metrics.ReplicaHealthy.Set(0)
+	}
+}
+
+// lag returns how far the replica is behind. A replica that replayed everything it received has no lag,
+// even if the primary has been idle since the last replayed transaction.
+func (r *ReplicaRouter) lag(ctx context.Context) (time.Duration, error) {
+	query := `
+        SELECT CASE
+            WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+            ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+        END`
+
+	var seconds float64
+	if err := r.pool.QueryRow(ctx, query).Scan(&seconds); err != nil {
+		return 0, fmt.Errorf("failed to check replica lag: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+	"transaction-processor/internal/archive"
 	"transaction-processor/internal/model"
 	"transaction-processor/internal/repository"
 
@@ -11,134 +13,214 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 )
 
 // Ensure implementation satisfies interface at compile time
 var _ repository.TransactionRepository = (*TransactionRepositoryImpl)(nil)
 
+// transactionColumns is the select list matching scanTransaction
+const transactionColumns = `id, transaction_id, user_id, source_type, COALESCE(provider_id, ''), state, amount, status,
+        COALESCE(review_reason, ''), COALESCE(reviewed_by, ''), reviewed_at, COALESCE(review_note, ''),
+        COALESCE(risk_decision, ''), COALESCE(risk_rules, '{}'),
+        cancelled_at, COALESCE(cancel_reason, ''), COALESCE(cancel_policy, ''), cancel_shortfall, bonus_amount, created_at, updated_at`
+
 // TransactionRepositoryImpl is the PostgreSQL implementation of TransactionRepository
 type TransactionRepositoryImpl struct {
 	*TransactionManager
 }
 
-func NewTransactionRepository(pool *pgxpool.Pool) repository.TransactionRepository {
+// NewTransactionRepository creates the repository, replica is optional and serves reads that tolerate replication lag
+func NewTransactionRepository(pool *pgxpool.Pool, replica *ReplicaRouter) repository.TransactionRepository {
 	return &TransactionRepositoryImpl{
-		TransactionManager: NewTransactionManager(pool),
+		TransactionManager: &TransactionManager{pool: pool, replica: replica},
 	}
 }
 
-// InsertTransaction creates a new transaction record
+// InsertTransaction creates a new transaction record in the tenant of its user.
+// transaction_id uniqueness across partitions (and archives) and tenants is enforced by transaction_index.
 func (r *TransactionRepositoryImpl) InsertTransaction(ctx context.Context, trans *model.Transaction, tx pgx.Tx) error {
+	index := `INSERT INTO transaction_index (transaction_id, user_id) VALUES ($1, $2)`
+
+	if _, err := tx.Exec(ctx, index, trans.TransactionID, trans.UserID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return model.ErrDuplicateTransaction
+		}
+		return fmt.Errorf("failed to index transaction: %w", err)
+	}
+
 	query := `
-        INSERT INTO transactions (transaction_id, user_id, source_type, state, amount, status)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO transactions (transaction_id, user_id, tenant_id, source_type, provider_id, state, amount, status, review_reason,
+                                  risk_decision, risk_rules, bonus_amount)
+        SELECT $1, id, tenant_id, $3, NULLIF($4, ''), $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10, $11
+        FROM users
+        WHERE id = $2 AND ($12 = '' OR tenant_id = $12)
         RETURNING id, created_at, updated_at`
 
-	err := tx.QueryRow(ctx, query, trans.TransactionID, trans.UserID, trans.SourceType, trans.State, trans.Amount, trans.Status).
+	err := tx.QueryRow(ctx, query, trans.TransactionID, trans.UserID, trans.SourceType, trans.ProviderID, trans.State, trans.Amount, trans.Status,
+		trans.ReviewReason, trans.RiskDecision, trans.RiskRules, trans.BonusAmount, tenantOf(ctx)).
 		Scan(&trans.ID, &trans.CreatedAt, &trans.UpdatedAt)
-
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			return model.ErrDuplicateTransaction
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrUserNotFound
 		}
 		return fmt.Errorf("failed to insert transaction: %w", err)
 	}
 	return nil
 }
 
-// GetTransaction retrieves a transaction by its transaction ID
+// GetTransaction retrieves a transaction by its transaction ID.
+// The index gives its created_at so only one partition is searched; archived transactions are read from their archive file.
 func (r *TransactionRepositoryImpl) GetTransaction(ctx context.Context, transactionID string, tx ...pgx.Tx) (*model.Transaction, error) {
 	query := `
-        SELECT id, transaction_id, user_id, source_type, state, amount, status, cancelled_at, created_at, updated_at
-        FROM transactions WHERE transaction_id = $1`
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE transaction_id = $1
+          AND created_at = (SELECT created_at FROM transaction_index WHERE transaction_id = $1)
+          AND ($2 = '' OR tenant_id = $2)`
 
-	trans := &model.Transaction{}
-	executor := r.getExecutor(tx...)
-	err := executor.QueryRow(ctx, query, transactionID).Scan(&trans.ID, &trans.TransactionID, &trans.UserID, &trans.SourceType, &trans.State, &trans.Amount, &trans.Status, &trans.CancelledAt, &trans.CreatedAt, &trans.UpdatedAt)
+	executor := r.getReader(ctx, tx...)
+	trans, err := scanTransaction(executor.QueryRow(ctx, query, transactionID, tenantOf(ctx)))
+	if err == nil {
+		return trans, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	path, err := r.archivePath(ctx, executor, transactionID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, model.ErrTransactionNotFound
+		return nil, err
+	}
+
+	trans, err = archive.Find(path, transactionID)
+	if err != nil {
+		if errors.Is(err, model.ErrTransactionNotFound) {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to get transaction: %w", err)
+		return nil, fmt.Errorf("failed to get archived transaction: %w", err)
 	}
 	return trans, nil
 }
 
+// archivePath returns the archive file of an archived transaction, model.ErrTransactionNotFound if it is not archived
+func (r *TransactionRepositoryImpl) archivePath(ctx context.Context, executor Querier, transactionID string) (string, error) {
+	query := `
+        SELECT archive_path
+        FROM transaction_index
+        WHERE transaction_id = $1 AND archive_path IS NOT NULL
+          AND ($2 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $2))`
+
+	var path string
+	if err := executor.QueryRow(ctx, query, transactionID, tenantOf(ctx)).Scan(&path); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", model.ErrTransactionNotFound
+		}
+		return "", fmt.Errorf("failed to get transaction archive: %w", err)
+	}
+	return path, nil
+}
+
 // GetTransactionsByUser retrieves paginated transactions for a user
 func (r *TransactionRepositoryImpl) GetTransactionsByUser(ctx context.Context, userID int64, limit, offset int) ([]*model.Transaction, error) {
 	query := `
-        SELECT id, transaction_id, user_id, source_type, state, amount, status, cancelled_at, created_at, updated_at
-        FROM transactions WHERE user_id = $1
+        SELECT ` + transactionColumns + `
+        FROM transactions WHERE user_id = $1 AND ($4 = '' OR tenant_id = $4)
         ORDER BY created_at DESC
         LIMIT $2 OFFSET $3`
 
-	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	rows, err := r.getReader(ctx).Query(ctx, query, userID, limit, offset, tenantOf(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query transactions: %w", err)
 	}
-	defer rows.Close()
 
-	var transactions []*model.Transaction
-	for rows.Next() {
-		trans := &model.Transaction{}
-		if err := rows.Scan(&trans.ID, &trans.TransactionID, &trans.UserID, &trans.SourceType, &trans.State, &trans.Amount, &trans.Status, &trans.CancelledAt, &trans.CreatedAt, &trans.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan transaction: %w", err)
-		}
-		transactions = append(transactions, trans)
-	}
-	return transactions, nil
+	return scanTransactions(rows)
 }
 
-// GetLatestOddProcessedTransactions retrieves latest odd-numbered processed transactions
+// GetLatestOddProcessedTransactions retrieves latest odd-numbered processed transactions, adjustments and payment
+// settlements are never swept
 func (r *TransactionRepositoryImpl) GetLatestOddProcessedTransactions(ctx context.Context, limit int) ([]*model.Transaction, error) {
 	query := `
-        SELECT id, transaction_id, user_id, source_type, state, amount, status, cancelled_at, created_at, updated_at
+        SELECT ` + transactionColumns + `
         FROM transactions
-        WHERE id % 2 = 1 AND status = 'processed'
+        WHERE id % 2 = 1 AND status = 'processed' AND source_type NOT IN ('adjustment', 'settlement') AND ($2 = '' OR tenant_id = $2)
         ORDER BY id DESC
         LIMIT $1`
 
-	rows, err := r.pool.Query(ctx, query, limit)
+	rows, err := r.pool.Query(ctx, query, limit, tenantOf(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query latest odd transactions: %w", err)
 	}
-	defer rows.Close()
 
-	var transactions []*model.Transaction
-	for rows.Next() {
-		trans := &model.Transaction{}
-		if err := rows.Scan(&trans.ID, &trans.TransactionID, &trans.UserID, &trans.SourceType, &trans.State, &trans.Amount, &trans.Status, &trans.CancelledAt, &trans.CreatedAt, &trans.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan transaction: %w", err)
-		}
-		transactions = append(transactions, trans)
-	}
-	return transactions, nil
+	return scanTransactions(rows)
 }
 
-// CancelTransactionIfProcessed cancels a transaction if status is processed
-func (r *TransactionRepositoryImpl) CancelTransactionIfProcessed(ctx context.Context, id int64, tx pgx.Tx) (bool, error) {
+// CancelTransactionIfProcessed cancels a transaction with a reason code if status is processed or cancellation_failed
+func (r *TransactionRepositoryImpl) CancelTransactionIfProcessed(ctx context.Context, id int64, reason string, policy model.ShortfallPolicy, shortfall *decimal.Decimal, tx pgx.Tx) (bool, error) {
 	query := `
 		UPDATE transactions
 		SET status = $1,
 		    cancelled_at = NOW(),
+		    cancel_reason = $2,
+		    cancel_policy = NULLIF($3, ''),
+		    cancel_shortfall = $4,
 		    updated_at = NOW()
-		WHERE id = $2
-		  AND status = $3`
+		WHERE id = $5
+		  AND status IN ($6, $7)
+		  AND ($8 = '' OR tenant_id = $8)`
 
-	result, err := tx.Exec(ctx, query, string(model.StatusCancelled), id, string(model.StatusProcessed))
+	result, err := tx.Exec(ctx, query, string(model.StatusCancelled), reason, string(policy), shortfall, id,
+		string(model.StatusProcessed), string(model.StatusCancellationFailed), tenantOf(ctx))
 	if err != nil {
 		return false, fmt.Errorf("failed to cancel transaction: %w", err)
 	}
 	return result.RowsAffected() == 1, nil
 }
 
+// MarkCancellationFailed records a cancellation the balance could not cover if status is processed
+func (r *TransactionRepositoryImpl) MarkCancellationFailed(ctx context.Context, id int64, reason string, shortfall decimal.Decimal, tx pgx.Tx) (bool, error) {
+	query := `
+		UPDATE transactions
+		SET status = $1,
+		    cancel_reason = $2,
+		    cancel_policy = $3,
+		    cancel_shortfall = $4,
+		    updated_at = NOW()
+		WHERE id = $5
+		  AND status = $6
+		  AND ($7 = '' OR tenant_id = $7)`
+
+	result, err := tx.Exec(ctx, query, string(model.StatusCancellationFailed), reason, string(model.ShortfallSkip), shortfall, id,
+		string(model.StatusProcessed), tenantOf(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to mark cancellation failed: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// GetCancellationShortfalls retrieves transactions whose cancellation failed or left a shortfall, newest first
+func (r *TransactionRepositoryImpl) GetCancellationShortfalls(ctx context.Context, limit, offset int) ([]*model.Transaction, error) {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE cancel_shortfall IS NOT NULL AND ($3 = '' OR tenant_id = $3)
+        ORDER BY created_at DESC
+        LIMIT $1 OFFSET $2`
+
+	rows, err := r.getReader(ctx).Query(ctx, query, limit, offset, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cancellation shortfalls: %w", err)
+	}
+	return scanTransactions(rows)
+}
+
 // LockTransactionForCancellation locks a transaction row for cancellation if it's still processed
 func (r *TransactionRepositoryImpl) LockTransactionForCancellation(ctx context.Context, id int64, tx pgx.Tx) (bool, error) {
-	query := `SELECT id FROM transactions WHERE id = $1 AND status = 'processed' FOR UPDATE SKIP LOCKED`
+	query := `SELECT id FROM transactions WHERE id = $1 AND status = 'processed' AND ($2 = '' OR tenant_id = $2) FOR UPDATE SKIP LOCKED`
 
 	var lockedID int64
-	err := tx.QueryRow(ctx, query, id).Scan(&lockedID)
+	err := tx.QueryRow(ctx, query, id, tenantOf(ctx)).Scan(&lockedID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return false, nil
@@ -147,3 +229,195 @@ func (r *TransactionRepositoryImpl) LockTransactionForCancellation(ctx context.C
 	}
 	return true, nil
 }
+
+// scanTransaction scans a row selected with transactionColumns
+func scanTransaction(row pgx.Row) (*model.Transaction, error) {
+	trans := &model.Transaction{}
+	err := row.Scan(&trans.ID, &trans.TransactionID, &trans.UserID, &trans.SourceType, &trans.ProviderID, &trans.State, &trans.Amount, &trans.Status,
+		&trans.ReviewReason, &trans.ReviewedBy, &trans.ReviewedAt, &trans.ReviewNote,
+		&trans.RiskDecision, &trans.RiskRules,
+		&trans.CancelledAt, &trans.CancelReason, &trans.CancelPolicy, &trans.CancelShortfall, &trans.BonusAmount, &trans.CreatedAt, &trans.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return trans, nil
+}
+
+// scanTransactions scans all rows selected with transactionColumns and closes them
+func scanTransactions(rows pgx.Rows) ([]*model.Transaction, error) {
+	defer rows.Close()
+
+	var transactions []*model.Transaction
+	for rows.Next() {
+		trans, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, trans)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// GetPendingReviewTransactions retrieves transactions waiting for manual review, oldest first
+func (r *TransactionRepositoryImpl) GetPendingReviewTransactions(ctx context.Context, limit, offset int) ([]*model.Transaction, error) {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE status = $1 AND ($4 = '' OR tenant_id = $4)
+        ORDER BY created_at ASC
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.getReader(ctx).Query(ctx, query, string(model.StatusPendingReview), limit, offset, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending review transactions: %w", err)
+	}
+	return scanTransactions(rows)
+}
+
+// GetTransactionForUpdate retrieves a transaction by its transaction ID with row-level lock.
+// Archived transactions are read-only and return model.ErrTransactionArchived.
+func (r *TransactionRepositoryImpl) GetTransactionForUpdate(ctx context.Context, transactionID string, tx pgx.Tx) (*model.Transaction, error) {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE transaction_id = $1
+          AND created_at = (SELECT created_at FROM transaction_index WHERE transaction_id = $1)
+          AND ($2 = '' OR tenant_id = $2)
+        FOR UPDATE`
+
+	trans, err := scanTransaction(tx.QueryRow(ctx, query, transactionID, tenantOf(ctx)))
+	if err == nil {
+		return trans, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get transaction for update: %w", err)
+	}
+
+	if _, err := r.archivePath(ctx, tx, transactionID); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: transaction %s", model.ErrTransactionArchived, transactionID)
+}
+
+// CompleteReview records a review decision on a transaction pending review, with the bonus part of an approved amount
+func (r *TransactionRepositoryImpl) CompleteReview(ctx context.Context, id int64, status model.TransactionStatus, reviewer, note string,
+	bonusAmount decimal.Decimal, tx pgx.Tx) (bool, error) {
+	query := `
+		UPDATE transactions
+		SET status = $1,
+		    reviewed_by = $2,
+		    reviewed_at = NOW(),
+		    review_note = NULLIF($3, ''),
+		    bonus_amount = $6,
+		    updated_at = NOW()
+		WHERE id = $4
+		  AND status = $5
+		  AND ($7 = '' OR tenant_id = $7)`
+
+	result, err := tx.Exec(ctx, query, string(status), reviewer, note, id, string(model.StatusPendingReview), bonusAmount, tenantOf(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to complete review: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// GetRecentTransactionsByUser retrieves a user's transactions created since the given time, newest first,
+// without rejected and blocked ones
+func (r *TransactionRepositoryImpl) GetRecentTransactionsByUser(ctx context.Context, userID int64, since time.Time, tx ...pgx.Tx) ([]*model.Transaction, error) {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE user_id = $1 AND created_at >= $2 AND status NOT IN ($3, $4) AND ($5 = '' OR tenant_id = $5)
+        ORDER BY created_at DESC, id DESC`
+
+	executor := r.getExecutor(tx...)
+	rows, err := executor.Query(ctx, query, userID, since, string(model.StatusRejected), string(model.StatusBlocked), tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent transactions: %w", err)
+	}
+
+	return scanTransactions(rows)
+}
+
+// GetWinStats returns the average amount and number of a user's latest processed game and server wins, at most limit
+// of them. Deposits and adjustments are not wins of play and would skew the average.
+func (r *TransactionRepositoryImpl) GetWinStats(ctx context.Context, userID int64, limit int, tx ...pgx.Tx) (decimal.Decimal, int, error) {
+	query := `
+        SELECT COALESCE(AVG(amount), 0), COUNT(*)
+        FROM (
+            SELECT amount
+            FROM transactions
+            WHERE user_id = $1 AND state = $2 AND status = $3 AND source_type IN ($4, $5) AND ($6 = '' OR tenant_id = $6)
+            ORDER BY created_at DESC
+            LIMIT $7
+        ) AS latest_wins`
+
+	var (
+		average decimal.Decimal
+		count   int
+	)
+	executor := r.getExecutor(tx...)
+	err := executor.QueryRow(ctx, query, userID, string(model.StateWin), string(model.StatusProcessed),
+		model.SourceGame.String(), model.SourceServer.String(), tenantOf(ctx), limit).Scan(&average, &count)
+	if err != nil {
+		return decimal.Zero, 0, fmt.Errorf("failed to get win stats: %w", err)
+	}
+	return average, count, nil
+}
+
+// InsertTransactionEvent records a status change of a transaction (must be in the transaction making the change)
+func (r *TransactionRepositoryImpl) InsertTransactionEvent(ctx context.Context, event *model.TransactionEvent, tx pgx.Tx) error {
+	query := `
+        INSERT INTO transaction_events (transaction_id, user_id, from_status, to_status, actor_type, actor, reason, note,
+                                        request_id, balance_before, balance_after, debt_before, debt_after, bonus_before, bonus_after)
+        SELECT $1, id, NULLIF($3, ''), $4, $5, NULLIF($6, ''), $7, NULLIF($8, ''), NULLIF($9, ''), $10, $11, $12, $13, $14, $15
+        FROM users
+        WHERE id = $2 AND ($16 = '' OR tenant_id = $16)
+        RETURNING id, created_at`
+
+	err := tx.QueryRow(ctx, query, event.TransactionID, event.UserID, string(event.FromStatus), string(event.ToStatus),
+		string(event.ActorType), event.Actor, event.Reason, event.Note, event.RequestID, event.BalanceBefore, event.BalanceAfter,
+		event.DebtBefore, event.DebtAfter, event.BonusBefore, event.BonusAfter, tenantOf(ctx)).
+		Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to insert transaction event: %w", err)
+	}
+	return nil
+}
+
+// GetTransactionEvents retrieves the status changes of a transaction, oldest first
+func (r *TransactionRepositoryImpl) GetTransactionEvents(ctx context.Context, transactionID string) ([]*model.TransactionEvent, error) {
+	query := `
+        SELECT id, transaction_id, user_id, COALESCE(from_status, ''), to_status, actor_type, COALESCE(actor, ''), reason,
+               COALESCE(note, ''), COALESCE(request_id, ''), balance_before, balance_after, debt_before,
+               debt_after, bonus_before, bonus_after, created_at
+        FROM transaction_events
+        WHERE transaction_id = $1
+          AND ($2 = '' OR user_id IN (SELECT id FROM users WHERE tenant_id = $2))
+        ORDER BY id`
+
+	rows, err := r.getReader(ctx).Query(ctx, query, transactionID, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transaction events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*model.TransactionEvent
+	for rows.Next() {
+		event := &model.TransactionEvent{}
+		err := rows.Scan(&event.ID, &event.TransactionID, &event.UserID, &event.FromStatus, &event.ToStatus, &event.ActorType,
+			&event.Actor, &event.Reason, &event.Note, &event.RequestID, &event.BalanceBefore, &event.BalanceAfter, &event.DebtBefore,
+			&event.DebtAfter, &event.BonusBefore, &event.BonusAfter, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
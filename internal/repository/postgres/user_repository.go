@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 	"transaction-processor/internal/model"
 	"transaction-processor/internal/repository"
 
@@ -21,18 +22,25 @@ type UserRepositoryImpl struct {
 	*TransactionManager
 }
 
-func NewUserRepository(pool *pgxpool.Pool) repository.UserRepository {
+// NewUserRepository creates the repository, replica is optional and serves reads that tolerate replication lag
+func NewUserRepository(pool *pgxpool.Pool, replica *ReplicaRouter) repository.UserRepository {
 	return &UserRepositoryImpl{
-		TransactionManager: NewTransactionManager(pool),
+		TransactionManager: &TransactionManager{pool: pool, replica: replica},
 	}
 }
 
 // GetUserForUpdate retrieves a user with row-level lock
 func (r *UserRepositoryImpl) GetUserForUpdate(ctx context.Context, userID int64, tx pgx.Tx) (*model.User, error) {
-	query := `SELECT id, balance, version, created_at, updated_at FROM users WHERE id = $1 FOR UPDATE`
+	query := `
+        SELECT id, tenant_id, COALESCE(provider_id, ''), balance, debt, bonus_balance, bonus_wagering, bonus_expires_at, reserved, version, mutations_paused,
+               created_at, updated_at
+        FROM users
+        WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+        FOR UPDATE`
 
 	user := &model.User{}
-	err := tx.QueryRow(ctx, query, userID).Scan(&user.ID, &user.Balance, &user.Version, &user.CreatedAt, &user.UpdatedAt)
+	err := tx.QueryRow(ctx, query, userID, tenantOf(ctx)).Scan(&user.ID, &user.TenantID, &user.ProviderID, &user.Balance, &user.Debt, &user.BonusBalance, &user.BonusWagering,
+		&user.BonusExpiresAt, &user.Reserved, &user.Version, &user.MutationsPaused, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -45,10 +53,10 @@ func (r *UserRepositoryImpl) GetUserForUpdate(ctx context.Context, userID int64,
 
 // GetBalance get the current balance for a user
 func (r *UserRepositoryImpl) GetBalance(ctx context.Context, userID int64, tx ...pgx.Tx) (decimal.Decimal, error) {
-	query := `SELECT balance FROM users WHERE id = $1`
+	query := `SELECT balance FROM users WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
 	var balance decimal.Decimal
-	executor := r.getExecutor(tx...)
-	err := executor.QueryRow(ctx, query, userID).Scan(&balance)
+	executor := r.getReader(ctx, tx...)
+	err := executor.QueryRow(ctx, query, userID, tenantOf(ctx)).Scan(&balance)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -59,14 +67,50 @@ func (r *UserRepositoryImpl) GetBalance(ctx context.Context, userID int64, tx ..
 	return balance, nil
 }
 
+// GetUser retrieves a user without locking
+func (r *UserRepositoryImpl) GetUser(ctx context.Context, userID int64, tx ...pgx.Tx) (*model.User, error) {
+	query := `
+        SELECT id, tenant_id, COALESCE(provider_id, ''), balance, debt, bonus_balance, bonus_wagering, bonus_expires_at, reserved, version, mutations_paused,
+               created_at, updated_at
+        FROM users
+        WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
+
+	user := &model.User{}
+	executor := r.getReader(ctx, tx...)
+	err := executor.QueryRow(ctx, query, userID, tenantOf(ctx)).Scan(&user.ID, &user.TenantID, &user.ProviderID, &user.Balance, &user.Debt, &user.BonusBalance, &user.BonusWagering,
+		&user.BonusExpiresAt, &user.Reserved, &user.Version, &user.MutationsPaused, &user.CreatedAt, &user.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// SetProvider sets the provider a user belongs to
+func (r *UserRepositoryImpl) SetProvider(ctx context.Context, userID int64, providerID string) error {
+	query := `UPDATE users SET provider_id = $2, updated_at = NOW() WHERE id = $1 AND ($3 = '' OR tenant_id = $3)`
+
+	tag, err := r.pool.Exec(ctx, query, userID, providerID, tenantOf(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set user provider: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
 // UpdateBalance update user balance
 func (r *UserRepositoryImpl) UpdateBalance(ctx context.Context, userID int64, balance decimal.Decimal, tx pgx.Tx) error {
 	query := `
         UPDATE users 
         SET balance = $1, version = version + 1, updated_at = NOW()
-        WHERE id = $2`
+        WHERE id = $2 AND ($3 = '' OR tenant_id = $3)`
 
-	commandTag, err := tx.Exec(ctx, query, balance, userID)
+	commandTag, err := tx.Exec(ctx, query, balance, userID, tenantOf(ctx))
 	if err != nil {
 		var pgErr *pgconn.PgError
 		// check if error is constraint violation, CONSTRAINT balance_non_negative CHECK (balance >= 0)
@@ -81,3 +125,124 @@ func (r *UserRepositoryImpl) UpdateBalance(ctx context.Context, userID int64, ba
 	}
 	return nil
 }
+
+// UpdateDebt update user debt, changed together with the balance so the version is bumped by UpdateBalance
+func (r *UserRepositoryImpl) UpdateDebt(ctx context.Context, userID int64, debt decimal.Decimal, tx pgx.Tx) error {
+	query := `
+        UPDATE users
+        SET debt = $1, updated_at = NOW()
+        WHERE id = $2 AND ($3 = '' OR tenant_id = $3)`
+
+	commandTag, err := tx.Exec(ctx, query, debt, userID, tenantOf(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update debt: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdateBonus update the user's bonus balance, remaining wagering and expiry
+func (r *UserRepositoryImpl) UpdateBonus(ctx context.Context, userID int64, bonus, wagering decimal.Decimal, expiresAt *time.Time, tx pgx.Tx) error {
+	query := `
+        UPDATE users
+        SET bonus_balance = $1, bonus_wagering = $2, bonus_expires_at = $3, updated_at = NOW()
+        WHERE id = $4 AND ($5 = '' OR tenant_id = $5)`
+
+	commandTag, err := tx.Exec(ctx, query, bonus, wagering, expiresAt, userID, tenantOf(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update bonus: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdateReserved update the funds held for withdrawals in progress
+func (r *UserRepositoryImpl) UpdateReserved(ctx context.Context, userID int64, reserved decimal.Decimal, tx pgx.Tx) error {
+	query := `
+        UPDATE users
+        SET reserved = $1, updated_at = NOW()
+        WHERE id = $2 AND ($3 = '' OR tenant_id = $3)`
+
+	commandTag, err := tx.Exec(ctx, query, reserved, userID, tenantOf(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update reserved funds: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+// GetBalanceDrifts recomputes every balance from the opening balance, archived totals, the transactions still applied
+// (processed, cancellation_failed, and the shortfall of partial cancellations) and the bonus grants net of expiries,
+// returning the users whose stored balance plus bonus and reserved funds minus debt differs
+func (r *UserRepositoryImpl) GetBalanceDrifts(ctx context.Context) ([]*model.BalanceDrift, error) {
+	query := `
+        SELECT u.id, u.balance + u.bonus_balance + u.reserved - u.debt,
+               u.opening_balance + u.archived_net + COALESCE(t.net, 0) + COALESCE(b.net, 0) AS expected
+        FROM users u
+        LEFT JOIN (
+            SELECT user_id, SUM(CASE WHEN state = 'win' THEN 1 ELSE -1 END *
+                                CASE WHEN status = 'cancelled' THEN cancel_shortfall ELSE amount END) AS net
+            FROM transactions
+            WHERE status IN ('processed', 'cancellation_failed') OR (status = 'cancelled' AND cancel_policy = 'partial')
+            GROUP BY user_id
+        ) t ON t.user_id = u.id
+        LEFT JOIN (
+            SELECT user_id, SUM(CASE WHEN kind = 'granted' THEN amount ELSE -amount END) AS net
+            FROM bonus_entries
+            GROUP BY user_id
+        ) b ON b.user_id = u.id
+        WHERE u.balance + u.bonus_balance + u.reserved - u.debt <> u.opening_balance + u.archived_net + COALESCE(t.net, 0) + COALESCE(b.net, 0)
+          AND ($1 = '' OR u.tenant_id = $1)
+        ORDER BY u.id`
+
+	rows, err := r.pool.Query(ctx, query, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance drifts: %w", err)
+	}
+	defer rows.Close()
+
+	var drifts []*model.BalanceDrift
+	for rows.Next() {
+		drift := &model.BalanceDrift{}
+		if err := rows.Scan(&drift.UserID, &drift.Balance, &drift.Expected); err != nil {
+			return nil, fmt.Errorf("failed to scan balance drift: %w", err)
+		}
+		drift.Drift = drift.Balance.Sub(drift.Expected)
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
+
+// SetMutationsPaused pauses or resumes balance mutations for the given users, returning the ids that changed
+func (r *UserRepositoryImpl) SetMutationsPaused(ctx context.Context, userIDs []int64, paused bool) ([]int64, error) {
+	query := `
+        UPDATE users
+        SET mutations_paused = $2, updated_at = NOW()
+        WHERE id = ANY($1) AND mutations_paused <> $2 AND ($3 = '' OR tenant_id = $3)
+        RETURNING id`
+
+	rows, err := r.pool.Query(ctx, query, userIDs, paused, tenantOf(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set mutations paused: %w", err)
+	}
+	defer rows.Close()
+
+	var changed []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user id: %w", err)
+		}
+		changed = append(changed, id)
+	}
+	return changed, nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Ensure implementation satisfies interface at compile time
+var _ repository.WorkerStateRepository = (*WorkerStateRepositoryImpl)(nil)
+
+// WorkerStateRepositoryImpl is the PostgreSQL implementation of WorkerStateRepository
+type WorkerStateRepositoryImpl struct {
+	*TransactionManager
+}
+
+func NewWorkerStateRepository(pool *pgxpool.Pool) repository.WorkerStateRepository {
+	return &WorkerStateRepositoryImpl{
+		TransactionManager: NewTransactionManager(pool),
+	}
+}
+
+// GetWorkerState retrieves the state of a worker, a worker without stored state is not paused
+func (r *WorkerStateRepositoryImpl) GetWorkerState(ctx context.Context, name string) (*model.WorkerState, error) {
+	query := `
+        SELECT name, paused, COALESCE(updated_by, ''), updated_at, COALESCE(interval_ms, 0)
+        FROM worker_state
+        WHERE name = $1`
+
+	state := &model.WorkerState{}
+	var intervalMs int64
+	err := r.pool.QueryRow(ctx, query, name).Scan(&state.Name, &state.Paused, &state.UpdatedBy, &state.UpdatedAt, &intervalMs)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &model.WorkerState{Name: name}, nil
+		}
+		return nil, fmt.Errorf("failed to get worker state: %w", err)
+	}
+	state.Interval = time.Duration(intervalMs) * time.Millisecond
+	return state, nil
+}
+
+// SetWorkerPaused pauses or resumes a worker
+func (r *WorkerStateRepositoryImpl) SetWorkerPaused(ctx context.Context, name string, paused bool, operator string) error {
+	query := `
+        INSERT INTO worker_state (name, paused, updated_by, updated_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (name) DO UPDATE
+        SET paused = EXCLUDED.paused,
+            updated_by = EXCLUDED.updated_by,
+            updated_at = NOW()`
+
+	_, err := r.pool.Exec(ctx, query, name, paused, operator)
+	if err != nil {
+		return fmt.Errorf("failed to set worker state: %w", err)
+	}
+	return nil
+}
+
+// SetWorkerInterval stores the interval between runs of a worker, zero restores the configured schedule
+func (r *WorkerStateRepositoryImpl) SetWorkerInterval(ctx context.Context, name string, interval time.Duration) error {
+	query := `
+        INSERT INTO worker_state (name, interval_ms)
+        VALUES ($1, NULLIF($2, 0))
+        ON CONFLICT (name) DO UPDATE
+        SET interval_ms = EXCLUDED.interval_ms`
+
+	_, err := r.pool.Exec(ctx, query, name, interval.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to set worker interval: %w", err)
+	}
+	return nil
+}
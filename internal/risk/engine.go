@@ -0,0 +1,140 @@
+// Package risk evaluates declarative fraud rules against an incoming transaction and the user's recent history.
+package risk
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// Action is the outcome of a rule or of the whole engine
+type Action string
+
+const (
+	ActionAllow Action = "allow"
+	ActionFlag  Action = "flag"
+	ActionBlock Action = "block"
+)
+
+// severity orders actions so the strictest matching rule wins
+func (a Action) severity() int {
+	switch a {
+	case ActionBlock:
+		return 2
+	case ActionFlag:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Input is everything a rule may look at. Recent holds the user's transactions created within
+// the engine's Lookback, newest first.
+type Input struct {
+	UserID     int64
+	SourceType model.SourceType
+	State      model.State
+	Amount     decimal.Decimal
+	Now        time.Time
+	Recent     []*model.Transaction
+	AverageWin decimal.Decimal
+	WinCount   int
+}
+
+// Decision is the engine verdict together with the names of the rules that matched
+type Decision struct {
+	Action Action
+	Rules  []string
+}
+
+// Rule is a single fraud check
+type Rule interface {
+	Name() string
+	Action() Action
+	// Lookback is how much history the rule needs in Input.Recent
+	Lookback() time.Duration
+	Matches(in *Input) bool
+}
+
+// Engine evaluates a fixed set of rules. A nil Engine allows everything.
+type Engine struct {
+	rules []Rule
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Evaluate runs every rule and returns the strictest action among the matches
+func (e *Engine) Evaluate(in *Input) Decision {
+	decision := Decision{Action: ActionAllow}
+	if e == nil {
+		return decision
+	}
+
+	for _, rule := range e.rules {
+		if !rule.Matches(in) {
+			continue
+		}
+		decision.Rules = append(decision.Rules, rule.Name())
+		if rule.Action().severity() > decision.Action.severity() {
+			decision.Action = rule.Action()
+		}
+	}
+	return decision
+}
+
+// Lookback returns the longest history window required by any rule
+func (e *Engine) Lookback() time.Duration {
+	var lookback time.Duration
+	if e == nil {
+		return lookback
+	}
+	for _, rule := range e.rules {
+		lookback = max(lookback, rule.Lookback())
+	}
+	return lookback
+}
+
+// Empty reports whether there is nothing to evaluate, letting callers skip loading history
+func (e *Engine) Empty() bool {
+	return e == nil || len(e.rules) == 0
+}
+
+// LoadFile reads rules from a YAML or JSON file
+func LoadFile(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risk rules: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse builds an engine from a YAML or JSON document
+func Parse(data []byte) (*Engine, error) {
+	var doc struct {
+		Rules []ruleConfig `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse risk rules: %w", err)
+	}
+
+	names := make(map[string]bool, len(doc.Rules))
+	rules := make([]Rule, 0, len(doc.Rules))
+	for i, cfg := range doc.Rules {
+		rule, err := cfg.build()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i, cfg.Name, err)
+		}
+		if names[rule.Name()] {
+			return nil, fmt.Errorf("rule %d: duplicate name %q", i, rule.Name())
+		}
+		names[rule.Name()] = true
+		rules = append(rules, rule)
+	}
+	return NewEngine(rules...), nil
+}
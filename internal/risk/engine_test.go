@@ -0,0 +1,116 @@
+package risk
+
+import (
+	"testing"
+	"time"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, time.May, 16, 12, 0, 0, 0, time.UTC)
+
+func recentTransaction(state model.State, source model.SourceType, ago time.Duration) *model.Transaction {
+	return &model.Transaction{State: state, SourceType: source, Amount: decimal.NewFromInt(10), CreatedAt: now.Add(-ago)}
+}
+
+func TestWinVelocityRule(t *testing.T) {
+	rule := NewWinVelocityRule("rapid_wins", ActionFlag, 2, 10*time.Minute, true)
+
+	in := &Input{
+		SourceType: model.SourceGame,
+		State:      model.StateWin,
+		Now:        now,
+		Recent: []*model.Transaction{
+			recentTransaction(model.StateWin, model.SourceGame, time.Minute),
+			recentTransaction(model.StateWin, model.SourceServer, 2*time.Minute),
+			recentTransaction(model.StateWin, model.SourceGame, 20*time.Minute),
+		},
+	}
+	assert.False(t, rule.Matches(in), "only one earlier win from the same source inside the window")
+
+	in.Recent = append([]*model.Transaction{recentTransaction(model.StateWin, model.SourceGame, 30*time.Second)}, in.Recent...)
+	assert.True(t, rule.Matches(in))
+
+	in.State = model.StateLost
+	assert.False(t, rule.Matches(in), "lost transactions are not wins")
+}
+
+func TestWinAmountMultipleRule(t *testing.T) {
+	rule := NewWinAmountMultipleRule("outsized_win", ActionFlag, decimal.NewFromInt(10), 3)
+
+	in := &Input{State: model.StateWin, Amount: decimal.NewFromInt(101), AverageWin: decimal.NewFromInt(10), WinCount: 3}
+	assert.True(t, rule.Matches(in))
+
+	in.Amount = decimal.NewFromInt(100)
+	assert.False(t, rule.Matches(in))
+
+	in.Amount = decimal.NewFromInt(1000)
+	in.WinCount = 2
+	assert.False(t, rule.Matches(in), "not enough history")
+}
+
+func TestWinLostAlternationRule(t *testing.T) {
+	rule := NewWinLostAlternationRule("ping_pong", ActionBlock, 5*time.Minute, 3)
+
+	in := &Input{
+		State: model.StateWin,
+		Now:   now,
+		Recent: []*model.Transaction{
+			recentTransaction(model.StateLost, model.SourceGame, time.Minute),
+			recentTransaction(model.StateWin, model.SourceGame, 2*time.Minute),
+			recentTransaction(model.StateLost, model.SourceGame, 3*time.Minute),
+		},
+	}
+	assert.True(t, rule.Matches(in))
+
+	in.Recent[2].CreatedAt = now.Add(-10 * time.Minute)
+	assert.False(t, rule.Matches(in), "oldest switch is outside the window")
+}
+
+func TestEngine_EvaluatePicksStrictestAction(t *testing.T) {
+	engine := NewEngine(
+		NewWinAmountMultipleRule("outsized_win", ActionFlag, decimal.NewFromInt(2), 1),
+		NewWinVelocityRule("any_win", ActionBlock, 0, time.Minute, false),
+	)
+
+	decision := engine.Evaluate(&Input{State: model.StateWin, Amount: decimal.NewFromInt(50), AverageWin: decimal.NewFromInt(10), WinCount: 5, Now: now})
+
+	assert.Equal(t, ActionBlock, decision.Action)
+	assert.Equal(t, []string{"outsized_win", "any_win"}, decision.Rules)
+	assert.Equal(t, time.Minute, engine.Lookback())
+}
+
+func TestEngine_NilAllowsEverything(t *testing.T) {
+	var engine *Engine
+
+	assert.True(t, engine.Empty())
+	assert.Equal(t, ActionAllow, engine.Evaluate(&Input{}).Action)
+}
+
+func TestParse(t *testing.T) {
+	engine, err := Parse([]byte(`
+rules:
+  - name: rapid_wins
+    type: win_velocity
+    action: flag
+    max_count: 5
+    window: 10m
+    same_source: true
+  - name: outsized_win
+    type: win_amount_multiple
+    action: flag
+    multiplier: 20
+    min_history: 5
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, engine.Lookback())
+
+	_, err = Parse([]byte(`{"rules": [{"name": "x", "type": "win_velocity", "action": "deny", "max_count": 1, "window": "1m"}]}`))
+	assert.ErrorContains(t, err, "action must be")
+
+	_, err = Parse([]byte(`{"rules": [{"name": "x", "type": "unknown", "action": "flag"}]}`))
+	assert.ErrorContains(t, err, "unknown rule type")
+}
@@ -0,0 +1,162 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rule types accepted in the rules file
+const (
+	TypeWinVelocity        = "win_velocity"
+	TypeWinAmountMultiple  = "win_amount_multiple"
+	TypeWinLostAlternation = "win_lost_alternation"
+)
+
+// ruleConfig is the declarative form of a rule, only the fields of its type are used
+type ruleConfig struct {
+	Name   string `yaml:"name"`
+	Type   string `yaml:"type"`
+	Action Action `yaml:"action"`
+
+	// win_velocity, win_lost_alternation
+	Window time.Duration `yaml:"window"`
+
+	// win_velocity
+	MaxCount   int  `yaml:"max_count"`
+	SameSource bool `yaml:"same_source"`
+
+	// win_amount_multiple
+	Multiplier float64 `yaml:"multiplier"`
+	MinHistory int     `yaml:"min_history"`
+
+	// win_lost_alternation
+	MinAlternations int `yaml:"min_alternations"`
+}
+
+func (c ruleConfig) build() (Rule, error) {
+	if c.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if c.Action != ActionFlag && c.Action != ActionBlock {
+		return nil, fmt.Errorf("action must be %q or %q", ActionFlag, ActionBlock)
+	}
+
+	base := baseRule{name: c.Name, action: c.Action}
+	switch c.Type {
+	case TypeWinVelocity:
+		if c.Window <= 0 || c.MaxCount <= 0 {
+			return nil, errors.New("window and max_count must be positive")
+		}
+		return &WinVelocityRule{baseRule: base, MaxCount: c.MaxCount, Window: c.Window, SameSource: c.SameSource}, nil
+	case TypeWinAmountMultiple:
+		if c.Multiplier <= 0 {
+			return nil, errors.New("multiplier must be positive")
+		}
+		return &WinAmountMultipleRule{baseRule: base, Multiplier: decimal.NewFromFloat(c.Multiplier), MinHistory: c.MinHistory}, nil
+	case TypeWinLostAlternation:
+		if c.Window <= 0 || c.MinAlternations <= 0 {
+			return nil, errors.New("window and min_alternations must be positive")
+		}
+		return &WinLostAlternationRule{baseRule: base, Window: c.Window, MinAlternations: c.MinAlternations}, nil
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", c.Type)
+	}
+}
+
+type baseRule struct {
+	name   string
+	action Action
+}
+
+func (r baseRule) Name() string   { return r.name }
+func (r baseRule) Action() Action { return r.action }
+
+// WinVelocityRule matches when the incoming win would be more than MaxCount wins within Window
+type WinVelocityRule struct {
+	baseRule
+	MaxCount   int
+	Window     time.Duration
+	SameSource bool
+}
+
+func NewWinVelocityRule(name string, action Action, maxCount int, window time.Duration, sameSource bool) *WinVelocityRule {
+	return &WinVelocityRule{baseRule: baseRule{name: name, action: action}, MaxCount: maxCount, Window: window, SameSource: sameSource}
+}
+
+func (r *WinVelocityRule) Lookback() time.Duration { return r.Window }
+
+func (r *WinVelocityRule) Matches(in *Input) bool {
+	if in.State != model.StateWin {
+		return false
+	}
+
+	since := in.Now.Add(-r.Window)
+	wins := 1 // the incoming transaction
+	for _, trans := range in.Recent {
+		if trans.State != model.StateWin || trans.CreatedAt.Before(since) {
+			continue
+		}
+		if r.SameSource && trans.SourceType != in.SourceType {
+			continue
+		}
+		wins++
+	}
+	return wins > r.MaxCount
+}
+
+// WinAmountMultipleRule matches a win more than Multiplier times the user's average win,
+// once the user has at least MinHistory wins
+type WinAmountMultipleRule struct {
+	baseRule
+	Multiplier decimal.Decimal
+	MinHistory int
+}
+
+func NewWinAmountMultipleRule(name string, action Action, multiplier decimal.Decimal, minHistory int) *WinAmountMultipleRule {
+	return &WinAmountMultipleRule{baseRule: baseRule{name: name, action: action}, Multiplier: multiplier, MinHistory: minHistory}
+}
+
+func (r *WinAmountMultipleRule) Lookback() time.Duration { return 0 }
+
+func (r *WinAmountMultipleRule) Matches(in *Input) bool {
+	if in.State != model.StateWin || in.WinCount < max(r.MinHistory, 1) || !in.AverageWin.IsPositive() {
+		return false
+	}
+	return in.Amount.GreaterThan(in.AverageWin.Mul(r.Multiplier))
+}
+
+// WinLostAlternationRule matches when the states within Window, including the incoming one,
+// switch between win and lost at least MinAlternations times
+type WinLostAlternationRule struct {
+	baseRule
+	Window          time.Duration
+	MinAlternations int
+}
+
+func NewWinLostAlternationRule(name string, action Action, window time.Duration, minAlternations int) *WinLostAlternationRule {
+	return &WinLostAlternationRule{baseRule: baseRule{name: name, action: action}, Window: window, MinAlternations: minAlternations}
+}
+
+func (r *WinLostAlternationRule) Lookback() time.Duration { return r.Window }
+
+func (r *WinLostAlternationRule) Matches(in *Input) bool {
+	since := in.Now.Add(-r.Window)
+
+	alternations := 0
+	previous := in.State
+	// Recent is newest first, so walk backwards in time from the incoming transaction
+	for _, trans := range in.Recent {
+		if trans.CreatedAt.Before(since) {
+			break
+		}
+		if trans.State != previous {
+			alternations++
+		}
+		previous = trans.State
+	}
+	return alternations >= r.MinAlternations
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var from = time.Date(2024, time.May, 16, 12, 7, 30, 0, time.UTC) // a Thursday
+
+func TestParseCron_Next(t *testing.T) {
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.May, 16, 12, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.May, 16, 12, 15, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2024, time.May, 16, 12, 25, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, time.May, 17, 3, 0, 0, 0, time.UTC)},
+		{"30 9-17 * * 1-5", time.Date(2024, time.May, 16, 12, 30, 0, 0, time.UTC)},
+		{"0 0 * * 6,7", time.Date(2024, time.May, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 20th or any Monday
+		{"0 0 20 * 1", time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 25 * 0", time.Date(2024, time.May, 19, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.May, 16, 13, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+			assert.Equal(t, tt.expr, schedule.String())
+		})
+	}
+}
+
+func TestParseCron_NeverMatches(t *testing.T) {
+	schedule, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(from).IsZero())
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"@often",
+	} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestNew(t *testing.T) {
+	schedule, err := New("", 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, from.Add(5*time.Minute), schedule.Next(from))
+	assert.Equal(t, "5m0s", schedule.String())
+
+	schedule, err = New("@daily", 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "@daily", schedule.String())
+
+	_, err = New("", 0)
+	assert.Error(t, err)
+}
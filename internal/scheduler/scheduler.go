@@ -0,0 +1,424 @@
+// Package scheduler runs named background jobs on interval or cron schedules, one run per job at a time,
+// and records every run in the job_runs table.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// Run triggers
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+var ErrStopped = errors.New("scheduler is stopped")
+
+// Leader reports whether scheduled runs are due on this instance
+type Leader interface {
+	IsLeader() bool
+}
+
+// Job is a named unit of background work
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Run does the work, the result is recorded with the run
+	Run func(ctx context.Context) (any, error)
+	// Paused is checked before scheduled runs and manual triggers, optional
+	Paused func(ctx context.Context) (bool, error)
+	// Jitter delays each scheduled run by a random duration below it, defaults to the scheduler's
+	Jitter time.Duration
+	// Timeout cancels the context of a run that takes longer, defaults to the scheduler's
+	Timeout time.Duration
+}
+
+// JobStatus is the schedule and last run of a job on this instance
+type JobStatus struct {
+	Name     string
+	Schedule string
+	Running  bool
+	// NextRunAt is zero while the schedule has no next run
+	NextRunAt time.Time
+	LastRun   *model.JobRun
+}
+
+type job struct {
+	Job
+	// runMu allows one run at a time, scheduled or manual
+	runMu      sync.Mutex
+	reschedule chan struct{}
+
+	// guarded by Scheduler.mu
+	running   bool
+	nextRunAt time.Time
+	lastRun   *model.JobRun
+}
+
+type Scheduler struct {
+	runRepo   repository.JobRunRepository
+	leader    Leader
+	jitter    time.Duration
+	timeout   time.Duration
+	retention time.Duration
+	instance  string
+	logger    zerolog.Logger
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	stopped bool
+	stop    chan struct{}
+	// active counts job loops that are running
+	active int
+	// runs are detached from the contexts that start them and cancelled by a Shutdown that times out
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+	// loops counts job loops, runs counts runs in flight
+	loops sync.WaitGroup
+	runs  sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler, leader is optional (every instance runs scheduled jobs without it).
+// jitter and timeout are the defaults of jobs that set none, runs older than retention are deleted.
+func NewScheduler(runRepo repository.JobRunRepository, leader Leader, jitter, timeout, retention time.Duration, logger zerolog.Logger) *Scheduler {
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+	return &Scheduler{
+		runRepo:    runRepo,
+		leader:     leader,
+		jitter:     jitter,
+		timeout:    timeout,
+		retention:  retention,
+		instance:   instance,
+		logger:     logger,
+		jobs:       make(map[string]*job),
+		stop:       make(chan struct{}),
+		runCtx:     runCtx,
+		cancelRuns: cancelRuns,
+	}
+}
+
+// Register adds a job, it is scheduled once Start is called
+func (s *Scheduler) Register(j Job) error {
+	if j.Name == "" || j.Run == nil || j.Schedule == nil {
+		return fmt.Errorf("job %q needs a name, a schedule and a run function", j.Name)
+	}
+	if j.Jitter == 0 {
+		j.Jitter = s.jitter
+	}
+	if j.Timeout == 0 {
+		j.Timeout = s.timeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[j.Name]; ok {
+		return fmt.Errorf("job %q is already registered", j.Name)
+	}
+	s.jobs[j.Name] = &job{Job: j, reschedule: make(chan struct{}, 1)}
+	return nil
+}
+
+// Start schedules every registered job until the context is cancelled or Shutdown is called
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		s.loops.Add(1)
+		s.active++
+		go s.loop(ctx, j)
+		s.logger.Info().Str("job", j.Name).Str("schedule", j.Schedule.String()).Msg("Job scheduled")
+	}
+}
+
+// Shutdown stops scheduling and waits for runs in flight. When the context is done first,
+// the runs are cancelled and its error is returned once they have returned.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stop)
+	}
+	s.mu.Unlock()
+	s.loops.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.cancelRuns()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Healthy returns an error unless every registered job is being scheduled
+func (s *Scheduler) Healthy() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return ErrStopped
+	}
+	if s.active < len(s.jobs) {
+		return fmt.Errorf("%d of %d jobs are not scheduled", len(s.jobs)-s.active, len(s.jobs))
+	}
+	return nil
+}
+
+// IsLeader reports whether scheduled runs are due on this instance
+func (s *Scheduler) IsLeader() bool {
+	return s.leader == nil || s.leader.IsLeader()
+}
+
+// SetSchedule changes the schedule of a job, the next run is computed from now
+func (s *Scheduler) SetSchedule(name string, schedule Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("unknown job %q", name)
+	}
+	j.Schedule = schedule
+
+	// A pending signal already makes the loop pick up the new schedule
+	select {
+	case j.reschedule <- struct{}{}:
+	default:
+	}
+
+	s.logger.Info().Str("job", name).Str("schedule", schedule.String()).Msg("Job schedule changed")
+	return nil
+}
+
+// Status returns the schedule and last run of a job on this instance
+func (s *Scheduler) Status(name string) (*JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown job %q", name)
+	}
+	return &JobStatus{
+		Name:      j.Name,
+		Schedule:  j.Schedule.String(),
+		Running:   j.running,
+		NextRunAt: j.nextRunAt,
+		LastRun:   j.lastRun,
+	}, nil
+}
+
+// Trigger runs a job now on this instance, whether leader or not. It returns ErrWorkerPaused if the job is paused
+// and ErrWorkerBusy if it is already running. The run is not cut short if the caller goes away.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*model.JobRun, error) {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown job %q", name)
+	}
+
+	if paused, err := s.paused(ctx, j); err != nil {
+		return nil, err
+	} else if paused {
+		return nil, model.ErrWorkerPaused
+	}
+
+	return s.run(ctx, j, TriggerManual)
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.loops.Done()
+	defer func() {
+		s.mu.Lock()
+		s.active--
+		s.mu.Unlock()
+	}()
+
+	for {
+		next := s.next(j)
+		// A schedule without next run waits for a new schedule
+		var fire <-chan time.Time
+		var timer *time.Timer
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			fire = timer.C
+		}
+
+		select {
+		case <-fire:
+			s.scheduledRun(ctx, j)
+		case <-j.reschedule:
+		case <-s.stop:
+			s.logger.Info().Str("job", j.Name).Msg("Job stopping")
+			return
+		case <-ctx.Done():
+			s.logger.Info().Str("job", j.Name).Msg("Job stopping (context done)")
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// next computes the next run time of a job with its jitter
+func (s *Scheduler) next(j *job) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := j.Schedule.Next(time.Now())
+	if !next.IsZero() && j.Jitter > 0 {
+		next = next.Add(rand.N(j.Jitter))
+	}
+	j.nextRunAt = next
+	return next
+}
+
+func (s *Scheduler) scheduledRun(ctx context.Context, j *job) {
+	if !s.IsLeader() {
+		s.logger.Debug().Str("job", j.Name).Msg("Not leader, skipping scheduled run")
+		return
+	}
+
+	paused, err := s.paused(ctx, j)
+	if err != nil {
+		s.logger.Error().Err(err).Str("job", j.Name).Msg("Failed to check whether job is paused, skipping run")
+		return
+	}
+	if paused {
+		s.logger.Debug().Str("job", j.Name).Msg("Job paused, skipping run")
+		return
+	}
+
+	s.logger.Debug().Str("job", j.Name).Msg("Running job")
+	if _, err := s.run(ctx, j, TriggerSchedule); err != nil {
+		if errors.Is(err, model.ErrWorkerBusy) {
+			s.logger.Warn().Str("job", j.Name).Msg("Previous run still in progress, skipping run")
+			return
+		}
+		s.logger.Error().Err(err).Str("job", j.Name).Msg("Job run failed")
+	}
+}
+
+func (s *Scheduler) paused(ctx context.Context, j *job) (bool, error) {
+	if j.Paused == nil {
+		return false, nil
+	}
+	return j.Paused(ctx)
+}
+
+// run executes a job and records the run, returning the run with the job's error.
+// The run keeps the values of ctx but only ends early on timeout or a Shutdown that times out.
+func (s *Scheduler) run(ctx context.Context, j *job, trigger string) (*model.JobRun, error) {
+	if !j.runMu.TryLock() {
+		return nil, model.ErrWorkerBusy
+	}
+	defer j.runMu.Unlock()
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil, ErrStopped
+	}
+	s.runs.Add(1)
+	j.running = true
+	s.mu.Unlock()
+	defer s.runs.Done()
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stopCancel := context.AfterFunc(s.runCtx, cancel)
+	defer stopCancel()
+
+	run := &model.JobRun{
+		Job:       j.Name,
+		Trigger:   trigger,
+		Instance:  s.instance,
+		Status:    model.JobRunRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	// Run history must not stop the job, a run without ID is not finished in the table either
+	if err := s.runRepo.CreateJobRun(ctx, run); err != nil {
+		s.logger.Error().Err(err).Str("job", j.Name).Msg("Failed to record job run")
+	}
+
+	runCtx := ctx
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	result, err := s.execute(runCtx, j)
+
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+	run.Result = result
+	run.Status = model.JobRunSucceeded
+	if err != nil {
+		run.Status = model.JobRunFailed
+		run.Error = err.Error()
+	}
+	s.record(ctx, run)
+
+	s.mu.Lock()
+	j.running = false
+	j.lastRun = run
+	s.mu.Unlock()
+
+	return run, err
+}
+
+// execute calls the job, turning a panic into an error
+func (s *Scheduler) execute(ctx context.Context, j *job) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error().Str("job", j.Name).Interface("panic", r).Bytes("stack", debug.Stack()).Msg("Job panicked")
+			result, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.Run(ctx)
+}
+
+// record finishes the run in the history and prunes old runs, also when the run was cancelled
+func (s *Scheduler) record(ctx context.Context, run *model.JobRun) {
+	if run.ID == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if err := s.runRepo.FinishJobRun(ctx, run); err != nil {
+		s.logger.Error().Err(err).Str("job", run.Job).Msg("Failed to record job run result")
+	}
+	if s.retention > 0 {
+		if _, err := s.runRepo.DeleteJobRunsBefore(ctx, run.Job, time.Now().UTC().Add(-s.retention)); err != nil {
+			s.logger.Error().Err(err).Str("job", run.Job).Msg("Failed to delete old job runs")
+		}
+	}
+}
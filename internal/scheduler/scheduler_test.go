@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type staticLeader bool
+
+func (l staticLeader) IsLeader() bool { return bool(l) }
+
+// recordingRepo expects every run to be created and finished
+func recordingRepo(t *testing.T) *mocks.JobRunRepository {
+	repo := mocks.NewJobRunRepository(t)
+	repo.On("CreateJobRun", mock.Anything, mock.AnythingOfType("*model.JobRun")).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.JobRun).ID = 1
+	}).Return(nil).Maybe()
+	repo.On("FinishJobRun", mock.Anything, mock.AnythingOfType("*model.JobRun")).Return(nil).Maybe()
+	repo.On("DeleteJobRunsBefore", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil).Maybe()
+	return repo
+}
+
+func TestScheduler_Trigger_RecordsRun(t *testing.T) {
+	repo := recordingRepo(t)
+	s := NewScheduler(repo, nil, 0, time.Minute, 24*time.Hour, zerolog.Nop())
+	require.NoError(t, s.Register(Job{
+		Name:     "count",
+		Schedule: Every(time.Hour),
+		Run: func(ctx context.Context) (any, error) {
+			return 3, nil
+		},
+	}))
+
+	run, err := s.Trigger(context.Background(), "count")
+
+	require.NoError(t, err)
+	assert.Equal(t, TriggerManual, run.Trigger)
+	assert.Equal(t, model.JobRunSucceeded, run.Status)
+	assert.Equal(t, 3, run.Result)
+	require.NotNil(t, run.FinishedAt)
+	repo.AssertCalled(t, "FinishJobRun", mock.Anything, run)
+	repo.AssertCalled(t, "DeleteJobRunsBefore", mock.Anything, "count", mock.Anything)
+
+	status, err := s.Status("count")
+	require.NoError(t, err)
+	assert.Same(t, run, status.LastRun)
+	assert.False(t, status.Running)
+}
+
+func TestScheduler_Trigger_RecoversPanic(t *testing.T) {
+	s := NewScheduler(recordingRepo(t), nil, 0, time.Minute, 0, zerolog.Nop())
+	require.NoError(t, s.Register(Job{
+		Name:     "panics",
+		Schedule: Every(time.Hour),
+		Run: func(ctx context.Context) (any, error) {
+			panic("boom")
+		},
+	}))
+
+	run, err := s.Trigger(context.Background(), "panics")
+
+	assert.EqualError(t, err, "panic: boom")
+	assert.Equal(t, model.JobRunFailed, run.Status)
+	assert.Equal(t, "panic: boom", run.Error)
+}
+
+func TestScheduler_Trigger_Paused(t *testing.T) {
+	s := NewScheduler(mocks.NewJobRunRepository(t), nil, 0, time.Minute, 0, zerolog.Nop())
+	require.NoError(t, s.Register(Job{
+		Name:     "paused",
+		Schedule: Every(time.Hour),
+		Run: func(ctx context.Context) (any, error) {
+			t.Fatal("paused job must not run")
+			return nil, nil
+		},
+		Paused: func(ctx context.Context) (bool, error) {
+			return true, nil
+		},
+	}))
+
+	_, err := s.Trigger(context.Background(), "paused")
+
+	assert.ErrorIs(t, err, model.ErrWorkerPaused)
+}
+
+func TestScheduler_Trigger_Busy(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := NewScheduler(recordingRepo(t), nil, 0, time.Minute, 0, zerolog.Nop())
+	require.NoError(t, s.Register(Job{
+		Name:     "slow",
+		Schedule: Every(time.Hour),
+		Run: func(ctx context.Context) (any, error) {
+			close(started)
+			<-release
+			return nil, nil
+		},
+	}))
+
+	done := make(chan error)
+	go func() {
+		_, err := s.Trigger(context.Background(), "slow")
+		done <- err
+	}()
+	<-started
+
+	_, err := s.Trigger(context.Background(), "slow")
+	assert.ErrorIs(t, err, model.ErrWorkerBusy)
+
+	close(release)
+	assert.NoError(t, <-done)
+}
+
+func TestScheduler_Trigger_Timeout(t *testing.T) {
+	s := NewScheduler(recordingRepo(t), nil, 0, 10*time.Millisecond, 0, zerolog.Nop())
+	require.NoError(t, s.Register(Job{
+		Name:     "stuck",
+		Schedule: Every(time.Hour),
+		Run: func(ctx context.Context) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}))
+
+	run, err := s.Trigger(context.Background(), "stuck")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, model.JobRunFailed, run.Status)
+}
+
+func TestScheduler_RunsOnScheduleOnlyOnLeader(t *testing.T) {
+	for _, leader := range []bool{true, false} {
+		ran := make(chan struct{}, 10)
+		s := NewScheduler(recordingRepo(t), staticLeader(leader), 0, time.Minute, 0, zerolog.Nop())
+		require.NoError(t, s.Register(Job{
+			Name:     "tick",
+			Schedule: Every(5 * time.Millisecond),
+			Run: func(ctx context.Context) (any, error) {
+				ran <- struct{}{}
+				return nil, nil
+			},
+		}))
+
+		s.Start(context.Background())
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, s.Shutdown(context.Background()))
+
+		assert.Equal(t, leader, len(ran) > 0, "leader %v", leader)
+	}
+}
+
+func TestScheduler_Shutdown_CancelsRunsOnTimeout(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	s := NewScheduler(recordingRepo(t), nil, 0, time.Minute, 0, zerolog.Nop())
+	require.NoError(t, s.Register(Job{
+		Name:     "long",
+		Schedule: Every(time.Hour),
+		Run: func(ctx context.Context) (any, error) {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		},
+	}))
+
+	go s.Trigger(context.Background(), "long")
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := s.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	<-cancelled
+
+	_, err = s.Trigger(context.Background(), "long")
+	assert.ErrorIs(t, err, ErrStopped)
+}
+
+func TestScheduler_SetSchedule(t *testing.T) {
+	s := NewScheduler(mocks.NewJobRunRepository(t), nil, 0, time.Minute, 0, zerolog.Nop())
+	require.NoError(t, s.Register(Job{
+		Name:     "job",
+		Schedule: Every(time.Hour),
+		Run:      func(ctx context.Context) (any, error) { return nil, nil },
+	}))
+
+	require.NoError(t, s.SetSchedule("job", Every(time.Minute)))
+	status, err := s.Status("job")
+	require.NoError(t, err)
+	assert.Equal(t, "1m0s", status.Schedule)
+
+	assert.Error(t, s.SetSchedule("missing", Every(time.Minute)))
+	assert.Error(t, s.Register(Job{Name: "job", Schedule: Every(time.Hour), Run: func(ctx context.Context) (any, error) { return nil, nil }}))
+}
+
+func TestScheduler_Healthy(t *testing.T) {
+	s := NewScheduler(mocks.NewJobRunRepository(t), nil, 0, time.Minute, 0, zerolog.Nop())
+	require.NoError(t, s.Register(Job{
+		Name:     "job",
+		Schedule: Every(time.Hour),
+		Run:      func(ctx context.Context) (any, error) { return nil, nil },
+	}))
+	assert.EqualError(t, s.Healthy(), "1 of 1 jobs are not scheduled")
+
+	s.Start(context.Background())
+	assert.NoError(t, s.Healthy())
+
+	require.NoError(t, s.Shutdown(context.Background()))
+	assert.ErrorIs(t, s.Healthy(), ErrStopped)
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type AdjustmentServiceImpl struct {
+	userRepo        repository.UserRepository
+	transactionRepo repository.TransactionRepository
+	adjustmentRepo  repository.AdjustmentRepository
+	dbManager       repository.DBManager
+	logger          zerolog.Logger
+}
+
+func NewAdjustmentService(
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	adjustmentRepo repository.AdjustmentRepository,
+	dbManager repository.DBManager,
+	logger zerolog.Logger,
+) AdjustmentService {
+	return &AdjustmentServiceImpl{
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		adjustmentRepo:  adjustmentRepo,
+		dbManager:       dbManager,
+		logger:          logger,
+	}
+}
+
+func (s *AdjustmentServiceImpl) CreateAdjustment(ctx context.Context, userID int64, amount decimal.Decimal, reason, operator string) (*model.BalanceAdjustment, error) {
+	if amount.IsZero() {
+		return nil, fmt.Errorf("%w: adjustment must not be zero", model.ErrInvalidAmount)
+	}
+	if reason == "" || operator == "" {
+		return nil, errors.New("reason and operator are required")
+	}
+
+	// Fail early for unknown users instead of when the adjustment is approved
+	if _, err := s.userRepo.GetBalance(ctx, userID); err != nil {
+		return nil, fmt.Errorf("get balance: %w", err)
+	}
+
+	adj := &model.BalanceAdjustment{
+		UserID:        userID,
+		Amount:        amount,
+		Reason:        reason,
+		Status:        model.AdjustmentPending,
+		CreatedBy:     operator,
+		TransactionID: uuid.New().String(),
+	}
+
+	if err := s.adjustmentRepo.InsertAdjustment(ctx, adj); err != nil {
+		return nil, fmt.Errorf("insert adjustment: %w", err)
+	}
+
+	s.logger.Info().
+		Int64("adjustment_id", adj.ID).
+		Int64("user_id", userID).
+		Str("amount", amount.StringFixed(2)).
+		Str("operator", operator).
+		Str("reason", reason).
+		Msg("balance adjustment requested")
+
+	return adj, nil
+}
+
+func (s *AdjustmentServiceImpl) ListAdjustments(ctx context.Context, status model.AdjustmentStatus, limit, offset int) ([]*model.BalanceAdjustment, error) {
+	adjustments, err := s.adjustmentRepo.GetAdjustments(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get adjustments: %w", err)
+	}
+
+	return adjustments, nil
+}
+
+func (s *AdjustmentServiceImpl) ApproveAdjustment(ctx context.Context, id int64, operator, note string) (*model.BalanceAdjustment, error) {
+	var result *model.BalanceAdjustment
+
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		adj, err := s.lockPendingAdjustment(ctx, id, operator, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := s.applyAdjustment(ctx, adj, operator, tx); err != nil {
+			return err
+		}
+
+		if err := s.completeAdjustment(ctx, adj, model.AdjustmentApproved, operator, note, tx); err != nil {
+			return err
+		}
+
+		result = adj
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *AdjustmentServiceImpl) RejectAdjustment(ctx context.Context, id int64, operator, note string) (*model.BalanceAdjustment, error) {
+	var result *model.BalanceAdjustment
+
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		adj, err := s.lockPendingAdjustment(ctx, id, operator, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := s.completeAdjustment(ctx, adj, model.AdjustmentRejected, operator, note, tx); err != nil {
+			return err
+		}
+
+		s.logger.Info().
+			Int64("adjustment_id", adj.ID).
+			Int64("user_id", adj.UserID).
+			Str("operator", operator).
+			Msg("balance adjustment rejected")
+
+		result = adj
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// lockPendingAdjustment locks the adjustment row, checks it still awaits a decision
+// and that the deciding operator is not the one who requested it
+func (s *AdjustmentServiceImpl) lockPendingAdjustment(ctx context.Context, id int64, operator string, tx pgx.Tx) (*model.BalanceAdjustment, error) {
+	adj, err := s.adjustmentRepo.GetAdjustmentForUpdate(ctx, id, tx)
+	if err != nil {
+		return nil, fmt.Errorf("get adjustment for update: %w", err)
+	}
+
+	if adj.Status != model.AdjustmentPending {
+		return nil, fmt.Errorf("%w: adjustment %d has status %s", model.ErrAdjustmentNotPending, id, adj.Status)
+	}
+	if adj.CreatedBy == operator {
+		return nil, fmt.Errorf("%w: adjustment %d was requested by %s", model.ErrSelfApproval, id, operator)
+	}
+	return adj, nil
+}
+
+func (s *AdjustmentServiceImpl) completeAdjustment(ctx context.Context, adj *model.BalanceAdjustment, status model.AdjustmentStatus, operator, note string, tx pgx.Tx) error {
+	adj.Status = status
+	adj.DecidedBy = operator
+	adj.DecisionNote = note
+
+	updated, err := s.adjustmentRepo.CompleteAdjustment(ctx, adj, tx)
+	if err != nil {
+		return fmt.Errorf("complete adjustment: %w", err)
+	}
+	if !updated {
+		return fmt.Errorf("%w: adjustment %d", model.ErrAdjustmentNotPending, adj.ID)
+	}
+	return nil
+}
+
+// applyAdjustment changes the balance of the locked user and records the adjustment as a transaction approved by operator
+func (s *AdjustmentServiceImpl) applyAdjustment(ctx context.Context, adj *model.BalanceAdjustment, operator string, tx pgx.Tx) error {
+	user, err := s.userRepo.GetUserForUpdate(ctx, adj.UserID, tx)
+	if err != nil {
+		return fmt.Errorf("get user for update: %w", err)
+	}
+
+	// Credits are recorded as wins and debits as losses so history sums up to the balance
+	state := model.StateWin
+	if adj.Amount.IsNegative() {
+		state = model.StateLost
+	}
+
+	newBalance := user.Balance.Add(adj.Amount)
+	if newBalance.LessThan(decimal.Zero) {
+		return model.ErrInsufficientBalance
+	}
+
+	if err := s.userRepo.UpdateBalance(ctx, adj.UserID, newBalance, tx); err != nil {
+		return fmt.Errorf("update balance: %w", err)
+	}
+
+	trans := &model.Transaction{
+		TransactionID: adj.TransactionID,
+		UserID:        adj.UserID,
+		SourceType:    model.SourceAdjustment,
+		State:         state,
+		Amount:        adj.Amount.Abs(),
+		Status:        model.StatusProcessed,
+	}
+	if err := s.transactionRepo.InsertTransaction(ctx, trans, tx); err != nil {
+		return fmt.Errorf("insert transaction: %w", err)
+	}
+
+	// The approver applies the adjustment, the reason given by the requester is the note
+	err = recordEvent(ctx, s.transactionRepo, trans, &model.TransactionEvent{
+		ActorType:     model.ActorOperator,
+		Actor:         operator,
+		Reason:        model.EventReasonAdjustment,
+		Note:          adj.Reason,
+		BalanceBefore: &user.Balance,
+		BalanceAfter:  &newBalance,
+	}, tx)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info().
+		Int64("adjustment_id", adj.ID).
+		Int64("user_id", adj.UserID).
+		Str("amount", adj.Amount.StringFixed(2)).
+		Str("requested_by", adj.CreatedBy).
+		Str("reason", adj.Reason).
+		Str("old_balance", user.Balance.StringFixed(2)).
+		Str("new_balance", newBalance.StringFixed(2)).
+		Msg("balance adjusted")
+	return nil
+}
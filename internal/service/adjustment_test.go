@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pendingAdjustment(amount int64) *model.BalanceAdjustment {
+	return &model.BalanceAdjustment{
+		ID:            5,
+		UserID:        1,
+		Amount:        decimal.NewFromInt(amount),
+		Reason:        "chargeback",
+		Status:        model.AdjustmentPending,
+		CreatedBy:     "alice",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440099",
+	}
+}
+
+func TestAdjustmentService_CreateAdjustment_Pending(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockAdjustmentRepo := mocks.NewAdjustmentRepository(t)
+
+	mockUserRepo.On("GetBalance", ctx, int64(1)).Return(decimal.NewFromInt(100), nil)
+	mockAdjustmentRepo.On("InsertAdjustment", ctx, mock.MatchedBy(func(adj *model.BalanceAdjustment) bool {
+		return adj.Status == model.AdjustmentPending && adj.CreatedBy == "alice" && adj.TransactionID != ""
+	})).Return(nil)
+
+	service := NewAdjustmentService(mockUserRepo, nil, mockAdjustmentRepo, nil, zerolog.Nop())
+	adj, err := service.CreateAdjustment(ctx, 1, decimal.NewFromInt(-25), "chargeback", "alice")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AdjustmentPending, adj.Status)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestAdjustmentService_CreateAdjustment_ZeroAmount(t *testing.T) {
+	service := NewAdjustmentService(nil, nil, nil, nil, zerolog.Nop())
+	_, err := service.CreateAdjustment(context.Background(), 1, decimal.Zero, "noop", "alice")
+
+	assert.ErrorIs(t, err, model.ErrInvalidAmount)
+}
+
+func TestAdjustmentService_ApproveAdjustment_Debit(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockAdjustmentRepo := mocks.NewAdjustmentRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	mockAdjustmentRepo.On("GetAdjustmentForUpdate", ctx, int64(5), mock.Anything).Return(pendingAdjustment(-25), nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:      1,
+		Balance: decimal.NewFromInt(100),
+	}, nil)
+	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimal.NewFromInt(75), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(t *model.Transaction) bool {
+		return t.SourceType == model.SourceAdjustment &&
+			t.State == model.StateLost &&
+			t.Amount.Equal(decimal.NewFromInt(25)) &&
+			t.Status == model.StatusProcessed
+	}), mock.Anything).Return(nil)
+	mockAdjustmentRepo.On("CompleteAdjustment", ctx, mock.MatchedBy(func(adj *model.BalanceAdjustment) bool {
+		return adj.Status == model.AdjustmentApproved && adj.DecidedBy == "bob"
+	}), mock.Anything).Return(true, nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.ActorType == model.ActorOperator && e.Actor == "bob" && e.Reason == model.EventReasonAdjustment && e.Note == "chargeback"
+	}), mock.Anything).Return(nil)
+
+	service := NewAdjustmentService(mockUserRepo, mockTransRepo, mockAdjustmentRepo, mockDBManager, zerolog.Nop())
+	adj, err := service.ApproveAdjustment(ctx, 5, "bob", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AdjustmentApproved, adj.Status)
+}
+
+func TestAdjustmentService_ApproveAdjustment_SelfApproval(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockAdjustmentRepo := mocks.NewAdjustmentRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	mockAdjustmentRepo.On("GetAdjustmentForUpdate", ctx, int64(5), mock.Anything).Return(pendingAdjustment(50), nil)
+
+	service := NewAdjustmentService(mockUserRepo, nil, mockAdjustmentRepo, mockDBManager, zerolog.Nop())
+	_, err := service.ApproveAdjustment(ctx, 5, "alice", "")
+
+	assert.ErrorIs(t, err, model.ErrSelfApproval)
+	mockUserRepo.AssertNotCalled(t, "GetUserForUpdate")
+}
+
+func TestAdjustmentService_ApproveAdjustment_InsufficientBalance(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockAdjustmentRepo := mocks.NewAdjustmentRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	mockAdjustmentRepo.On("GetAdjustmentForUpdate", ctx, int64(5), mock.Anything).Return(pendingAdjustment(-25), nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:      1,
+		Balance: decimal.NewFromInt(10),
+	}, nil)
+
+	service := NewAdjustmentService(mockUserRepo, nil, mockAdjustmentRepo, mockDBManager, zerolog.Nop())
+	_, err := service.ApproveAdjustment(ctx, 5, "bob", "")
+
+	assert.ErrorIs(t, err, model.ErrInsufficientBalance)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+	mockAdjustmentRepo.AssertNotCalled(t, "CompleteAdjustment")
+}
+
+func TestAdjustmentService_RejectAdjustment_NotPending(t *testing.T) {
+	ctx := context.Background()
+
+	mockAdjustmentRepo := mocks.NewAdjustmentRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	adj := pendingAdjustment(50)
+	adj.Status = model.AdjustmentApproved
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	mockAdjustmentRepo.On("GetAdjustmentForUpdate", ctx, int64(5), mock.Anything).Return(adj, nil)
+
+	service := NewAdjustmentService(nil, nil, mockAdjustmentRepo, mockDBManager, zerolog.Nop())
+	_, err := service.RejectAdjustment(ctx, 5, "bob", "duplicate")
+
+	assert.ErrorIs(t, err, model.ErrAdjustmentNotPending)
+	mockAdjustmentRepo.AssertNotCalled(t, "CompleteAdjustment")
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// AmountLimits holds the per-source and per-provider amount rules applied to incoming transactions
+type AmountLimits struct {
+	// mu guards the maps, which are replaced on config reload
+	mu          sync.RWMutex
+	sourceMin   map[model.SourceType]decimal.Decimal
+	sourceMax   map[model.SourceType]decimal.Decimal
+	providerMin map[string]decimal.Decimal
+	providerMax map[string]decimal.Decimal
+	maxAutoWin  map[model.SourceType]decimal.Decimal
+}
+
+// NewAmountLimits builds the amount rules from configuration, rejecting unknown source types
+func NewAmountLimits(cfg config.LimitsConfig) (*AmountLimits, error) {
+	limits := &AmountLimits{
+		providerMin: cfg.ProviderMinAmount,
+		providerMax: cfg.ProviderMaxAmount,
+	}
+
+	var err error
+	if limits.sourceMin, err = bySourceType(cfg.SourceMinAmount); err != nil {
+		return nil, fmt.Errorf("source min amount: %w", err)
+	}
+	if limits.sourceMax, err = bySourceType(cfg.SourceMaxAmount); err != nil {
+		return nil, fmt.Errorf("source max amount: %w", err)
+	}
+	if limits.maxAutoWin, err = bySourceType(cfg.MaxAutoWin); err != nil {
+		return nil, fmt.Errorf("max auto win: %w", err)
+	}
+	return limits, nil
+}
+
+// Update replaces the rules with reloaded configuration, the current rules stay if the new ones are invalid
+func (l *AmountLimits) Update(cfg config.LimitsConfig) error {
+	next, err := NewAmountLimits(cfg)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sourceMin = next.sourceMin
+	l.sourceMax = next.sourceMax
+	l.providerMin = next.providerMin
+	l.providerMax = next.providerMax
+	l.maxAutoWin = next.maxAutoWin
+	return nil
+}
+
+// Check returns ErrAmountOutOfRange if amount is outside the range allowed for the source or provider
+func (l *AmountLimits) Check(amount decimal.Decimal, sourceType model.SourceType, providerID string) error {
+	if l == nil {
+		return nil
+	}
+
+	min, max := l.SourceRange(sourceType)
+	if err := checkRange(amount, min, max); err != nil {
+		return fmt.Errorf("%w: %s for source %s", model.ErrAmountOutOfRange, err.Error(), sourceType)
+	}
+
+	if providerID == "" {
+		return nil
+	}
+
+	min, max = l.ProviderRange(providerID)
+	if err := checkRange(amount, min, max); err != nil {
+		return fmt.Errorf("%w: %s for provider %s", model.ErrAmountOutOfRange, err.Error(), providerID)
+	}
+	return nil
+}
+
+// RequiresReview reports whether a transaction is too large to be applied without manual review
+func (l *AmountLimits) RequiresReview(state model.State, amount decimal.Decimal, sourceType model.SourceType) bool {
+	if l == nil || state != model.StateWin {
+		return false
+	}
+
+	threshold, ok := l.MaxAutoWin(sourceType)
+	return ok && amount.GreaterThan(threshold)
+}
+
+// SourceRange returns the amount bounds of a source type, a zero bound is not configured
+func (l *AmountLimits) SourceRange(sourceType model.SourceType) (min, max decimal.Decimal) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.sourceMin[sourceType], l.sourceMax[sourceType]
+}
+
+// ProviderRange returns the amount bounds of a provider, a zero bound is not configured
+func (l *AmountLimits) ProviderRange(providerID string) (min, max decimal.Decimal) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.providerMin[providerID], l.providerMax[providerID]
+}
+
+// MaxAutoWin returns the largest win of a source type applied without manual review, if one is configured
+func (l *AmountLimits) MaxAutoWin(sourceType model.SourceType) (decimal.Decimal, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	threshold, ok := l.maxAutoWin[sourceType]
+	return threshold, ok
+}
+
+// checkRange treats a zero bound as not configured
+func checkRange(amount, min, max decimal.Decimal) error {
+	if !min.IsZero() && amount.LessThan(min) {
+		return fmt.Errorf("amount below minimum %s", min.StringFixed(2))
+	}
+	if !max.IsZero() && amount.GreaterThan(max) {
+		return fmt.Errorf("amount above maximum %s", max.StringFixed(2))
+	}
+	return nil
+}
+
+func bySourceType(values map[string]decimal.Decimal) (map[model.SourceType]decimal.Decimal, error) {
+	result := make(map[model.SourceType]decimal.Decimal, len(values))
+	for key, value := range values {
+		sourceType, err := model.ParseSourceType(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, key)
+		}
+		result[sourceType] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountLimits_Check(t *testing.T) {
+	limits, err := NewAmountLimits(config.LimitsConfig{
+		SourceMinAmount:   map[string]decimal.Decimal{"game": decimal.RequireFromString("0.10")},
+		SourceMaxAmount:   map[string]decimal.Decimal{"game": decimal.NewFromInt(500)},
+		ProviderMaxAmount: map[string]decimal.Decimal{"acme": decimal.NewFromInt(100)},
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, limits.Check(decimal.NewFromInt(50), model.SourceGame, ""))
+	assert.NoError(t, limits.Check(decimal.NewFromInt(1000), model.SourceServer, ""))
+	assert.ErrorIs(t, limits.Check(decimal.RequireFromString("0.05"), model.SourceGame, ""), model.ErrAmountOutOfRange)
+	assert.ErrorIs(t, limits.Check(decimal.NewFromInt(501), model.SourceGame, ""), model.ErrAmountOutOfRange)
+	assert.ErrorIs(t, limits.Check(decimal.NewFromInt(200), model.SourceGame, "acme"), model.ErrAmountOutOfRange)
+}
+
+func TestAmountLimits_RequiresReview(t *testing.T) {
+	limits, err := NewAmountLimits(config.LimitsConfig{
+		MaxAutoWin: map[string]decimal.Decimal{"payment": decimal.NewFromInt(1000)},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, limits.RequiresReview(model.StateWin, decimal.NewFromInt(1001), model.SourcePayment))
+	assert.False(t, limits.RequiresReview(model.StateWin, decimal.NewFromInt(1000), model.SourcePayment))
+	assert.False(t, limits.RequiresReview(model.StateLost, decimal.NewFromInt(5000), model.SourcePayment))
+	assert.False(t, limits.RequiresReview(model.StateWin, decimal.NewFromInt(5000), model.SourceGame))
+}
+
+func TestNewAmountLimits_UnknownSourceType(t *testing.T) {
+	_, err := NewAmountLimits(config.LimitsConfig{
+		SourceMaxAmount: map[string]decimal.Decimal{"casino": decimal.NewFromInt(10)},
+	})
+
+	assert.ErrorIs(t, err, model.ErrInvalidSourceType)
+}
+
+func TestAmountLimits_Update(t *testing.T) {
+	limits, err := NewAmountLimits(config.LimitsConfig{
+		SourceMaxAmount: map[string]decimal.Decimal{"game": decimal.NewFromInt(500)},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, limits.Update(config.LimitsConfig{
+		SourceMaxAmount: map[string]decimal.Decimal{"game": decimal.NewFromInt(1000)},
+	}))
+	assert.NoError(t, limits.Check(decimal.NewFromInt(800), model.SourceGame, ""))
+	_, max := limits.SourceRange(model.SourceGame)
+	assert.True(t, max.Equal(decimal.NewFromInt(1000)))
+
+	// Invalid rules are rejected and the current ones kept
+	assert.Error(t, limits.Update(config.LimitsConfig{
+		SourceMaxAmount: map[string]decimal.Decimal{"casino": decimal.NewFromInt(10)},
+	}))
+	assert.ErrorIs(t, limits.Check(decimal.NewFromInt(1001), model.SourceGame, ""), model.ErrAmountOutOfRange)
+}
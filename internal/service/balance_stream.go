@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	listenRetryMin = time.Second
+	listenRetryMax = 30 * time.Second
+)
+
+type BalanceStreamServiceImpl struct {
+	userRepo repository.UserRepository
+	listener repository.BalanceListener
+	logger   zerolog.Logger
+
+	mu          sync.Mutex
+	subscribers map[int64]map[chan *model.BalanceEvent]struct{}
+	closed      bool
+}
+
+func NewBalanceStreamService(userRepo repository.UserRepository, listener repository.BalanceListener, logger zerolog.Logger) BalanceStreamService {
+	return &BalanceStreamServiceImpl{
+		userRepo:    userRepo,
+		listener:    listener,
+		logger:      logger,
+		subscribers: make(map[int64]map[chan *model.BalanceEvent]struct{}),
+	}
+}
+
+func (s *BalanceStreamServiceImpl) Run(ctx context.Context) {
+	// Ends open streams on shutdown, the HTTP server waits for them otherwise
+	defer s.closeAll()
+
+	retry := listenRetryMin
+	for {
+		err := s.listener.Listen(ctx, func() {
+			retry = listenRetryMin
+			// Changes made while the connection was down were not notified
+			s.resync(ctx)
+		}, s.publish)
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.logger.Error().Err(err).Dur("retry_in", retry).Msg("balance listener failed")
+		select {
+		case <-time.After(retry):
+		case <-ctx.Done():
+			return
+		}
+		retry = min(retry*2, listenRetryMax)
+	}
+}
+
+func (s *BalanceStreamServiceImpl) Subscribe(userID int64) (<-chan *model.BalanceEvent, func()) {
+	// Only the latest balance matters, a slow subscriber gets the newest event instead of a backlog
+	ch := make(chan *model.BalanceEvent, 1)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if s.subscribers[userID] == nil {
+		s.subscribers[userID] = make(map[chan *model.BalanceEvent]struct{})
+	}
+	s.subscribers[userID][ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.subscribers[userID], ch)
+			if len(s.subscribers[userID]) == 0 {
+				delete(s.subscribers, userID)
+			}
+		})
+	}
+}
+
+func (s *BalanceStreamServiceImpl) Current(ctx context.Context, userID int64) (*model.BalanceEvent, error) {
+	// The snapshot must not be older than the notifications that follow it
+	user, err := s.userRepo.GetUser(repository.WithReadYourWrites(ctx), userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := auth.CheckUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &model.BalanceEvent{
+		UserID:  user.ID,
+		Balance: user.Balance.StringFixed(2),
+		Version: user.Version,
+	}, nil
+}
+
+// publish delivers an event to every subscriber of the user, replacing an undelivered older event
+func (s *BalanceStreamServiceImpl) publish(event *model.BalanceEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- event
+		}
+	}
+}
+
+// closeAll closes every subscription and rejects new ones
+func (s *BalanceStreamServiceImpl) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for userID, channels := range s.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+		delete(s.subscribers, userID)
+	}
+}
+
+// resync publishes the current balance of every subscribed user
+func (s *BalanceStreamServiceImpl) resync(ctx context.Context) {
+	s.mu.Lock()
+	userIDs := make([]int64, 0, len(s.subscribers))
+	for userID := range s.subscribers {
+		userIDs = append(userIDs, userID)
+	}
+	s.mu.Unlock()
+
+	for _, userID := range userIDs {
+		event, err := s.Current(ctx, userID)
+		if err != nil {
+			s.logger.Warn().Err(err).Int64("user_id", userID).Msg("failed to resync balance stream")
+			continue
+		}
+		s.publish(event)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceStreamService_Run_FansOutToUserSubscribers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockListener := mocks.NewBalanceListener(t)
+
+	svc := NewBalanceStreamService(mockUserRepo, mockListener, zerolog.Nop())
+	user1, unsubscribe1 := svc.Subscribe(1)
+	defer unsubscribe1()
+	user2, unsubscribe2 := svc.Subscribe(2)
+	defer unsubscribe2()
+
+	// Reconnect resync publishes the current balances of subscribed users
+	mockUserRepo.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Balance: decimal.NewFromInt(100), Version: 3}, nil)
+	mockUserRepo.On("GetUser", mock.Anything, int64(2)).Return(&model.User{ID: 2, Balance: decimal.NewFromInt(50), Version: 1}, nil)
+
+	mockListener.On("Listen", mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, ready func(), fn func(*model.BalanceEvent)) error {
+		ready()
+		fn(&model.BalanceEvent{UserID: 1, Balance: "110.00", Version: 4})
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx)
+		close(done)
+	}()
+
+	// Only the latest event is kept for a subscriber that has not read yet
+	require.Eventually(t, func() bool { return len(user1) == 1 && len(user2) == 1 }, time.Second, 10*time.Millisecond)
+	event := <-user1
+	assert.Equal(t, 4, event.Version)
+	assert.Equal(t, "110.00", event.Balance)
+	event = <-user2
+	assert.Equal(t, 1, event.Version)
+
+	cancel()
+	<-done
+
+	_, open := <-user1
+	assert.False(t, open, "subscriptions are closed when the service stops")
+}
+
+func TestBalanceStreamService_Unsubscribe(t *testing.T) {
+	svc := NewBalanceStreamService(nil, nil, zerolog.Nop()).(*BalanceStreamServiceImpl)
+
+	events, unsubscribe := svc.Subscribe(1)
+	unsubscribe()
+	unsubscribe()
+
+	svc.publish(&model.BalanceEvent{UserID: 1, Balance: "10.00", Version: 2})
+
+	assert.Empty(t, events)
+	assert.Empty(t, svc.subscribers)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+// bonusExpiryBatch is the number of users expired per statement
+const bonusExpiryBatch = 500
+
+type BonusServiceImpl struct {
+	userRepo  repository.UserRepository
+	bonusRepo repository.BonusRepository
+	dbManager repository.DBManager
+	policy    *BonusPolicy
+	logger    zerolog.Logger
+}
+
+func NewBonusService(
+	userRepo repository.UserRepository,
+	bonusRepo repository.BonusRepository,
+	dbManager repository.DBManager,
+	policy *BonusPolicy,
+	logger zerolog.Logger,
+) BonusService {
+	return &BonusServiceImpl{
+		userRepo:  userRepo,
+		bonusRepo: bonusRepo,
+		dbManager: dbManager,
+		policy:    policy,
+		logger:    logger,
+	}
+}
+
+func (s *BonusServiceImpl) GrantBonus(ctx context.Context, userID int64, amount decimal.Decimal, wagering *decimal.Decimal, note, operator string) (*model.BonusWallet, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: bonus must be positive", model.ErrInvalidAmount)
+	}
+	if wagering != nil && !wagering.IsPositive() {
+		return nil, fmt.Errorf("%w: wagering must be positive", model.ErrInvalidAmount)
+	}
+	if operator == "" {
+		return nil, errors.New("operator is required")
+	}
+
+	now := time.Now().UTC()
+	defaultWagering, expiresAt := s.policy.grantTerms(amount, now)
+	if wagering == nil {
+		wagering = &defaultWagering
+	}
+
+	var wallet *model.BonusWallet
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		user, err := s.userRepo.GetUserForUpdate(ctx, userID, tx)
+		if err != nil {
+			return fmt.Errorf("get user for update: %w", err)
+		}
+		if user.MutationsPaused {
+			return fmt.Errorf("%w %d", model.ErrMutationsPaused, user.ID)
+		}
+
+		// A new grant adds to the active bonus and extends it
+		wallet = &model.BonusWallet{
+			UserID:    userID,
+			Balance:   user.BonusBalance.Add(amount),
+			Wagering:  user.BonusWagering.Add(*wagering),
+			ExpiresAt: &expiresAt,
+		}
+		if err := s.userRepo.UpdateBonus(ctx, userID, wallet.Balance, wallet.Wagering, wallet.ExpiresAt, tx); err != nil {
+			return fmt.Errorf("update bonus: %w", err)
+		}
+
+		err = s.bonusRepo.InsertBonusEntry(ctx, &model.BonusEntry{
+			UserID:    userID,
+			Kind:      model.BonusGranted,
+			Amount:    amount,
+			Wagering:  *wagering,
+			Actor:     operator,
+			Note:      note,
+			ExpiresAt: &expiresAt,
+		}, tx)
+		if err != nil {
+			return fmt.Errorf("record bonus grant: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Int64("user_id", userID).
+		Str("amount", amount.StringFixed(2)).
+		Str("wagering", wagering.StringFixed(2)).
+		Str("operator", operator).
+		Time("expires_at", expiresAt).
+		Msg("bonus granted")
+
+	return wallet, nil
+}
+
+func (s *BonusServiceImpl) GetBonusWallet(ctx context.Context, userID int64) (*model.BonusWallet, error) {
+	user, err := s.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := auth.CheckUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &model.BonusWallet{
+		UserID:    userID,
+		Balance:   user.BonusBalance,
+		Wagering:  user.BonusWagering,
+		ExpiresAt: user.BonusExpiresAt,
+	}, nil
+}
+
+func (s *BonusServiceImpl) ListBonusEntries(ctx context.Context, userID int64, limit, offset int) ([]*model.BonusEntry, error) {
+	entries, err := s.bonusRepo.GetBonusEntries(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get bonus entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ExpireBonuses removes the bonuses that expired, in batches until none is left
+func (s *BonusServiceImpl) ExpireBonuses(ctx context.Context) (*model.BonusExpiryResult, error) {
+	result := &model.BonusExpiryResult{}
+	now := time.Now().UTC()
+	for {
+		entries, err := s.bonusRepo.ExpireBonuses(ctx, now, bonusExpiryBatch)
+		if err != nil {
+			return result, fmt.Errorf("expire bonuses: %w", err)
+		}
+
+		for _, entry := range entries {
+			result.Expired++
+			result.Amount = result.Amount.Add(entry.Amount)
+			s.logger.Info().
+				Int64("user_id", entry.UserID).
+				Str("amount", entry.Amount.StringFixed(2)).
+				Str("wagering", entry.Wagering.StringFixed(2)).
+				Msg("bonus expired")
+		}
+
+		if len(entries) < bonusExpiryBatch {
+			return result, nil
+		}
+	}
+}
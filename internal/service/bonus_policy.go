@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// BonusPolicy holds the bonus rules: the order lost transactions spend the balances in, the default wagering
+// requirement and how long a bonus lasts
+type BonusPolicy struct {
+	mu                 sync.RWMutex
+	SpendOrder         model.BonusSpendOrder
+	WageringMultiplier decimal.Decimal
+	TTL                time.Duration
+}
+
+// NewBonusPolicy builds the bonus rules from configuration
+func NewBonusPolicy(cfg config.BonusConfig) (*BonusPolicy, error) {
+	order, err := model.ParseBonusSpendOrder(cfg.SpendOrder)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, cfg.SpendOrder)
+	}
+	return &BonusPolicy{
+		SpendOrder:         order,
+		WageringMultiplier: cfg.WageringMultiplier,
+		TTL:                cfg.TTL,
+	}, nil
+}
+
+// Update replaces the rules with reloaded configuration, the current rules stay if the new ones are invalid
+func (p *BonusPolicy) Update(cfg config.BonusConfig) error {
+	next, err := NewBonusPolicy(cfg)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.SpendOrder = next.SpendOrder
+	p.WageringMultiplier = next.WageringMultiplier
+	p.TTL = next.TTL
+	return nil
+}
+
+// spend splits a lost amount the user can cover between the real and bonus balances, in the spend order.
+// Without a policy real money is spent first.
+func (p *BonusPolicy) spend(user *model.User, amount decimal.Decimal) (fromReal, fromBonus decimal.Decimal) {
+	order := model.BonusSpendRealFirst
+	if p != nil {
+		p.mu.RLock()
+		order = p.SpendOrder
+		p.mu.RUnlock()
+	}
+
+	if order == model.BonusSpendBonusFirst {
+		fromBonus = decimal.Min(amount, user.BonusBalance)
+		return amount.Sub(fromBonus), fromBonus
+	}
+	fromReal = decimal.Min(amount, user.Balance)
+	return fromReal, amount.Sub(fromReal)
+}
+
+// grantTerms returns the wagering requirement of a grant that does not set one, and when the bonus expires
+func (p *BonusPolicy) grantTerms(amount decimal.Decimal, now time.Time) (decimal.Decimal, time.Time) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return amount.Mul(p.WageringMultiplier).Round(2), now.Add(p.TTL)
+}
+
+// bonusWinShare is the part of a win credited to the bonus balance: while a bonus is active wins are split
+// in proportion to the user's real and bonus balances
+func bonusWinShare(user *model.User, amount decimal.Decimal) decimal.Decimal {
+	total := user.Balance.Add(user.BonusBalance)
+	if user.BonusExpiresAt == nil || !total.IsPositive() {
+		return decimal.Zero
+	}
+	return amount.Mul(user.BonusBalance).Div(total).Round(2)
+}
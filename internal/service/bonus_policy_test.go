@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+	"time"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBonusPolicy_Spend(t *testing.T) {
+	policy, err := NewBonusPolicy(config.BonusConfig{SpendOrder: "real_first", WageringMultiplier: decimal.NewFromInt(30), TTL: time.Hour})
+	require.NoError(t, err)
+	user := &model.User{Balance: decimal.NewFromInt(30), BonusBalance: decimal.NewFromInt(50)}
+
+	fromReal, fromBonus := policy.spend(user, decimal.NewFromInt(40))
+	assert.True(t, decimal.NewFromInt(30).Equal(fromReal))
+	assert.True(t, decimal.NewFromInt(10).Equal(fromBonus))
+
+	require.NoError(t, policy.Update(config.BonusConfig{SpendOrder: "bonus_first", WageringMultiplier: decimal.NewFromInt(30), TTL: time.Hour}))
+	fromReal, fromBonus = policy.spend(user, decimal.NewFromInt(60))
+	assert.True(t, decimal.NewFromInt(10).Equal(fromReal))
+	assert.True(t, decimal.NewFromInt(50).Equal(fromBonus))
+
+	assert.Error(t, policy.Update(config.BonusConfig{SpendOrder: "random"}))
+	assert.Equal(t, model.BonusSpendBonusFirst, policy.SpendOrder)
+}
+
+func TestBonusWinShare(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	active := &model.User{Balance: decimal.NewFromInt(75), BonusBalance: decimal.NewFromInt(25), BonusExpiresAt: &expiresAt}
+	assert.True(t, decimal.NewFromInt(10).Equal(bonusWinShare(active, decimal.NewFromInt(40))))
+
+	// No active bonus, or nothing left to split by
+	assert.True(t, bonusWinShare(&model.User{Balance: decimal.NewFromInt(75)}, decimal.NewFromInt(40)).IsZero())
+	assert.True(t, bonusWinShare(&model.User{BonusExpiresAt: &expiresAt}, decimal.NewFromInt(40)).IsZero())
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newBonusPolicy(t *testing.T) *BonusPolicy {
+	policy, err := NewBonusPolicy(config.BonusConfig{SpendOrder: "real_first", WageringMultiplier: decimal.NewFromInt(30), TTL: 24 * time.Hour})
+	require.NoError(t, err)
+	return policy
+}
+
+func TestBonusService_GrantBonus(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockBonusRepo := mocks.NewBonusRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:            1,
+		BonusBalance:  decimal.NewFromInt(5),
+		BonusWagering: decimal.NewFromInt(100),
+	}, nil)
+	// The default wagering is the amount times the multiplier, added to what is left of the active bonus
+	mockUserRepo.On("UpdateBonus", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(25))
+	}), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(700))
+	}), mock.Anything, mock.Anything).Return(nil)
+	mockBonusRepo.On("InsertBonusEntry", ctx, mock.MatchedBy(func(e *model.BonusEntry) bool {
+		return e.Kind == model.BonusGranted && e.Amount.Equal(decimal.NewFromInt(20)) && e.Wagering.Equal(decimal.NewFromInt(600)) &&
+			e.Actor == "alice" && e.Note == "welcome"
+	}), mock.Anything).Return(nil)
+
+	service := NewBonusService(mockUserRepo, mockBonusRepo, mockDBManager, newBonusPolicy(t), zerolog.Nop())
+	wallet, err := service.GrantBonus(ctx, 1, decimal.NewFromInt(20), nil, "welcome", "alice")
+
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(25).Equal(wallet.Balance))
+	if assert.NotNil(t, wallet.ExpiresAt) {
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), *wallet.ExpiresAt, time.Minute)
+	}
+}
+
+func TestBonusService_GrantBonus_InvalidAmount(t *testing.T) {
+	service := NewBonusService(nil, nil, nil, newBonusPolicy(t), zerolog.Nop())
+
+	_, err := service.GrantBonus(context.Background(), 1, decimal.Zero, nil, "", "alice")
+	assert.ErrorIs(t, err, model.ErrInvalidAmount)
+
+	wagering := decimal.NewFromInt(-1)
+	_, err = service.GrantBonus(context.Background(), 1, decimal.NewFromInt(10), &wagering, "", "alice")
+	assert.ErrorIs(t, err, model.ErrInvalidAmount)
+}
+
+func TestBonusService_ExpireBonuses(t *testing.T) {
+	ctx := context.Background()
+	mockBonusRepo := mocks.NewBonusRepository(t)
+
+	mockBonusRepo.On("ExpireBonuses", ctx, mock.Anything, bonusExpiryBatch).Return([]*model.BonusEntry{
+		{UserID: 1, Kind: model.BonusExpired, Amount: decimal.NewFromInt(20)},
+		{UserID: 2, Kind: model.BonusExpired, Amount: decimal.RequireFromString("5.50")},
+	}, nil)
+
+	service := NewBonusService(nil, mockBonusRepo, nil, newBonusPolicy(t), zerolog.Nop())
+	result, err := service.ExpireBonuses(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Expired)
+	assert.True(t, decimal.RequireFromString("25.50").Equal(result.Amount))
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 	"transaction-processor/internal/model"
 	"transaction-processor/internal/repository"
 
@@ -11,52 +13,84 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// cancellationWorkerName identifies the automatic cancellation runs in worker_state
+const cancellationWorkerName = "cancellation"
+
+// cancelOutcome is the result of cancelling a locked transaction
+type cancelOutcome int
+
+const (
+	// cancelNotUpdated transactions changed status before the update, e.g. cancelled by another run
+	cancelNotUpdated cancelOutcome = iota
+	cancelCompleted
+	// cancelWithShortfall transactions were cancelled although the balance could not cover the reversal
+	cancelWithShortfall
+	// cancelFailed transactions were marked cancellation_failed and keep their balance effect
+	cancelFailed
+)
+
 type CancellationServiceImpl struct {
 	userRepo        repository.UserRepository
 	transactionRepo repository.TransactionRepository
+	limitRepo       repository.LimitRepository
+	workerStateRepo repository.WorkerStateRepository
 	dbManager       repository.DBManager
+	shortfallPolicy model.ShortfallPolicy
+	tenants         *Tenants
+	debt            *DebtPolicy
 	logger          zerolog.Logger
 }
 
 func NewCancellationService(
 	userRepo repository.UserRepository,
 	transactionRepo repository.TransactionRepository,
+	limitRepo repository.LimitRepository,
+	workerStateRepo repository.WorkerStateRepository,
 	dbManager repository.DBManager,
+	shortfallPolicy model.ShortfallPolicy,
+	tenants *Tenants,
+	debt *DebtPolicy,
 	logger zerolog.Logger,
 ) CancellationService {
 	return &CancellationServiceImpl{
 		userRepo:        userRepo,
 		transactionRepo: transactionRepo,
+		limitRepo:       limitRepo,
+		workerStateRepo: workerStateRepo,
 		dbManager:       dbManager,
+		shortfallPolicy: shortfallPolicy,
+		tenants:         tenants,
+		debt:            debt,
 		logger:          logger,
 	}
 }
 
 // ProcessOddRecordCancellation cancels odd-numbered processed transactions and adjusts user balance
-func (s *CancellationServiceImpl) ProcessOddRecordCancellation(ctx context.Context) error {
-	var cancelledCount int
+func (s *CancellationServiceImpl) ProcessOddRecordCancellation(ctx context.Context) (*model.CancellationResult, error) {
+	result := &model.CancellationResult{}
 
 	// Fetch up to 10 latest odd transactions with 'processed' state
 	transactions, err := s.transactionRepo.GetLatestOddProcessedTransactions(ctx, 10)
 	if err != nil {
-		return fmt.Errorf("get odd transactions: %w", err)
+		return nil, fmt.Errorf("get odd transactions: %w", err)
 	}
 
 	if len(transactions) == 0 {
 		s.logger.Debug().Msg("no odd transactions with 'processed' state to cancel")
-		return nil
+		return result, nil
 	}
+	result.Requested = len(transactions)
 
 	// Process each transaction in its own transaction
 	for _, trans := range transactions {
 		// Stop quickly on shutdown
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return result, ctx.Err()
 		default:
 		}
 
-		var cancelled bool
+		var outcome cancelOutcome
 		err = s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
 			// Lock transaction row to avoid duplicate work under concurrency
 			locked, err := s.transactionRepo.LockTransactionForCancellation(ctx, trans.ID, tx)
@@ -68,79 +102,314 @@ func (s *CancellationServiceImpl) ProcessOddRecordCancellation(ctx context.Conte
 				return nil
 			}
 
-			// Get user with lock
-			user, err := s.userRepo.GetUserForUpdate(ctx, trans.UserID, tx)
-			if err != nil {
-				return fmt.Errorf("get user for update: %w", err)
-			}
-
-			// Reverse the transaction (+/-)
-			// "win" originally adds to user balance, so cancellation subtracts it back
-			newBalance := user.Balance
-			switch trans.State {
-			case model.StateWin:
-				// Reverse win = subtract
-				newBalance = newBalance.Sub(trans.Amount)
-			case model.StateLost:
-				// Reverse lost = add
-				newBalance = newBalance.Add(trans.Amount)
-			}
-
-			// Check balance constraint
-			if newBalance.LessThan(decimal.Zero) {
+			outcome, err = s.cancelLocked(ctx, trans, &model.TransactionEvent{
+				ActorType: model.ActorWorker,
+				Actor:     cancellationWorkerName,
+				Reason:    model.EventReasonOddRecord,
+			}, s.shortfallPolicy, tx)
+			if errors.Is(err, model.ErrMutationsPaused) {
 				s.logger.Warn().
 					Str("transaction_id", trans.TransactionID).
 					Int64("user_id", trans.UserID).
-					Str("current_balance", user.Balance.StringFixed(2)).
-					Str("would_be_balance", newBalance.StringFixed(2)).
-					Msg("cannot cancel transaction: negative balance not allowed")
-				return nil
-			}
-
-			err = s.userRepo.UpdateBalance(ctx, trans.UserID, newBalance, tx)
-			if err != nil {
-				return fmt.Errorf("update balance: %w", err)
-			}
-
-			// Update transaction status, if current status is 'processed'
-			updated, err := s.transactionRepo.CancelTransactionIfProcessed(ctx, trans.ID, tx)
-			if err != nil {
-				return fmt.Errorf("update transaction status: %w", err)
-			}
-
-			if !updated {
-				s.logger.Warn().Str("transaction_id", trans.TransactionID).Msg("transaction status not updated - may have been already cancelled")
+					Msg("cannot cancel transaction: user mutations are paused")
 				return nil
 			}
-
-			s.logger.Info().
-				Str("transaction_id", trans.TransactionID).
-				Int64("user_id", trans.UserID).
-				Str("original_state", trans.State.String()).
-				Str("amount", trans.Amount.StringFixed(2)).
-				Str("old_balance", user.Balance.StringFixed(2)).
-				Str("new_balance", newBalance.StringFixed(2)).
-				Msg("transaction cancelled and balance adjusted")
-			cancelled = true
-			return nil
+			return err
 		})
 
-		if err != nil {
+		switch {
+		case err != nil:
 			s.logger.Error().
 				Err(err).
 				Str("transaction_id", trans.TransactionID).
 				Int64("user_id", trans.UserID).
 				Msg("failed to cancel transaction")
-		}
-		if cancelled {
-			cancelledCount++
+			result.Failed++
+		case outcome == cancelCompleted:
+			result.Cancelled++
+		case outcome == cancelWithShortfall:
+			result.Cancelled++
+			result.Shortfalls++
+		case outcome == cancelFailed:
+			result.CancellationFailed++
+		default:
+			result.Skipped++
 		}
 	}
 
 	s.logger.Info().
-		Int("requested", len(transactions)).
-		Int("cancelled", cancelledCount).
+		Int("requested", result.Requested).
+		Int("cancelled", result.Cancelled).
+		Int("shortfalls", result.Shortfalls).
+		Int("cancellation_failed", result.CancellationFailed).
+		Int("skipped", result.Skipped).
+		Int("failed", result.Failed).
 		Msg("odd transactions cancellation completed")
 
+	return result, nil
+}
+
+// GetWorkerState returns whether automatic cancellation runs are paused
+func (s *CancellationServiceImpl) GetWorkerState(ctx context.Context) (*model.WorkerState, error) {
+	state, err := s.workerStateRepo.GetWorkerState(ctx, cancellationWorkerName)
+	if err != nil {
+		return nil, fmt.Errorf("get worker state: %w", err)
+	}
+	return state, nil
+}
+
+// SetWorkerPaused pauses or resumes automatic cancellation runs on all instances
+func (s *CancellationServiceImpl) SetWorkerPaused(ctx context.Context, paused bool, operator string) error {
+	if err := s.workerStateRepo.SetWorkerPaused(ctx, cancellationWorkerName, paused, operator); err != nil {
+		return fmt.Errorf("set worker state: %w", err)
+	}
+
+	s.logger.Info().Bool("paused", paused).Str("operator", operator).Msg("automatic cancellation state changed")
+	return nil
+}
+
+// SetWorkerInterval changes the interval between automatic cancellation runs on all instances,
+// zero restores the configured schedule
+func (s *CancellationServiceImpl) SetWorkerInterval(ctx context.Context, interval time.Duration, operator string) error {
+	if err := s.workerStateRepo.SetWorkerInterval(ctx, cancellationWorkerName, interval); err != nil {
+		return fmt.Errorf("set worker interval: %w", err)
+	}
+
+	s.logger.Info().Dur("interval", interval).Str("operator", operator).Msg("automatic cancellation interval changed")
 	return nil
 }
+
+// CancelTransaction cancels a single processed transaction on operator request, waiting for row locks.
+// Transactions whose automatic cancellation failed can be cancelled once the balance covers the reversal,
+// the shortfall policy does not apply: a reversal the balance cannot cover fails with ErrInsufficientBalance.
+// Payment settlements are not cancellable, the provider has already moved the money.
+func (s *CancellationServiceImpl) CancelTransaction(ctx context.Context, transactionID, operator string, reason model.CancelReason, note string) (*model.Transaction, error) {
+	var result *model.Transaction
+
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		trans, err := s.transactionRepo.GetTransactionForUpdate(ctx, transactionID, tx)
+		if err != nil {
+			return fmt.Errorf("get transaction for update: %w", err)
+		}
+
+		if trans.Status != model.StatusProcessed && trans.Status != model.StatusCancellationFailed {
+			return fmt.Errorf("%w: transaction %s has status %s", model.ErrNotCancellable, transactionID, trans.Status)
+		}
+		if trans.SourceType == model.SourceSettlement {
+			return fmt.Errorf("%w: transaction %s settles a payment", model.ErrNotCancellable, transactionID)
+		}
+
+		outcome, err := s.cancelLocked(ctx, trans, &model.TransactionEvent{
+			ActorType: model.ActorOperator,
+			Actor:     operator,
+			Reason:    string(reason),
+			Note:      note,
+		}, "", tx)
+		if err != nil {
+			return err
+		}
+		if outcome != cancelCompleted {
+			return fmt.Errorf("%w: transaction %s", model.ErrNotCancellable, transactionID)
+		}
+
+		s.logger.Info().
+			Str("transaction_id", trans.TransactionID).
+			Str("operator", operator).
+			Str("reason", string(reason)).
+			Msg("transaction cancelled manually")
+
+		result = trans
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListCancellationShortfalls returns the transactions whose cancellation failed or left a shortfall, newest first
+func (s *CancellationServiceImpl) ListCancellationShortfalls(ctx context.Context, limit, offset int) ([]*model.Transaction, error) {
+	transactions, err := s.transactionRepo.GetCancellationShortfalls(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get cancellation shortfalls: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// cancelLocked reverses the balance effect of a transaction whose row is locked, marks it cancelled with the reason
+// of the event and records the event, which carries the actor, reason and note.
+// The bonus part of the transaction is reversed against the bonus balance while the bonus is active.
+// When the balance cannot cover the reversal the policy applies, an empty policy returns ErrInsufficientBalance
+// without changing anything. The debt policy falls back to skip for source types not in debt mode.
+// Returns ErrMutationsPaused if the user is paused by the integrity check.
+func (s *CancellationServiceImpl) cancelLocked(ctx context.Context, trans *model.Transaction, event *model.TransactionEvent, policy model.ShortfallPolicy, tx pgx.Tx) (cancelOutcome, error) {
+	// Get user with lock
+	user, err := s.userRepo.GetUserForUpdate(ctx, trans.UserID, tx)
+	if err != nil {
+		return cancelNotUpdated, fmt.Errorf("get user for update: %w", err)
+	}
+
+	if user.MutationsPaused {
+		return cancelNotUpdated, fmt.Errorf("%w %d", model.ErrMutationsPaused, user.ID)
+	}
+
+	// The worker applies the policy of the user's tenant, operators' cancellations never apply one
+	if policy != "" {
+		policy = s.tenants.ShortfallPolicy(user.TenantID, policy)
+	}
+
+	// Reverse the transaction (+/-)
+	// "win" originally adds to user balance, so cancellation subtracts it back
+	newBalance := user.Balance
+	newBonus := user.BonusBalance
+	switch trans.State {
+	case model.StateWin:
+		// Reverse win = subtract, the bonus part from what is left of the bonus and the rest from the balance
+		fromBonus := decimal.Min(trans.BonusAmount, newBonus)
+		newBonus = newBonus.Sub(fromBonus)
+		newBalance = newBalance.Sub(trans.Amount.Sub(fromBonus))
+	case model.StateLost:
+		// Reverse lost = add, the bonus part goes back to an active bonus and to the balance once it expired or was released
+		if user.BonusExpiresAt != nil {
+			newBonus = newBonus.Add(trans.BonusAmount)
+			newBalance = newBalance.Add(trans.Amount.Sub(trans.BonusAmount))
+		} else {
+			newBalance = newBalance.Add(trans.Amount)
+		}
+	}
+
+	// Apply the shortfall policy when the balance cannot cover the reversal
+	reversed := trans.Amount
+	newDebt := user.Debt
+	var shortfall *decimal.Decimal
+	if newBalance.IsNegative() {
+		missing := newBalance.Neg()
+		if policy == model.ShortfallDebt && !s.debt.Enabled(trans.SourceType) {
+			policy = model.ShortfallSkip
+		}
+		switch policy {
+		case model.ShortfallDebt:
+			shortfall = &missing
+			newDebt = newDebt.Add(missing)
+			newBalance = decimal.Zero
+		case model.ShortfallPartial:
+			shortfall = &missing
+			reversed = trans.Amount.Sub(missing)
+			newBalance = decimal.Zero
+		case model.ShortfallSkip:
+			return s.failLocked(ctx, trans, user, missing, event, tx)
+		default:
+			return cancelNotUpdated, fmt.Errorf("%w: balance %s would become %s",
+				model.ErrInsufficientBalance, user.Balance.StringFixed(2), newBalance.StringFixed(2))
+		}
+	}
+
+	err = s.userRepo.UpdateBalance(ctx, trans.UserID, newBalance, tx)
+	if err != nil {
+		return cancelNotUpdated, fmt.Errorf("update balance: %w", err)
+	}
+
+	if !newDebt.Equal(user.Debt) {
+		if err := s.userRepo.UpdateDebt(ctx, trans.UserID, newDebt, tx); err != nil {
+			return cancelNotUpdated, fmt.Errorf("update debt: %w", err)
+		}
+	}
+
+	if !newBonus.Equal(user.BonusBalance) {
+		if err := s.userRepo.UpdateBonus(ctx, trans.UserID, newBonus, user.BonusWagering, user.BonusExpiresAt, tx); err != nil {
+			return cancelNotUpdated, fmt.Errorf("update bonus: %w", err)
+		}
+	}
+
+	// Update transaction status, if current status is 'processed' or 'cancellation_failed'
+	var shortfallPolicy model.ShortfallPolicy
+	if shortfall != nil {
+		shortfallPolicy = policy
+	}
+	updated, err := s.transactionRepo.CancelTransactionIfProcessed(ctx, trans.ID, event.Reason, shortfallPolicy, shortfall, tx)
+	if err != nil {
+		return cancelNotUpdated, fmt.Errorf("update transaction status: %w", err)
+	}
+
+	if !updated {
+		s.logger.Warn().Str("transaction_id", trans.TransactionID).Msg("transaction status not updated - may have been already cancelled")
+		return cancelNotUpdated, nil
+	}
+
+	// Undo the reversed part's effect on the net loss of its window (ignored once the window is over)
+	err = s.limitRepo.AddNetLoss(ctx, trans.UserID, netLossDelta(trans.State, reversed).Neg(), trans.CreatedAt, tx)
+	if err != nil {
+		return cancelNotUpdated, fmt.Errorf("update loss counters: %w", err)
+	}
+
+	now := time.Now().UTC()
+	event.FromStatus = trans.Status
+	event.BalanceBefore = &user.Balance
+	event.BalanceAfter = &newBalance
+	withWallets(event, user, newDebt, newBonus)
+	trans.Status = model.StatusCancelled
+	trans.CancelledAt = &now
+	trans.CancelReason = event.Reason
+	trans.CancelPolicy = shortfallPolicy
+	trans.CancelShortfall = shortfall
+	if err := recordEvent(ctx, s.transactionRepo, trans, event, tx); err != nil {
+		return cancelNotUpdated, err
+	}
+
+	if shortfall != nil {
+		s.logger.Warn().
+			Str("transaction_id", trans.TransactionID).
+			Int64("user_id", trans.UserID).
+			Str("policy", string(policy)).
+			Str("shortfall", shortfall.StringFixed(2)).
+			Str("old_balance", user.Balance.StringFixed(2)).
+			Str("new_balance", newBalance.StringFixed(2)).
+			Str("debt", newDebt.StringFixed(2)).
+			Msg("transaction cancelled with a shortfall")
+		return cancelWithShortfall, nil
+	}
+
+	s.logger.Info().
+		Str("transaction_id", trans.TransactionID).
+		Int64("user_id", trans.UserID).
+		Str("original_state", trans.State.String()).
+		Str("amount", trans.Amount.StringFixed(2)).
+		Str("old_balance", user.Balance.StringFixed(2)).
+		Str("new_balance", newBalance.StringFixed(2)).
+		Msg("transaction cancelled and balance adjusted")
+	return cancelCompleted, nil
+}
+
+// failLocked marks a locked transaction cancellation_failed with the shortfall, so it is reported instead of
+// being retried by every run. The balance is not changed.
+func (s *CancellationServiceImpl) failLocked(ctx context.Context, trans *model.Transaction, user *model.User, shortfall decimal.Decimal, event *model.TransactionEvent, tx pgx.Tx) (cancelOutcome, error) {
+	updated, err := s.transactionRepo.MarkCancellationFailed(ctx, trans.ID, event.Reason, shortfall, tx)
+	if err != nil {
+		return cancelNotUpdated, fmt.Errorf("mark cancellation failed: %w", err)
+	}
+	if !updated {
+		return cancelNotUpdated, nil
+	}
+
+	event.FromStatus = trans.Status
+	event.BalanceBefore = &user.Balance
+	event.BalanceAfter = &user.Balance
+	event.Note = fmt.Sprintf("balance %s cannot cover the reversal of %s", user.Balance.StringFixed(2), trans.Amount.StringFixed(2))
+	trans.Status = model.StatusCancellationFailed
+	trans.CancelReason = event.Reason
+	trans.CancelPolicy = model.ShortfallSkip
+	trans.CancelShortfall = &shortfall
+	if err := recordEvent(ctx, s.transactionRepo, trans, event, tx); err != nil {
+		return cancelNotUpdated, err
+	}
+
+	s.logger.Warn().
+		Str("transaction_id", trans.TransactionID).
+		Int64("user_id", trans.UserID).
+		Str("balance", user.Balance.StringFixed(2)).
+		Str("shortfall", shortfall.StringFixed(2)).
+		Msg("cannot cancel transaction: negative balance not allowed, marked cancellation_failed")
+	return cancelFailed, nil
+}
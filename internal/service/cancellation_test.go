@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"testing"
+	"transaction-processor/internal/config"
 	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
 	"transaction-processor/mocks/repository"
 
 	"github.com/jackc/pgx/v5"
@@ -11,6 +13,7 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCancellationService_ProcessOddRecordCancellation_Success(t *testing.T) {
@@ -19,6 +22,7 @@ func TestCancellationService_ProcessOddRecordCancellation_Success(t *testing.T)
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
 	transactions := []*model.Transaction{
@@ -42,12 +46,145 @@ func TestCancellationService_ProcessOddRecordCancellation_Success(t *testing.T)
 		Version: 1,
 	}, nil)
 	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimal.NewFromInt(100), mock.Anything).Return(nil)
-	mockTransRepo.On("CancelTransactionIfProcessed", ctx, int64(1), mock.Anything).Return(true, nil)
+	mockTransRepo.On("CancelTransactionIfProcessed", ctx, int64(1), model.EventReasonOddRecord, model.ShortfallPolicy(""), (*decimal.Decimal)(nil), mock.Anything).Return(true, nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockLimitRepo.On("AddNetLoss", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(100))
+	}), mock.Anything, mock.Anything).Return(nil)
 
-	service := NewCancellationService(mockUserRepo, mockTransRepo, mockDBManager, logger)
-	err := service.ProcessOddRecordCancellation(ctx)
+	service := NewCancellationService(mockUserRepo, mockTransRepo, mockLimitRepo, nil, mockDBManager, model.ShortfallSkip, nil, nil, logger)
+	result, err := service.ProcessOddRecordCancellation(ctx)
 
 	assert.NoError(t, err)
+	assert.Equal(t, &model.CancellationResult{Requested: 1, Cancelled: 1}, result)
+}
+
+func TestCancellationService_ProcessOddRecordCancellation_CountsSkippedAndFailed(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	transactions := []*model.Transaction{
+		{ID: 1, UserID: 1, State: model.StateWin, Amount: decimal.NewFromInt(100), Status: model.StatusProcessed},
+		{ID: 3, UserID: 2, State: model.StateWin, Amount: decimal.NewFromInt(100), Status: model.StatusProcessed},
+		{ID: 5, UserID: 3, State: model.StateWin, Amount: decimal.NewFromInt(100), Status: model.StatusProcessed},
+	}
+
+	mockTransRepo.On("GetLatestOddProcessedTransactions", ctx, 10).Return(transactions, nil)
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	// Claimed by another run
+	mockTransRepo.On("LockTransactionForCancellation", ctx, int64(1), mock.Anything).Return(false, nil)
+	// Balance too low, marked cancellation_failed
+	mockTransRepo.On("LockTransactionForCancellation", ctx, int64(3), mock.Anything).Return(true, nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(2), mock.Anything).Return(&model.User{ID: 2, Balance: decimal.NewFromInt(50)}, nil)
+	mockTransRepo.On("MarkCancellationFailed", ctx, int64(3), model.EventReasonOddRecord, mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(50))
+	}), mock.Anything).Return(true, nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.FromStatus == model.StatusProcessed && e.ToStatus == model.StatusCancellationFailed &&
+			e.BalanceAfter.Equal(decimal.NewFromInt(50))
+	}), mock.Anything).Return(nil)
+	// Database error
+	mockTransRepo.On("LockTransactionForCancellation", ctx, int64(5), mock.Anything).Return(false, assert.AnError)
+
+	service := NewCancellationService(mockUserRepo, mockTransRepo, nil, nil, mockDBManager, model.ShortfallSkip, nil, nil, logger)
+	result, err := service.ProcessOddRecordCancellation(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.CancellationResult{Requested: 3, CancellationFailed: 1, Skipped: 1, Failed: 1}, result)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestCancellationService_ProcessOddRecordCancellation_ShortfallPolicies(t *testing.T) {
+	tenants, err := ParseTenants([]byte("tenants:\n  - id: brand-a\n    api_keys_sha256: [" + keyHash("key-a") + "]\n    shortfall_policy: partial\n"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		policy     model.ShortfallPolicy
+		newBalance decimal.Decimal
+		netLoss    decimal.Decimal
+		newDebt    decimal.Decimal
+		// configured replaces policy as the global setting when the user's tenant sets its own policy
+		configured model.ShortfallPolicy
+		tenants    *Tenants
+	}{
+		{"debt reverses the full amount", model.ShortfallDebt, decimal.Zero, decimal.NewFromInt(100), decimal.NewFromInt(60), "", nil},
+		{"partial reverses what the balance covers", model.ShortfallPartial, decimal.Zero, decimal.NewFromInt(50), decimal.NewFromInt(10), "", nil},
+		{"tenant policy replaces the global one", model.ShortfallPartial, decimal.Zero, decimal.NewFromInt(50), decimal.NewFromInt(10),
+			model.ShortfallSkip, tenants},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockUserRepo := mocks.NewUserRepository(t)
+			mockTransRepo := mocks.NewTransactionRepository(t)
+			mockLimitRepo := mocks.NewLimitRepository(t)
+			mockDBManager := mocks.NewDBManager(t)
+
+			mockTransRepo.On("GetLatestOddProcessedTransactions", ctx, 10).Return([]*model.Transaction{
+				{ID: 1, UserID: 1, SourceType: model.SourceGame, State: model.StateWin, Amount: decimal.NewFromInt(100), Status: model.StatusProcessed},
+			}, nil)
+			mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+				return fn(nil)
+			})
+			mockTransRepo.On("LockTransactionForCancellation", ctx, int64(1), mock.Anything).Return(true, nil)
+			mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{ID: 1, TenantID: "brand-a", Balance: decimal.NewFromInt(50), Debt: decimal.NewFromInt(10)}, nil)
+			mockUserRepo.On("UpdateBalance", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+				return d.Equal(tt.newBalance)
+			}), mock.Anything).Return(nil)
+			if !tt.newDebt.Equal(decimal.NewFromInt(10)) {
+				mockUserRepo.On("UpdateDebt", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+					return d.Equal(tt.newDebt)
+				}), mock.Anything).Return(nil)
+			}
+			mockTransRepo.On("CancelTransactionIfProcessed", ctx, int64(1), model.EventReasonOddRecord, tt.policy, mock.MatchedBy(func(d *decimal.Decimal) bool {
+				return d != nil && d.Equal(decimal.NewFromInt(50))
+			}), mock.Anything).Return(true, nil)
+			mockLimitRepo.On("AddNetLoss", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+				return d.Equal(tt.netLoss)
+			}), mock.Anything, mock.Anything).Return(nil)
+			mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+				return e.ToStatus == model.StatusCancelled && e.BalanceAfter.Equal(tt.newBalance) &&
+					(e.DebtAfter == nil || e.DebtAfter.Equal(tt.newDebt))
+			}), mock.Anything).Return(nil)
+
+			debt, err := NewDebtPolicy(config.DebtConfig{SourceTypes: []string{"game"}})
+			require.NoError(t, err)
+
+			configured := tt.policy
+			if tt.configured != "" {
+				configured = tt.configured
+			}
+			service := NewCancellationService(mockUserRepo, mockTransRepo, mockLimitRepo, nil, mockDBManager, configured, tt.tenants, debt, zerolog.Nop())
+			result, err := service.ProcessOddRecordCancellation(ctx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, &model.CancellationResult{Requested: 1, Cancelled: 1, Shortfalls: 1}, result)
+		})
+	}
+}
+
+func TestCancellationService_SetWorkerPaused(t *testing.T) {
+	ctx := context.Background()
+	mockWorkerStateRepo := mocks.NewWorkerStateRepository(t)
+
+	mockWorkerStateRepo.On("SetWorkerPaused", ctx, "cancellation", true, "ops").Return(nil)
+	mockWorkerStateRepo.On("GetWorkerState", ctx, "cancellation").Return(&model.WorkerState{Name: "cancellation", Paused: true, UpdatedBy: "ops"}, nil)
+
+	service := NewCancellationService(nil, nil, nil, mockWorkerStateRepo, nil, model.ShortfallSkip, nil, nil, zerolog.Nop())
+	assert.NoError(t, service.SetWorkerPaused(ctx, true, "ops"))
+
+	state, err := service.GetWorkerState(ctx)
+	assert.NoError(t, err)
+	assert.True(t, state.Paused)
 }
 
 func TestCancellationService_ProcessOddRecordCancellation_NoTransactionsToCancel(t *testing.T) {
@@ -56,17 +193,163 @@ func TestCancellationService_ProcessOddRecordCancellation_NoTransactionsToCancel
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
 	mockTransRepo.On("GetLatestOddProcessedTransactions", ctx, 10).Return([]*model.Transaction{}, nil)
 
-	service := NewCancellationService(mockUserRepo, mockTransRepo, mockDBManager, logger)
-	err := service.ProcessOddRecordCancellation(ctx)
+	service := NewCancellationService(mockUserRepo, mockTransRepo, mockLimitRepo, nil, mockDBManager, model.ShortfallSkip, nil, nil, logger)
+	result, err := service.ProcessOddRecordCancellation(ctx)
 
 	assert.NoError(t, err)
+	assert.Zero(t, result.Requested)
 
 	mockUserRepo.AssertNotCalled(t, "GetUserForUpdate")
 	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
 	mockTransRepo.AssertNotCalled(t, "CancelTransactionIfProcessed")
 	mockDBManager.AssertNotCalled(t, "WithTransaction")
 }
+
+func TestCancellationService_CancelTransaction_Success(t *testing.T) {
+	ctx := repository.WithRequestID(context.Background(), "req-1")
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	mockTransRepo.On("GetTransactionForUpdate", ctx, "tx-1", mock.Anything).Return(&model.Transaction{
+		ID:            1,
+		TransactionID: "tx-1",
+		UserID:        1,
+		State:         model.StateLost,
+		Amount:        decimal.NewFromInt(30),
+		Status:        model.StatusProcessed,
+	}, nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:      1,
+		Balance: decimal.NewFromInt(70),
+	}, nil)
+	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimal.NewFromInt(100), mock.Anything).Return(nil)
+	mockTransRepo.On("CancelTransactionIfProcessed", ctx, int64(1), string(model.CancelReasonCustomerRequest), model.ShortfallPolicy(""), (*decimal.Decimal)(nil), mock.Anything).Return(true, nil)
+	mockLimitRepo.On("AddNetLoss", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(-30))
+	}), mock.Anything, mock.Anything).Return(nil)
+
+	var event *model.TransactionEvent
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(*model.TransactionEvent)
+	}).Return(nil)
+
+	service := NewCancellationService(mockUserRepo, mockTransRepo, mockLimitRepo, nil, mockDBManager, model.ShortfallSkip, nil, nil, logger)
+	trans, err := service.CancelTransaction(ctx, "tx-1", "ops", model.CancelReasonCustomerRequest, "ticket #4411")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusCancelled, trans.Status)
+	assert.Equal(t, string(model.CancelReasonCustomerRequest), trans.CancelReason)
+	assert.NotNil(t, trans.CancelledAt)
+
+	if assert.NotNil(t, event) {
+		assert.Equal(t, "tx-1", event.TransactionID)
+		assert.Equal(t, model.StatusProcessed, event.FromStatus)
+		assert.Equal(t, model.StatusCancelled, event.ToStatus)
+		assert.Equal(t, model.ActorOperator, event.ActorType)
+		assert.Equal(t, "ops", event.Actor)
+		assert.Equal(t, string(model.CancelReasonCustomerRequest), event.Reason)
+		assert.Equal(t, "ticket #4411", event.Note)
+		assert.Equal(t, "req-1", event.RequestID)
+		assert.True(t, event.BalanceBefore.Equal(decimal.NewFromInt(70)))
+		assert.True(t, event.BalanceAfter.Equal(decimal.NewFromInt(100)))
+	}
+}
+
+func TestCancellationService_CancelTransaction_NotProcessed(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	mockTransRepo.On("GetTransactionForUpdate", ctx, "tx-1", mock.Anything).Return(&model.Transaction{
+		ID:            1,
+		TransactionID: "tx-1",
+		UserID:        1,
+		Status:        model.StatusCancelled,
+	}, nil)
+
+	service := NewCancellationService(mockUserRepo, mockTransRepo, nil, nil, mockDBManager, model.ShortfallSkip, nil, nil, logger)
+	trans, err := service.CancelTransaction(ctx, "tx-1", "ops", model.CancelReasonOther, "")
+
+	assert.ErrorIs(t, err, model.ErrNotCancellable)
+	assert.Nil(t, trans)
+	mockUserRepo.AssertNotCalled(t, "GetUserForUpdate")
+}
+
+func TestCancellationService_CancelTransaction_Settlement(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	mockTransRepo.On("GetTransactionForUpdate", ctx, "tx-1", mock.Anything).Return(&model.Transaction{
+		ID:            1,
+		TransactionID: "tx-1",
+		UserID:        1,
+		SourceType:    model.SourceSettlement,
+		State:         model.StateLost,
+		Amount:        decimal.NewFromInt(30),
+		Status:        model.StatusProcessed,
+	}, nil)
+
+	service := NewCancellationService(mockUserRepo, mockTransRepo, nil, nil, mockDBManager, model.ShortfallSkip, nil, nil, logger)
+	trans, err := service.CancelTransaction(ctx, "tx-1", "ops", model.CancelReasonOther, "")
+
+	// Reversing a completed withdrawal would pay it out a second time
+	assert.ErrorIs(t, err, model.ErrNotCancellable)
+	assert.Nil(t, trans)
+	mockUserRepo.AssertNotCalled(t, "GetUserForUpdate")
+}
+
+func TestCancellationService_CancelTransaction_InsufficientBalance(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	mockTransRepo.On("GetTransactionForUpdate", ctx, "tx-1", mock.Anything).Return(&model.Transaction{
+		ID:            1,
+		TransactionID: "tx-1",
+		UserID:        1,
+		State:         model.StateWin,
+		Amount:        decimal.NewFromInt(50),
+		Status:        model.StatusProcessed,
+	}, nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:      1,
+		Balance: decimal.NewFromInt(20),
+	}, nil)
+
+	service := NewCancellationService(mockUserRepo, mockTransRepo, nil, nil, mockDBManager, model.ShortfallSkip, nil, nil, logger)
+	_, err := service.CancelTransaction(ctx, "tx-1", "ops", model.CancelReasonOther, "")
+
+	assert.ErrorIs(t, err, model.ErrInsufficientBalance)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+)
+
+// DebtPolicy holds the source types in debt mode: their cancellations may leave the user in debt under the
+// debt shortfall policy, and their wins repay the debt before reaching the balance
+type DebtPolicy struct {
+	mu          sync.RWMutex
+	SourceTypes map[model.SourceType]bool
+}
+
+// NewDebtPolicy builds the debt mode from configuration, rejecting unknown source types
+func NewDebtPolicy(cfg config.DebtConfig) (*DebtPolicy, error) {
+	policy := &DebtPolicy{SourceTypes: make(map[model.SourceType]bool, len(cfg.SourceTypes))}
+	for _, name := range cfg.SourceTypes {
+		sourceType, err := model.ParseSourceType(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, name)
+		}
+		policy.SourceTypes[sourceType] = true
+	}
+	return policy, nil
+}
+
+// Update replaces the source types with reloaded configuration, the current ones stay if the new ones are invalid
+func (p *DebtPolicy) Update(cfg config.DebtConfig) error {
+	next, err := NewDebtPolicy(cfg)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.SourceTypes = next.SourceTypes
+	return nil
+}
+
+// Enabled reports whether a source type is in debt mode
+func (p *DebtPolicy) Enabled(sourceType model.SourceType) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.SourceTypes[sourceType]
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebtPolicy_Enabled(t *testing.T) {
+	policy, err := NewDebtPolicy(config.DebtConfig{SourceTypes: []string{"game"}})
+	require.NoError(t, err)
+
+	assert.True(t, policy.Enabled(model.SourceGame))
+	assert.False(t, policy.Enabled(model.SourcePayment))
+
+	require.NoError(t, policy.Update(config.DebtConfig{SourceTypes: []string{"payment"}}))
+	assert.False(t, policy.Enabled(model.SourceGame))
+	assert.True(t, policy.Enabled(model.SourcePayment))
+
+	assert.Error(t, policy.Update(config.DebtConfig{SourceTypes: []string{"casino"}}))
+	assert.True(t, policy.Enabled(model.SourcePayment))
+
+	var disabled *DebtPolicy
+	assert.False(t, disabled.Enabled(model.SourceGame))
+}
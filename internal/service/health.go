@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// Readiness statuses
+const (
+	ReadinessOK          = "ok"
+	ReadinessFailing     = "failing"
+	ReadinessUnavailable = "unavailable"
+)
+
+type HealthServiceImpl struct {
+	healthRepo    repository.HealthRepository
+	workers       WorkerHealth
+	timeout       time.Duration
+	maxPoolUsage  float64
+	schemaVersion int
+	draining      atomic.Bool
+	logger        zerolog.Logger
+}
+
+// NewHealthService creates the readiness checks. Database checks give up after timeout, the pool is saturated once
+// maxPoolUsage (0-1) of its connections are in use and the schema must be at least schemaVersion. workers is optional.
+func NewHealthService(healthRepo repository.HealthRepository, workers WorkerHealth, timeout time.Duration, maxPoolUsage float64,
+	schemaVersion int, logger zerolog.Logger) HealthService {
+	return &HealthServiceImpl{
+		healthRepo:    healthRepo,
+		workers:       workers,
+		timeout:       timeout,
+		maxPoolUsage:  maxPoolUsage,
+		schemaVersion: schemaVersion,
+		logger:        logger,
+	}
+}
+
+func (s *HealthServiceImpl) Readiness(ctx context.Context) *model.ReadinessResponse {
+	resp := &model.ReadinessResponse{Status: ReadinessOK, Checks: make(map[string]model.ReadinessCheck)}
+	check := func(name string, err error, message string) {
+		if err != nil {
+			resp.Status = ReadinessUnavailable
+			resp.Checks[name] = model.ReadinessCheck{Status: ReadinessFailing, Message: err.Error()}
+			return
+		}
+		resp.Checks[name] = model.ReadinessCheck{Status: ReadinessOK, Message: message}
+	}
+
+	if s.draining.Load() {
+		check("shutdown", fmt.Errorf("shutting down"), "")
+		return resp
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	check("database", s.healthRepo.Ping(ctx), "")
+
+	stats := s.healthRepo.PoolStats()
+	usage := fmt.Sprintf("%d of %d connections in use", stats.Acquired, stats.Max)
+	if stats.Max > 0 && float64(stats.Acquired)/float64(stats.Max) >= s.maxPoolUsage {
+		check("pool", fmt.Errorf("saturated: %s", usage), "")
+	} else {
+		check("pool", nil, usage)
+	}
+
+	version, err := s.healthRepo.SchemaVersion(ctx)
+	if err == nil && version < s.schemaVersion {
+		err = fmt.Errorf("schema version %d, migrations up to %d required", version, s.schemaVersion)
+	}
+	check("migrations", err, fmt.Sprintf("schema version %d", version))
+
+	if s.workers != nil {
+		check("workers", s.workers.Healthy(), "")
+	}
+
+	if resp.Status != ReadinessOK {
+		s.logger.Warn().Interface("checks", resp.Checks).Msg("Readiness check failed")
+	}
+	return resp
+}
+
+func (s *HealthServiceImpl) Drain() {
+	if !s.draining.Swap(true) {
+		s.logger.Info().Msg("Draining, readiness checks fail from now on")
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+	servicemocks "transaction-processor/mocks/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealthService_Readiness_OK(t *testing.T) {
+	mockRepo := mocks.NewHealthRepository(t)
+	mockWorkers := servicemocks.NewWorkerHealth(t)
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockRepo.On("PoolStats").Return(model.PoolStats{Acquired: 3, Max: 10})
+	mockRepo.On("SchemaVersion", mock.Anything).Return(15, nil)
+	mockWorkers.On("Healthy").Return(nil)
+
+	service := NewHealthService(mockRepo, mockWorkers, time.Second, 0.9, 15, zerolog.Nop())
+	resp := service.Readiness(context.Background())
+
+	assert.Equal(t, ReadinessOK, resp.Status)
+	assert.Equal(t, model.ReadinessCheck{Status: ReadinessOK, Message: "3 of 10 connections in use"}, resp.Checks["pool"])
+	assert.Equal(t, ReadinessOK, resp.Checks["workers"].Status)
+}
+
+func TestHealthService_Readiness_Failing(t *testing.T) {
+	mockRepo := mocks.NewHealthRepository(t)
+
+	mockRepo.On("Ping", mock.Anything).Return(errors.New("connection refused"))
+	mockRepo.On("PoolStats").Return(model.PoolStats{Acquired: 9, Max: 10})
+	mockRepo.On("SchemaVersion", mock.Anything).Return(14, nil)
+
+	service := NewHealthService(mockRepo, nil, time.Second, 0.9, 15, zerolog.Nop())
+	resp := service.Readiness(context.Background())
+
+	assert.Equal(t, ReadinessUnavailable, resp.Status)
+	assert.Equal(t, model.ReadinessCheck{Status: ReadinessFailing, Message: "connection refused"}, resp.Checks["database"])
+	assert.Equal(t, ReadinessFailing, resp.Checks["pool"].Status)
+	assert.Equal(t, model.ReadinessCheck{Status: ReadinessFailing, Message: "schema version 14, migrations up to 15 required"}, resp.Checks["migrations"])
+	assert.NotContains(t, resp.Checks, "workers")
+}
+
+func TestHealthService_Readiness_Draining(t *testing.T) {
+	mockRepo := mocks.NewHealthRepository(t)
+
+	service := NewHealthService(mockRepo, nil, time.Second, 0.9, 15, zerolog.Nop())
+	service.Drain()
+	resp := service.Readiness(context.Background())
+
+	assert.Equal(t, ReadinessUnavailable, resp.Status)
+	assert.Equal(t, map[string]model.ReadinessCheck{"shutdown": {Status: ReadinessFailing, Message: "shutting down"}}, resp.Checks)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+type IdempotencyServiceImpl struct {
+	idempotencyRepo repository.IdempotencyRepository
+	ttl             time.Duration
+	logger          zerolog.Logger
+}
+
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, ttl time.Duration, logger zerolog.Logger) IdempotencyService {
+	return &IdempotencyServiceImpl{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+		logger:          logger,
+	}
+}
+
+func (s *IdempotencyServiceImpl) Reserve(ctx context.Context, scope, key, fingerprint string) (*model.IdempotencyRecord, error) {
+	reserved, err := s.idempotencyRepo.ReserveIdempotencyKey(ctx, scope, key, fingerprint, time.Now().UTC().Add(s.ttl))
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	rec, err := s.idempotencyRepo.GetIdempotencyRecord(ctx, scope, key)
+	if err != nil {
+		// Expired and deleted in between, the client can retry
+		if errors.Is(err, model.ErrIdempotencyNotFound) {
+			return nil, model.ErrIdempotencyInProgress
+		}
+		return nil, fmt.Errorf("get idempotency record: %w", err)
+	}
+
+	if rec.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("%w: key %s", model.ErrIdempotencyMismatch, key)
+	}
+	if rec.StatusCode == 0 {
+		return nil, model.ErrIdempotencyInProgress
+	}
+	return rec, nil
+}
+
+func (s *IdempotencyServiceImpl) Complete(ctx context.Context, scope, key, fingerprint string, statusCode int, response []byte) error {
+	err := s.idempotencyRepo.SaveIdempotencyRecord(ctx, &model.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		StatusCode:  statusCode,
+		Response:    response,
+		ExpiresAt:   time.Now().UTC().Add(s.ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("save idempotency record: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyServiceImpl) Release(ctx context.Context, scope, key string) error {
+	if err := s.idempotencyRepo.DeleteIdempotencyKey(ctx, scope, key); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyServiceImpl) DeleteExpired(ctx context.Context) error {
+	deleted, err := s.idempotencyRepo.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+
+	s.logger.Debug().Int64("deleted", deleted).Msg("expired idempotency keys deleted")
+	return nil
+}
+
+// Fingerprint hashes the parts of a request that must match when its idempotency key is reused
+func Fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyReserve_NewKey(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := mocks.NewIdempotencyRepository(t)
+	mockRepo.On("ReserveIdempotencyKey", ctx, "POST /admin/adjustments", "key-1", "fp", mock.Anything).Return(true, nil)
+
+	svc := NewIdempotencyService(mockRepo, time.Hour, zerolog.Nop())
+
+	rec, err := svc.Reserve(ctx, "POST /admin/adjustments", "key-1", "fp")
+
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+	mockRepo.AssertNotCalled(t, "GetIdempotencyRecord")
+}
+
+func TestIdempotencyReserve_ReturnsStoredResponse(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := mocks.NewIdempotencyRepository(t)
+	mockRepo.On("ReserveIdempotencyKey", ctx, "POST /admin/adjustments", "key-1", "fp", mock.Anything).Return(false, nil)
+	mockRepo.On("GetIdempotencyRecord", ctx, "POST /admin/adjustments", "key-1").Return(&model.IdempotencyRecord{
+		Fingerprint: "fp",
+		StatusCode:  201,
+		Response:    []byte(`{"id":1}`),
+	}, nil)
+
+	svc := NewIdempotencyService(mockRepo, time.Hour, zerolog.Nop())
+
+	rec, err := svc.Reserve(ctx, "POST /admin/adjustments", "key-1", "fp")
+
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, 201, rec.StatusCode)
+	assert.JSONEq(t, `{"id":1}`, string(rec.Response))
+}
+
+func TestIdempotencyReserve_Mismatch(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := mocks.NewIdempotencyRepository(t)
+	mockRepo.On("ReserveIdempotencyKey", ctx, "POST /admin/adjustments", "key-1", "other", mock.Anything).Return(false, nil)
+	mockRepo.On("GetIdempotencyRecord", ctx, "POST /admin/adjustments", "key-1").Return(&model.IdempotencyRecord{
+		Fingerprint: "fp",
+		StatusCode:  201,
+	}, nil)
+
+	svc := NewIdempotencyService(mockRepo, time.Hour, zerolog.Nop())
+
+	rec, err := svc.Reserve(ctx, "POST /admin/adjustments", "key-1", "other")
+
+	require.Error(t, err)
+	assert.Nil(t, rec)
+	assert.ErrorIs(t, err, model.ErrIdempotencyMismatch)
+}
+
+func TestIdempotencyReserve_InProgress(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := mocks.NewIdempotencyRepository(t)
+	mockRepo.On("ReserveIdempotencyKey", ctx, "POST /admin/adjustments", "key-1", "fp", mock.Anything).Return(false, nil)
+	mockRepo.On("GetIdempotencyRecord", ctx, "POST /admin/adjustments", "key-1").Return(&model.IdempotencyRecord{
+		Fingerprint: "fp",
+	}, nil)
+
+	svc := NewIdempotencyService(mockRepo, time.Hour, zerolog.Nop())
+
+	rec, err := svc.Reserve(ctx, "POST /admin/adjustments", "key-1", "fp")
+
+	require.Error(t, err)
+	assert.Nil(t, rec)
+	assert.ErrorIs(t, err, model.ErrIdempotencyInProgress)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-processor/internal/metrics"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type IntegrityServiceImpl struct {
+	userRepo     repository.UserRepository
+	pauseOnDrift bool
+	logger       zerolog.Logger
+}
+
+func NewIntegrityService(userRepo repository.UserRepository, pauseOnDrift bool, logger zerolog.Logger) IntegrityService {
+	return &IntegrityServiceImpl{
+		userRepo:     userRepo,
+		pauseOnDrift: pauseOnDrift,
+		logger:       logger,
+	}
+}
+
+func (s *IntegrityServiceImpl) VerifyBalances(ctx context.Context) ([]*model.BalanceDrift, error) {
+	drifts, err := s.userRepo.GetBalanceDrifts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get balance drifts: %w", err)
+	}
+
+	for _, drift := range drifts {
+		s.logger.Warn().
+			Int64("user_id", drift.UserID).
+			Str("balance", drift.Balance.StringFixed(2)).
+			Str("expected", drift.Expected.StringFixed(2)).
+			Str("drift", drift.Drift.StringFixed(2)).
+			Msg("balance drift detected")
+	}
+
+	return drifts, nil
+}
+
+func (s *IntegrityServiceImpl) RunCheck(ctx context.Context) (*model.IntegrityReport, error) {
+	drifts, err := s.VerifyBalances(ctx)
+	if err != nil {
+		metrics.IntegrityChecksTotal.WithLabelValues("error").Inc()
+		return nil, err
+	}
+
+	report := &model.IntegrityReport{
+		CheckedAt:   time.Now().UTC(),
+		Drifts:      drifts,
+		PausedUsers: []int64{},
+	}
+	if report.Drifts == nil {
+		report.Drifts = []*model.BalanceDrift{}
+	}
+
+	total := decimal.Zero
+	userIDs := make([]int64, 0, len(drifts))
+	for _, drift := range drifts {
+		total = total.Add(drift.Drift.Abs())
+		userIDs = append(userIDs, drift.UserID)
+	}
+	report.TotalDrift = total.StringFixed(2)
+
+	if s.pauseOnDrift && len(userIDs) > 0 {
+		paused, err := s.userRepo.SetMutationsPaused(ctx, userIDs, true)
+		if err != nil {
+			metrics.IntegrityChecksTotal.WithLabelValues("error").Inc()
+			return nil, fmt.Errorf("pause drifting users: %w", err)
+		}
+		if paused != nil {
+			report.PausedUsers = paused
+		}
+		for _, userID := range paused {
+			s.logger.Warn().Int64("user_id", userID).Msg("balance mutations paused due to drift")
+		}
+		metrics.IntegrityPausedUsersTotal.Add(float64(len(paused)))
+	}
+
+	result := "ok"
+	if len(drifts) > 0 {
+		result = "drift"
+	}
+	metrics.IntegrityChecksTotal.WithLabelValues(result).Inc()
+	metrics.IntegrityDriftUsers.Set(float64(len(drifts)))
+	metrics.IntegrityDriftAmount.Set(total.InexactFloat64())
+	metrics.IntegrityLastCheck.Set(float64(report.CheckedAt.Unix()))
+
+	s.logger.Info().
+		Int("drift_users", len(drifts)).
+		Str("total_drift", report.TotalDrift).
+		Int("paused_users", len(report.PausedUsers)).
+		Msg("balance integrity check completed")
+
+	return report, nil
+}
+
+func (s *IntegrityServiceImpl) ResumeUser(ctx context.Context, userID int64, operator string) error {
+	resumed, err := s.userRepo.SetMutationsPaused(ctx, []int64{userID}, false)
+	if err != nil {
+		return fmt.Errorf("resume user: %w", err)
+	}
+
+	// Nothing changed, either the user is not paused or does not exist
+	if len(resumed) == 0 {
+		if _, err := s.userRepo.GetBalance(ctx, userID); err != nil {
+			if errors.Is(err, model.ErrUserNotFound) {
+				return err
+			}
+			return fmt.Errorf("get balance: %w", err)
+		}
+		return nil
+	}
+
+	s.logger.Info().Int64("user_id", userID).Str("operator", operator).Msg("balance mutations resumed")
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntegrityService_RunCheck_PausesDriftingUsers(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockUserRepo.On("GetBalanceDrifts", ctx).Return([]*model.BalanceDrift{
+		{UserID: 1, Balance: decimal.NewFromInt(110), Expected: decimal.NewFromInt(100), Drift: decimal.NewFromInt(10)},
+		{UserID: 2, Balance: decimal.NewFromInt(95), Expected: decimal.NewFromInt(100), Drift: decimal.NewFromInt(-5)},
+	}, nil)
+	// User 2 was already paused by a previous check
+	mockUserRepo.On("SetMutationsPaused", ctx, []int64{1, 2}, true).Return([]int64{1}, nil)
+
+	service := NewIntegrityService(mockUserRepo, true, zerolog.Nop())
+	report, err := service.RunCheck(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Drifts, 2)
+	assert.Equal(t, "15.00", report.TotalDrift)
+	assert.Equal(t, []int64{1}, report.PausedUsers)
+}
+
+func TestIntegrityService_RunCheck_ReportOnly(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockUserRepo.On("GetBalanceDrifts", ctx).Return([]*model.BalanceDrift{
+		{UserID: 1, Balance: decimal.NewFromInt(110), Expected: decimal.NewFromInt(100), Drift: decimal.NewFromInt(10)},
+	}, nil)
+
+	service := NewIntegrityService(mockUserRepo, false, zerolog.Nop())
+	report, err := service.RunCheck(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Drifts, 1)
+	assert.Empty(t, report.PausedUsers)
+	mockUserRepo.AssertNotCalled(t, "SetMutationsPaused")
+}
+
+func TestIntegrityService_RunCheck_NoDrift(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockUserRepo.On("GetBalanceDrifts", ctx).Return(nil, nil)
+
+	service := NewIntegrityService(mockUserRepo, true, zerolog.Nop())
+	report, err := service.RunCheck(ctx)
+
+	assert.NoError(t, err)
+	assert.Empty(t, report.Drifts)
+	assert.Equal(t, "0.00", report.TotalDrift)
+	mockUserRepo.AssertNotCalled(t, "SetMutationsPaused")
+}
+
+func TestIntegrityService_ResumeUser_NotFound(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockUserRepo.On("SetMutationsPaused", ctx, []int64{7}, false).Return(nil, nil)
+	mockUserRepo.On("GetBalance", ctx, int64(7)).Return(decimal.Zero, model.ErrUserNotFound)
+
+	service := NewIntegrityService(mockUserRepo, false, zerolog.Nop())
+	err := service.ResumeUser(ctx, 7, "ops")
+
+	assert.ErrorIs(t, err, model.ErrUserNotFound)
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"time"
 	"transaction-processor/internal/model"
+
+	"github.com/shopspring/decimal"
 )
 
 // TransactionService defines the business logic for processing transactions
@@ -10,10 +13,180 @@ type TransactionService interface {
 	ProcessTransaction(ctx context.Context, req *model.TransactionRequest, sourceType model.SourceType, userID int64) (*model.TransactionResponse, error)
 	GetBalance(ctx context.Context, userID int64) (*model.BalanceResponse, error)
 	GetTransactionsByUser(ctx context.Context, userID int64, limit, offset int) ([]*model.Transaction, error)
+	// SetUserProvider binds a user to the provider it belongs to, the only one whose tokens may reach the user
+	SetUserProvider(ctx context.Context, userID int64, providerID, operator string) error
+	GetTransaction(ctx context.Context, transactionID string) (*model.Transaction, error)
+	// GetTransactionEvents returns the audit trail of a transaction's status changes, oldest first
+	GetTransactionEvents(ctx context.Context, transactionID string) ([]*model.TransactionEvent, error)
 }
 
 // CancellationService defines the business logic for cancelling transactions
 type CancellationService interface {
 	// ProcessOddRecordCancellation cancels odd-numbered processed transactions and adjusts user balances
-	ProcessOddRecordCancellation(ctx context.Context) error
+	ProcessOddRecordCancellation(ctx context.Context) (*model.CancellationResult, error)
+	// CancelTransaction cancels a single processed transaction on behalf of an operator and reverses its balance effect
+	CancelTransaction(ctx context.Context, transactionID, operator string, reason model.CancelReason, note string) (*model.Transaction, error)
+	// ListCancellationShortfalls returns the transactions whose cancellation failed or left a shortfall, newest first
+	ListCancellationShortfalls(ctx context.Context, limit, offset int) ([]*model.Transaction, error)
+	// GetWorkerState returns whether automatic cancellation runs are paused
+	GetWorkerState(ctx context.Context) (*model.WorkerState, error)
+	// SetWorkerPaused pauses or resumes automatic cancellation runs on all instances
+	SetWorkerPaused(ctx context.Context, paused bool, operator string) error
+	// SetWorkerInterval changes the interval between automatic cancellation runs on all instances,
+	// zero restores the configured schedule
+	SetWorkerInterval(ctx context.Context, interval time.Duration, operator string) error
+}
+
+// Leadership reports whether this instance is the elected leader, the only one running scheduled background jobs
+type Leadership interface {
+	IsLeader() bool
+}
+
+// WorkerHealth reports whether the background jobs are being scheduled
+type WorkerHealth interface {
+	Healthy() error
+}
+
+// HealthService checks whether this instance can serve traffic
+type HealthService interface {
+	// Readiness runs the dependency checks, it fails without running them once Drain is called
+	Readiness(ctx context.Context) *model.ReadinessResponse
+	// Drain makes readiness fail ahead of shutdown so load balancers stop routing traffic here
+	Drain()
+}
+
+// CancellationWorkerControl controls the automatic cancellation runs of the running server
+type CancellationWorkerControl interface {
+	// Status returns the pause state, interval and last run of any instance
+	Status(ctx context.Context) (*model.WorkerStatus, error)
+	// Pause stops automatic runs until resumed, a run in progress completes
+	Pause(ctx context.Context, operator string) error
+	// Resume restarts automatic runs
+	Resume(ctx context.Context, operator string) error
+	// Trigger runs a cancellation now, returning ErrWorkerPaused or ErrWorkerBusy if it cannot
+	Trigger(ctx context.Context) (*model.WorkerStatus, error)
+	// SetInterval changes the interval between automatic runs on all instances, zero restores the configured schedule
+	SetInterval(ctx context.Context, interval time.Duration, operator string) error
+}
+
+// LimitService defines the business logic for responsible-gaming loss limits
+type LimitService interface {
+	// GetLossLimits returns the user's loss limits together with the net loss of the current windows
+	GetLossLimits(ctx context.Context, userID int64) (*model.LossLimitsResponse, error)
+	// SetLossLimit sets a loss limit; decreases apply immediately, increases after the cooling-off period
+	SetLossLimit(ctx context.Context, userID int64, req *model.LossLimitRequest) (*model.LossLimitResponse, error)
+}
+
+// ReviewService defines the manual review workflow for transactions held in pending_review
+type ReviewService interface {
+	// ListPendingReviews returns transactions waiting for a review decision, oldest first
+	ListPendingReviews(ctx context.Context, limit, offset int) ([]*model.Transaction, error)
+	// ApproveTransaction applies a pending transaction to the user's balance
+	ApproveTransaction(ctx context.Context, transactionID, reviewer, note string) (*model.Transaction, error)
+	// RejectTransaction declines a pending transaction without touching the balance
+	RejectTransaction(ctx context.Context, transactionID, reviewer, note string) (*model.Transaction, error)
+}
+
+// AdjustmentService defines manual balance corrections made by operators under maker-checker approval
+type AdjustmentService interface {
+	// CreateAdjustment requests a credit (positive amount) or debit (negative amount), pending approval
+	CreateAdjustment(ctx context.Context, userID int64, amount decimal.Decimal, reason, operator string) (*model.BalanceAdjustment, error)
+	// ListAdjustments returns adjustments with the given status, oldest first
+	ListAdjustments(ctx context.Context, status model.AdjustmentStatus, limit, offset int) ([]*model.BalanceAdjustment, error)
+	// ApproveAdjustment applies a pending adjustment and records it in the transaction history,
+	// the approver must differ from the operator who requested it
+	ApproveAdjustment(ctx context.Context, id int64, operator, note string) (*model.BalanceAdjustment, error)
+	// RejectAdjustment declines a pending adjustment without touching the balance
+	RejectAdjustment(ctx context.Context, id int64, operator, note string) (*model.BalanceAdjustment, error)
+}
+
+// BonusService manages promotional bonus funds, spent and released by transactions
+type BonusService interface {
+	// GrantBonus credits bonus funds, the wagering requirement defaults to the amount times the configured multiplier.
+	// The grant is added to the active bonus, whose expiry restarts.
+	GrantBonus(ctx context.Context, userID int64, amount decimal.Decimal, wagering *decimal.Decimal, note, operator string) (*model.BonusWallet, error)
+	// GetBonusWallet returns the bonus balance of a user and what is left to wager
+	GetBonusWallet(ctx context.Context, userID int64) (*model.BonusWallet, error)
+	// ListBonusEntries returns a user's grants and expirations, newest first
+	ListBonusEntries(ctx context.Context, userID int64, limit, offset int) ([]*model.BonusEntry, error)
+	// ExpireBonuses removes the bonuses whose expiry passed, users paused by the integrity check keep them
+	ExpireBonuses(ctx context.Context) (*model.BonusExpiryResult, error)
+}
+
+// PaymentService manages deposits and withdrawals: requested by users, approved by operators and completed or failed
+// by the payment provider
+type PaymentService interface {
+	// RequestDeposit records a pending deposit, the balance is credited when the provider completes it
+	RequestDeposit(ctx context.Context, userID int64, amount decimal.Decimal) (*model.Payment, error)
+	// RequestWithdrawal moves the amount from the balance to the reserved funds and records a pending withdrawal
+	RequestWithdrawal(ctx context.Context, userID int64, amount decimal.Decimal) (*model.Payment, error)
+	// ListPayments returns payments with the given status, oldest first
+	ListPayments(ctx context.Context, status model.PaymentStatus, limit, offset int) ([]*model.Payment, error)
+	// ListUserPayments returns a user's payments, newest first
+	ListUserPayments(ctx context.Context, userID int64, limit, offset int) ([]*model.Payment, error)
+	// ApprovePayment submits a pending payment to the provider, or retries the submission of a submitting one
+	ApprovePayment(ctx context.Context, id int64, operator string) (*model.Payment, error)
+	// RejectPayment fails a pending payment, releasing the reserved funds of a withdrawal
+	RejectPayment(ctx context.Context, id int64, operator, reason string) (*model.Payment, error)
+	// VerifyCallback checks that a callback body was signed by the provider
+	VerifyCallback(body []byte, signature string) error
+	// HandleCallback completes or fails an approved payment by its provider reference.
+	// A callback repeating the current outcome returns the payment unchanged.
+	HandleCallback(ctx context.Context, reference string, status model.PaymentStatus, reason string) (*model.Payment, error)
+}
+
+// PaymentProvider submits payments to a payment provider and verifies its callbacks
+type PaymentProvider interface {
+	Name() string
+	// Submit hands over an approved payment and returns the provider's reference for it. The payment's TransactionID
+	// is the idempotency key: submitting a payment again must not move the money twice and returns the same reference.
+	Submit(ctx context.Context, payment *model.Payment) (string, error)
+	VerifyCallback(body []byte, signature string) error
+}
+
+// IntegrityService checks stored balances against transaction history
+type IntegrityService interface {
+	// VerifyBalances returns every user whose balance differs from the recomputed one
+	VerifyBalances(ctx context.Context) ([]*model.BalanceDrift, error)
+	// RunCheck verifies balances, records metrics and pauses drifting users if configured
+	RunCheck(ctx context.Context) (*model.IntegrityReport, error)
+	// ResumeUser lifts a mutation pause from a user
+	ResumeUser(ctx context.Context, userID int64, operator string) error
+}
+
+// BalanceStreamService pushes balance changes from all API replicas to stream subscribers
+type BalanceStreamService interface {
+	// Run listens for balance changes until the context is cancelled, reconnecting on failures,
+	// then closes every subscription
+	Run(ctx context.Context)
+	// Subscribe returns the balance changes of a user and a function to stop receiving them,
+	// the channel is closed when the service stops
+	Subscribe(userID int64) (<-chan *model.BalanceEvent, func())
+	// Current returns the user's balance and version
+	Current(ctx context.Context, userID int64) (*model.BalanceEvent, error)
+}
+
+// IdempotencyService stores responses of mutating requests sent with an Idempotency-Key
+type IdempotencyService interface {
+	// Reserve claims a key for a new request, returning the stored record if the key was already used.
+	// Returns ErrIdempotencyMismatch if the key was used with a different fingerprint
+	// and ErrIdempotencyInProgress if the first request has not completed yet.
+	Reserve(ctx context.Context, scope, key, fingerprint string) (*model.IdempotencyRecord, error)
+	// Complete stores the response of a reserved key
+	Complete(ctx context.Context, scope, key, fingerprint string, statusCode int, response []byte) error
+	// Release frees a reserved key so the request can be retried, used when it failed without side effects
+	Release(ctx context.Context, scope, key string) error
+	// DeleteExpired removes records older than the configured TTL
+	DeleteExpired(ctx context.Context) error
+}
+
+// PartitionService creates the monthly transaction partitions ahead of time and archives old ones
+type PartitionService interface {
+	// ListPartitions returns live and archived partitions, oldest first
+	ListPartitions(ctx context.Context) ([]*model.TransactionPartition, error)
+	// EnsurePartitions creates the partitions from the latest existing one up to the configured months ahead
+	EnsurePartitions(ctx context.Context) error
+	// ArchivePartitions exports partitions older than the retention to archive files and drops them,
+	// returning the archived partitions. Partitions holding transactions pending review are skipped.
+	ArchivePartitions(ctx context.Context) ([]*model.TransactionPartition, error)
 }
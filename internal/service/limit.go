@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type LimitServiceImpl struct {
+	userRepo   repository.UserRepository
+	limitRepo  repository.LimitRepository
+	dbManager  repository.DBManager
+	coolingOff time.Duration
+	logger     zerolog.Logger
+}
+
+func NewLimitService(
+	userRepo repository.UserRepository,
+	limitRepo repository.LimitRepository,
+	dbManager repository.DBManager,
+	coolingOff time.Duration,
+	logger zerolog.Logger,
+) LimitService {
+	return &LimitServiceImpl{
+		userRepo:   userRepo,
+		limitRepo:  limitRepo,
+		dbManager:  dbManager,
+		coolingOff: coolingOff,
+		logger:     logger,
+	}
+}
+
+func (s *LimitServiceImpl) GetLossLimits(ctx context.Context, userID int64) (*model.LossLimitsResponse, error) {
+	// Also validates that the user exists
+	user, err := s.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := auth.CheckUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	limits, err := s.limitRepo.GetLossLimits(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get loss limits: %w", err)
+	}
+
+	counters, err := s.limitRepo.GetLossCounters(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get loss counters: %w", err)
+	}
+
+	now := time.Now().UTC()
+	resp := &model.LossLimitsResponse{UserID: userID, Limits: []model.LossLimitResponse{}}
+	for _, limit := range limits {
+		resp.Limits = append(resp.Limits, toLossLimitResponse(limit, currentNetLoss(counters, limit.Period, now), now))
+	}
+	return resp, nil
+}
+
+func (s *LimitServiceImpl) SetLossLimit(ctx context.Context, userID int64, req *model.LossLimitRequest) (*model.LossLimitResponse, error) {
+	period, err := model.ParseLimitPeriod(req.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidAmount, err.Error())
+	}
+
+	if amount.LessThan(decimal.Zero) {
+		return nil, fmt.Errorf("%w: limit must not be negative", model.ErrInvalidAmount)
+	}
+
+	var resp model.LossLimitResponse
+	err = s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		// Lock the user so the change is serialized with transactions checked against the limit
+		user, err := s.userRepo.GetUserForUpdate(ctx, userID, tx)
+		if err != nil {
+			return fmt.Errorf("get user for update: %w", err)
+		}
+		if err := auth.CheckUser(ctx, user); err != nil {
+			return err
+		}
+
+		limits, err := s.limitRepo.GetLossLimits(ctx, userID, tx)
+		if err != nil {
+			return fmt.Errorf("get loss limits: %w", err)
+		}
+
+		now := time.Now().UTC()
+		limit := &model.LossLimit{UserID: userID, Period: period, Amount: amount}
+		if existing := findLossLimit(limits, period); existing != nil {
+			current := existing.EffectiveAmount(now)
+			if amount.GreaterThan(current) {
+				// Increases only take effect once the cooling-off period has passed
+				effectiveAt := now.Add(s.coolingOff)
+				limit.Amount = current
+				limit.PendingAmount = &amount
+				limit.PendingEffectiveAt = &effectiveAt
+			}
+		}
+
+		if err := s.limitRepo.UpsertLossLimit(ctx, limit, tx); err != nil {
+			return fmt.Errorf("upsert loss limit: %w", err)
+		}
+
+		counters, err := s.limitRepo.GetLossCounters(ctx, userID, tx)
+		if err != nil {
+			return fmt.Errorf("get loss counters: %w", err)
+		}
+
+		s.logger.Info().
+			Int64("user_id", userID).
+			Str("period", period.String()).
+			Str("amount", limit.Amount.StringFixed(2)).
+			Bool("pending_increase", limit.PendingAmount != nil).
+			Msg("loss limit updated")
+
+		resp = toLossLimitResponse(limit, currentNetLoss(counters, period, now), now)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// checkLossLimits returns ErrLossLimitExceeded if losing amount would take the user's net loss
+// above any of the limits in force. Must be called with the user row locked.
+func checkLossLimits(ctx context.Context, limitRepo repository.LimitRepository, userID int64, amount decimal.Decimal, now time.Time, tx pgx.Tx) error {
+	limits, err := limitRepo.GetLossLimits(ctx, userID, tx)
+	if err != nil {
+		return fmt.Errorf("get loss limits: %w", err)
+	}
+
+	// Users without limits don't need counters
+	if len(limits) == 0 {
+		return nil
+	}
+
+	counters, err := limitRepo.GetLossCounters(ctx, userID, tx)
+	if err != nil {
+		return fmt.Errorf("get loss counters: %w", err)
+	}
+
+	for _, limit := range limits {
+		effective := limit.EffectiveAmount(now)
+		netLoss := currentNetLoss(counters, limit.Period, now)
+		if netLoss.Add(amount).GreaterThan(effective) {
+			return fmt.Errorf("%w: %s limit %s, current net loss %s",
+				model.ErrLossLimitExceeded, limit.Period, effective.StringFixed(2), netLoss.StringFixed(2))
+		}
+	}
+	return nil
+}
+
+// netLossDelta returns how a transaction changes the user's net loss
+func netLossDelta(state model.State, amount decimal.Decimal) decimal.Decimal {
+	if state == model.StateLost {
+		return amount
+	}
+	return amount.Neg()
+}
+
+// currentNetLoss returns the net loss of the window containing now, counters of older windows count as zero
+func currentNetLoss(counters []*model.LossCounter, period model.LimitPeriod, now time.Time) decimal.Decimal {
+	for _, counter := range counters {
+		if counter.Period == period && counter.WindowStart.Equal(period.WindowStart(now)) {
+			return counter.NetLoss
+		}
+	}
+	return decimal.Zero
+}
+
+func findLossLimit(limits []*model.LossLimit, period model.LimitPeriod) *model.LossLimit {
+	for _, limit := range limits {
+		if limit.Period == period {
+			return limit
+		}
+	}
+	return nil
+}
+
+func toLossLimitResponse(limit *model.LossLimit, netLoss decimal.Decimal, now time.Time) model.LossLimitResponse {
+	effective := limit.EffectiveAmount(now)
+	remaining := decimal.Max(effective.Sub(netLoss), decimal.Zero)
+
+	resp := model.LossLimitResponse{
+		Period:    limit.Period.String(),
+		Amount:    effective.StringFixed(2),
+		NetLoss:   netLoss.StringFixed(2),
+		Remaining: remaining.StringFixed(2),
+	}
+	if limit.PendingAmount != nil && limit.PendingEffectiveAt != nil && now.Before(*limit.PendingEffectiveAt) {
+		resp.PendingAmount = limit.PendingAmount.StringFixed(2)
+		resp.PendingEffectiveAt = limit.PendingEffectiveAt
+	}
+	return resp
+}
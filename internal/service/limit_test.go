@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitService_SetLossLimit_DecreaseAppliesImmediately(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{ID: 1}, nil)
+	mockLimitRepo.On("GetLossLimits", ctx, int64(1), mock.Anything).Return([]*model.LossLimit{
+		{UserID: 1, Period: model.PeriodDaily, Amount: decimal.NewFromInt(100)},
+	}, nil)
+	mockLimitRepo.On("UpsertLossLimit", ctx, mock.MatchedBy(func(limit *model.LossLimit) bool {
+		return limit.Period == model.PeriodDaily &&
+			limit.Amount.Equal(decimal.NewFromInt(50)) &&
+			limit.PendingAmount == nil
+	}), mock.Anything).Return(nil)
+	mockLimitRepo.On("GetLossCounters", ctx, int64(1), mock.Anything).Return([]*model.LossCounter{}, nil)
+
+	service := NewLimitService(mockUserRepo, mockLimitRepo, mockDBManager, 24*time.Hour, logger)
+
+	resp, err := service.SetLossLimit(ctx, 1, &model.LossLimitRequest{Period: "daily", Amount: "50"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "50.00", resp.Amount)
+	assert.Equal(t, "50.00", resp.Remaining)
+	assert.Empty(t, resp.PendingAmount)
+}
+
+func TestLimitService_SetLossLimit_IncreaseWaitsForCoolingOff(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	now := time.Now().UTC()
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{ID: 1}, nil)
+	mockLimitRepo.On("GetLossLimits", ctx, int64(1), mock.Anything).Return([]*model.LossLimit{
+		{UserID: 1, Period: model.PeriodWeekly, Amount: decimal.NewFromInt(100)},
+	}, nil)
+	mockLimitRepo.On("UpsertLossLimit", ctx, mock.MatchedBy(func(limit *model.LossLimit) bool {
+		return limit.Amount.Equal(decimal.NewFromInt(100)) &&
+			limit.PendingAmount != nil && limit.PendingAmount.Equal(decimal.NewFromInt(500)) &&
+			limit.PendingEffectiveAt != nil && limit.PendingEffectiveAt.After(now.Add(23*time.Hour))
+	}), mock.Anything).Return(nil)
+	mockLimitRepo.On("GetLossCounters", ctx, int64(1), mock.Anything).Return([]*model.LossCounter{
+		{UserID: 1, Period: model.PeriodWeekly, WindowStart: model.PeriodWeekly.WindowStart(now), NetLoss: decimal.NewFromInt(30)},
+	}, nil)
+
+	service := NewLimitService(mockUserRepo, mockLimitRepo, mockDBManager, 24*time.Hour, logger)
+
+	resp, err := service.SetLossLimit(ctx, 1, &model.LossLimitRequest{Period: "weekly", Amount: "500"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "100.00", resp.Amount)
+	assert.Equal(t, "500.00", resp.PendingAmount)
+	assert.Equal(t, "30.00", resp.NetLoss)
+	assert.Equal(t, "70.00", resp.Remaining)
+}
+
+func TestLimitService_SetLossLimit_InvalidPeriod(t *testing.T) {
+	ctx := context.Background()
+
+	service := NewLimitService(mocks.NewUserRepository(t), mocks.NewLimitRepository(t), mocks.NewDBManager(t), time.Hour, zerolog.Nop())
+
+	resp, err := service.SetLossLimit(ctx, 1, &model.LossLimitRequest{Period: "yearly", Amount: "10"})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrInvalidLimitPeriod)
+}
+
+func TestLimitPeriod_WindowStart(t *testing.T) {
+	// Thursday
+	at := time.Date(2024, time.May, 16, 15, 4, 5, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC), model.PeriodDaily.WindowStart(at))
+	assert.Equal(t, time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC), model.PeriodWeekly.WindowStart(at))
+	assert.Equal(t, time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), model.PeriodMonthly.WindowStart(at))
+}
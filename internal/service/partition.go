@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-processor/internal/archive"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+// errPartitionPendingReview skips archiving a partition until its reviews are decided
+var errPartitionPendingReview = errors.New("partition has transactions pending review")
+
+type PartitionServiceImpl struct {
+	partitionRepo   repository.PartitionRepository
+	dbManager       repository.DBManager
+	premakeMonths   int
+	retentionMonths int
+	archiveDir      string
+	logger          zerolog.Logger
+}
+
+func NewPartitionService(
+	partitionRepo repository.PartitionRepository,
+	dbManager repository.DBManager,
+	premakeMonths int,
+	retentionMonths int,
+	archiveDir string,
+	logger zerolog.Logger,
+) PartitionService {
+	return &PartitionServiceImpl{
+		partitionRepo:   partitionRepo,
+		dbManager:       dbManager,
+		premakeMonths:   premakeMonths,
+		retentionMonths: retentionMonths,
+		archiveDir:      archiveDir,
+		logger:          logger,
+	}
+}
+
+func (s *PartitionServiceImpl) ListPartitions(ctx context.Context) ([]*model.TransactionPartition, error) {
+	partitions, err := s.partitionRepo.GetPartitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get partitions: %w", err)
+	}
+	return partitions, nil
+}
+
+func (s *PartitionServiceImpl) EnsurePartitions(ctx context.Context) error {
+	partitions, err := s.partitionRepo.GetPartitions(ctx)
+	if err != nil {
+		return fmt.Errorf("get partitions: %w", err)
+	}
+
+	// Partitions are contiguous, new ones start where the latest ends
+	var start time.Time
+	for _, p := range partitions {
+		if p.RangeEnd.After(start) {
+			start = p.RangeEnd
+		}
+	}
+
+	currentMonth := monthStart(time.Now())
+	if start.IsZero() {
+		start = currentMonth
+	}
+
+	until := currentMonth.AddDate(0, s.premakeMonths+1, 0)
+	for ; start.Before(until); start = start.AddDate(0, 1, 0) {
+		name := partitionName(start)
+		if err := s.partitionRepo.CreatePartition(ctx, name, start, start.AddDate(0, 1, 0)); err != nil {
+			return fmt.Errorf("create partition: %w", err)
+		}
+		s.logger.Info().Str("partition", name).Msg("transaction partition created")
+	}
+	return nil
+}
+
+func (s *PartitionServiceImpl) ArchivePartitions(ctx context.Context) ([]*model.TransactionPartition, error) {
+	archived := []*model.TransactionPartition{}
+	if s.retentionMonths <= 0 {
+		return archived, nil
+	}
+
+	partitions, err := s.partitionRepo.GetPartitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get partitions: %w", err)
+	}
+
+	cutoff := monthStart(time.Now()).AddDate(0, -s.retentionMonths, 0)
+	for _, p := range partitions {
+		if p.ArchivedAt != nil || p.RangeEnd.After(cutoff) {
+			continue
+		}
+
+		err := s.archivePartition(ctx, p)
+		if errors.Is(err, errPartitionPendingReview) {
+			s.logger.Warn().Str("partition", p.Name).Msg("partition not archived, it has transactions pending review")
+			continue
+		}
+		if err != nil {
+			return archived, fmt.Errorf("archive partition %s: %w", p.Name, err)
+		}
+
+		s.logger.Info().Str("partition", p.Name).Str("archive", p.ArchivePath).Int64("rows", p.RowCount).Msg("transaction partition archived")
+		archived = append(archived, p)
+	}
+	return archived, nil
+}
+
+// archivePartition exports a partition and drops it in one database transaction, the partition is locked
+// against writes during the export so the archive holds its final state
+func (s *PartitionServiceImpl) archivePartition(ctx context.Context, p *model.TransactionPartition) error {
+	path := archive.Path(s.archiveDir, p.Name)
+
+	return s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		pending, err := s.partitionRepo.HasPendingReview(ctx, p.Name, tx)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return errPartitionPendingReview
+		}
+
+		// A leftover archive of a failed run is overwritten
+		w, err := archive.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := s.partitionRepo.ExportPartition(ctx, p.Name, w.Write, tx); err != nil {
+			w.Abort()
+			return err
+		}
+		if err := w.Commit(); err != nil {
+			return err
+		}
+
+		if err := s.partitionRepo.ArchivePartition(ctx, p.Name, path, w.Count(), tx); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		p.ArchivedAt = &now
+		p.ArchivePath = path
+		p.RowCount = w.Count()
+		return nil
+	})
+}
+
+// monthStart returns the first instant of the UTC month of t
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// partitionName returns the name of the partition holding the month starting at start
+func partitionName(start time.Time) string {
+	return fmt.Sprintf("transactions_p%04d_%02d", start.Year(), int(start.Month()))
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"transaction-processor/internal/archive"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsurePartitions_CreatesMissingMonths(t *testing.T) {
+	ctx := context.Background()
+	currentMonth := monthStart(time.Now())
+
+	mockRepo := mocks.NewPartitionRepository(t)
+	mockRepo.On("GetPartitions", ctx).Return([]*model.TransactionPartition{
+		{Name: "transactions_legacy", RangeEnd: currentMonth.AddDate(0, 1, 0)},
+	}, nil)
+	for i := 1; i <= 2; i++ {
+		start := currentMonth.AddDate(0, i, 0)
+		mockRepo.On("CreatePartition", ctx, partitionName(start), start, start.AddDate(0, 1, 0)).Return(nil).Once()
+	}
+
+	svc := NewPartitionService(mockRepo, mocks.NewDBManager(t), 2, 0, t.TempDir(), zerolog.Nop())
+
+	require.NoError(t, svc.EnsurePartitions(ctx))
+}
+
+func TestEnsurePartitions_UpToDate(t *testing.T) {
+	ctx := context.Background()
+	currentMonth := monthStart(time.Now())
+
+	mockRepo := mocks.NewPartitionRepository(t)
+	mockRepo.On("GetPartitions", ctx).Return([]*model.TransactionPartition{
+		{Name: partitionName(currentMonth), RangeEnd: currentMonth.AddDate(0, 1, 0)},
+		{Name: partitionName(currentMonth.AddDate(0, 1, 0)), RangeEnd: currentMonth.AddDate(0, 2, 0)},
+	}, nil)
+
+	svc := NewPartitionService(mockRepo, mocks.NewDBManager(t), 1, 0, t.TempDir(), zerolog.Nop())
+
+	require.NoError(t, svc.EnsurePartitions(ctx))
+	mockRepo.AssertNotCalled(t, "CreatePartition")
+}
+
+func TestArchivePartitions_ExportsAndDropsOldPartitions(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	currentMonth := monthStart(time.Now())
+	oldStart := currentMonth.AddDate(0, -14, 0)
+	oldName := partitionName(oldStart)
+	path := archive.Path(dir, oldName)
+
+	mockRepo := mocks.NewPartitionRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockRepo.On("GetPartitions", ctx).Return([]*model.TransactionPartition{
+		{Name: oldName, RangeStart: &oldStart, RangeEnd: oldStart.AddDate(0, 1, 0)},
+		{Name: partitionName(currentMonth), RangeStart: &currentMonth, RangeEnd: currentMonth.AddDate(0, 1, 0)},
+	}, nil)
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockRepo.On("HasPendingReview", ctx, oldName, mock.Anything).Return(false, nil)
+	mockRepo.On("ExportPartition", ctx, oldName, mock.Anything, mock.Anything).Return(func(ctx context.Context, name string, fn func(*model.Transaction) error, tx pgx.Tx) error {
+		return fn(&model.Transaction{
+			ID:            7,
+			TransactionID: "550e8400-e29b-41d4-a716-446655440000",
+			UserID:        1,
+			State:         model.StateWin,
+			Amount:        decimal.NewFromInt(10),
+			Status:        model.StatusProcessed,
+		})
+	})
+	mockRepo.On("ArchivePartition", ctx, oldName, path, int64(1), mock.Anything).Return(nil)
+
+	svc := NewPartitionService(mockRepo, mockDBManager, 3, 12, dir, zerolog.Nop())
+
+	archived, err := svc.ArchivePartitions(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, oldName, archived[0].Name)
+	assert.Equal(t, int64(1), archived[0].RowCount)
+
+	trans, err := archive.Find(path, "550e8400-e29b-41d4-a716-446655440000")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), trans.ID)
+}
+
+func TestArchivePartitions_SkipsPendingReview(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	oldStart := monthStart(time.Now()).AddDate(0, -14, 0)
+	oldName := partitionName(oldStart)
+
+	mockRepo := mocks.NewPartitionRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockRepo.On("GetPartitions", ctx).Return([]*model.TransactionPartition{
+		{Name: oldName, RangeStart: &oldStart, RangeEnd: oldStart.AddDate(0, 1, 0)},
+	}, nil)
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockRepo.On("HasPendingReview", ctx, oldName, mock.Anything).Return(true, nil)
+
+	svc := NewPartitionService(mockRepo, mockDBManager, 3, 12, dir, zerolog.Nop())
+
+	archived, err := svc.ArchivePartitions(ctx)
+
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+	mockRepo.AssertNotCalled(t, "ArchivePartition")
+
+	_, err = os.Stat(filepath.Join(dir, oldName+archive.Extension))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestArchivePartitions_DisabledWithoutRetention(t *testing.T) {
+	svc := NewPartitionService(mocks.NewPartitionRepository(t), mocks.NewDBManager(t), 3, 0, t.TempDir(), zerolog.Nop())
+
+	archived, err := svc.ArchivePartitions(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type PaymentServiceImpl struct {
+	userRepo        repository.UserRepository
+	transactionRepo repository.TransactionRepository
+	paymentRepo     repository.PaymentRepository
+	dbManager       repository.DBManager
+	amountLimits    *AmountLimits
+	tenants         *Tenants
+	provider        PaymentProvider
+	logger          zerolog.Logger
+}
+
+func NewPaymentService(
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	paymentRepo repository.PaymentRepository,
+	dbManager repository.DBManager,
+	amountLimits *AmountLimits,
+	tenants *Tenants,
+	provider PaymentProvider,
+	logger zerolog.Logger,
+) PaymentService {
+	return &PaymentServiceImpl{
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		paymentRepo:     paymentRepo,
+		dbManager:       dbManager,
+		amountLimits:    amountLimits,
+		tenants:         tenants,
+		provider:        provider,
+		logger:          logger,
+	}
+}
+
+func (s *PaymentServiceImpl) RequestDeposit(ctx context.Context, userID int64, amount decimal.Decimal) (*model.Payment, error) {
+	if err := s.checkAmount(ctx, amount); err != nil {
+		return nil, err
+	}
+
+	// Fail early for unknown users instead of when the provider completes the deposit
+	user, err := s.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if err := auth.CheckUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	payment := newPayment(userID, model.PaymentDeposit, amount)
+	if err := s.paymentRepo.InsertPayment(ctx, payment); err != nil {
+		return nil, fmt.Errorf("insert payment: %w", err)
+	}
+
+	s.logPayment(payment).Msg("deposit requested")
+	return payment, nil
+}
+
+func (s *PaymentServiceImpl) RequestWithdrawal(ctx context.Context, userID int64, amount decimal.Decimal) (*model.Payment, error) {
+	if err := s.checkAmount(ctx, amount); err != nil {
+		return nil, err
+	}
+
+	payment := newPayment(userID, model.PaymentWithdrawal, amount)
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		user, err := s.userRepo.GetUserForUpdate(ctx, userID, tx)
+		if err != nil {
+			return fmt.Errorf("get user for update: %w", err)
+		}
+		if err := auth.CheckUser(ctx, user); err != nil {
+			return err
+		}
+		if user.MutationsPaused {
+			return fmt.Errorf("%w %d", model.ErrMutationsPaused, user.ID)
+		}
+
+		// Only real money can be withdrawn, the bonus balance stays until it is wagered
+		if user.Balance.LessThan(amount) {
+			return model.ErrInsufficientBalance
+		}
+		if err := s.userRepo.UpdateBalance(ctx, userID, user.Balance.Sub(amount), tx); err != nil {
+			return fmt.Errorf("update balance: %w", err)
+		}
+		if err := s.userRepo.UpdateReserved(ctx, userID, user.Reserved.Add(amount), tx); err != nil {
+			return fmt.Errorf("update reserved funds: %w", err)
+		}
+
+		if err := s.paymentRepo.InsertPayment(ctx, payment, tx); err != nil {
+			return fmt.Errorf("insert payment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logPayment(payment).Msg("withdrawal requested, funds reserved")
+	return payment, nil
+}
+
+func (s *PaymentServiceImpl) ListPayments(ctx context.Context, status model.PaymentStatus, limit, offset int) ([]*model.Payment, error) {
+	payments, err := s.paymentRepo.GetPayments(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+func (s *PaymentServiceImpl) ListUserPayments(ctx context.Context, userID int64, limit, offset int) ([]*model.Payment, error) {
+	if err := checkUserAccess(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.GetPaymentsByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get user payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+// ApprovePayment submits a payment in three steps so no row lock is held while the provider is called: the approval
+// is committed as submitting, the payment is submitted with its TransactionID as idempotency key, and the provider's
+// reference is recorded. A submission that fails, or whose reference is not recorded, leaves the payment submitting
+// and approving it again resubmits it, which the idempotency key keeps from moving the money twice.
+func (s *PaymentServiceImpl) ApprovePayment(ctx context.Context, id int64, operator string) (*model.Payment, error) {
+	if operator == "" {
+		return nil, errors.New("operator is required")
+	}
+
+	var payment *model.Payment
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		locked, err := s.paymentRepo.GetPaymentForUpdate(ctx, id, tx)
+		if err != nil {
+			return fmt.Errorf("get payment for update: %w", err)
+		}
+		if locked.Status == model.PaymentSubmitting {
+			payment = locked
+			return nil
+		}
+		if locked.Status != model.PaymentPending {
+			return fmt.Errorf("%w: payment %d has status %s", model.ErrPaymentStatusConflict, id, locked.Status)
+		}
+
+		locked.Status = model.PaymentSubmitting
+		locked.Provider = s.provider.Name()
+		locked.DecidedBy = operator
+		if err := s.updatePayment(ctx, locked, model.PaymentPending, tx); err != nil {
+			return err
+		}
+
+		payment = locked
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reference, err := s.provider.Submit(ctx, payment)
+	if err != nil {
+		return nil, fmt.Errorf("submit payment to %s: %w", s.provider.Name(), err)
+	}
+
+	err = s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		locked, err := s.lockPayment(ctx, id, model.PaymentSubmitting, tx)
+		if err != nil {
+			return err
+		}
+
+		locked.Status = model.PaymentApproved
+		locked.ProviderReference = reference
+		if err := s.updatePayment(ctx, locked, model.PaymentSubmitting, tx); err != nil {
+			return err
+		}
+
+		payment = locked
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("record reference %s: %w", reference, err)
+	}
+
+	s.logPayment(payment).
+		Str("operator", operator).
+		Str("provider_reference", payment.ProviderReference).
+		Msg("payment approved and submitted")
+	return payment, nil
+}
+
+func (s *PaymentServiceImpl) RejectPayment(ctx context.Context, id int64, operator, reason string) (*model.Payment, error) {
+	if operator == "" || reason == "" {
+		return nil, errors.New("reason and operator are required")
+	}
+
+	var result *model.Payment
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		payment, err := s.lockPayment(ctx, id, model.PaymentPending, tx)
+		if err != nil {
+			return err
+		}
+
+		payment.DecidedBy = operator
+		if err := s.failPayment(ctx, payment, model.PaymentPending, reason, tx); err != nil {
+			return err
+		}
+
+		result = payment
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logPayment(result).Str("operator", operator).Str("reason", reason).Msg("payment rejected")
+	return result, nil
+}
+
+func (s *PaymentServiceImpl) VerifyCallback(body []byte, signature string) error {
+	return s.provider.VerifyCallback(body, signature)
+}
+
+func (s *PaymentServiceImpl) HandleCallback(ctx context.Context, reference string, status model.PaymentStatus, reason string) (*model.Payment, error) {
+	if status != model.PaymentCompleted && status != model.PaymentFailed {
+		return nil, fmt.Errorf("%w: callbacks report completed or failed", model.ErrInvalidPaymentStatus)
+	}
+
+	var result *model.Payment
+	replayed := false
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		payment, err := s.paymentRepo.GetPaymentByReferenceForUpdate(ctx, s.provider.Name(), reference, tx)
+		if err != nil {
+			return fmt.Errorf("get payment by reference for update: %w", err)
+		}
+
+		// Providers retry callbacks until they are acknowledged
+		if payment.Status == status {
+			result, replayed = payment, true
+			return nil
+		}
+		if payment.Status != model.PaymentApproved {
+			return fmt.Errorf("%w: payment %d has status %s", model.ErrPaymentStatusConflict, payment.ID, payment.Status)
+		}
+
+		if status == model.PaymentCompleted {
+			err = s.completePayment(ctx, payment, tx)
+		} else {
+			err = s.failPayment(ctx, payment, model.PaymentApproved, reason, tx)
+		}
+		if err != nil {
+			return err
+		}
+
+		result = payment
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !replayed {
+		s.logPayment(result).
+			Str("provider_reference", reference).
+			Str("reason", result.FailureReason).
+			Msgf("payment %s by provider", result.Status)
+	}
+	return result, nil
+}
+
+// completePayment applies an approved payment to the locked user and records it as a 'settlement' transaction:
+// deposits credit the balance, withdrawals take their amount out of the reserved funds.
+// Completion is allowed for users paused by the integrity check: the provider has already moved the money,
+// refusing it would only fail the callback forever.
+func (s *PaymentServiceImpl) completePayment(ctx context.Context, payment *model.Payment, tx pgx.Tx) error {
+	user, err := s.userRepo.GetUserForUpdate(ctx, payment.UserID, tx)
+	if err != nil {
+		return fmt.Errorf("get user for update: %w", err)
+	}
+
+	newBalance := user.Balance
+	state, reason := model.StateWin, model.EventReasonDeposit
+	if payment.Kind == model.PaymentDeposit {
+		newBalance = user.Balance.Add(payment.Amount)
+		if err := s.userRepo.UpdateBalance(ctx, user.ID, newBalance, tx); err != nil {
+			return fmt.Errorf("update balance: %w", err)
+		}
+	} else {
+		state, reason = model.StateLost, model.EventReasonWithdrawal
+		if err := s.userRepo.UpdateReserved(ctx, user.ID, user.Reserved.Sub(payment.Amount), tx); err != nil {
+			return fmt.Errorf("update reserved funds: %w", err)
+		}
+	}
+
+	trans := &model.Transaction{
+		TransactionID: payment.TransactionID,
+		UserID:        payment.UserID,
+		SourceType:    model.SourceSettlement,
+		ProviderID:    payment.Provider,
+		State:         state,
+		Amount:        payment.Amount,
+		Status:        model.StatusProcessed,
+	}
+	if err := s.transactionRepo.InsertTransaction(ctx, trans, tx); err != nil {
+		return fmt.Errorf("insert transaction: %w", err)
+	}
+
+	err = recordEvent(ctx, s.transactionRepo, trans, &model.TransactionEvent{
+		ActorType:     model.ActorProvider,
+		Actor:         payment.Provider,
+		Reason:        reason,
+		Note:          payment.ProviderReference,
+		BalanceBefore: &user.Balance,
+		BalanceAfter:  &newBalance,
+	}, tx)
+	if err != nil {
+		return err
+	}
+
+	payment.Status = model.PaymentCompleted
+	return s.updatePayment(ctx, payment, model.PaymentApproved, tx)
+}
+
+// failPayment marks a payment failed and moves the reserved funds of a withdrawal back to the balance.
+// The release is allowed for users paused by the integrity check, it only returns their own funds.
+func (s *PaymentServiceImpl) failPayment(ctx context.Context, payment *model.Payment, from model.PaymentStatus, reason string, tx pgx.Tx) error {
+	if payment.Kind == model.PaymentWithdrawal {
+		user, err := s.userRepo.GetUserForUpdate(ctx, payment.UserID, tx)
+		if err != nil {
+			return fmt.Errorf("get user for update: %w", err)
+		}
+		if err := s.userRepo.UpdateBalance(ctx, user.ID, user.Balance.Add(payment.Amount), tx); err != nil {
+			return fmt.Errorf("update balance: %w", err)
+		}
+		if err := s.userRepo.UpdateReserved(ctx, user.ID, user.Reserved.Sub(payment.Amount), tx); err != nil {
+			return fmt.Errorf("update reserved funds: %w", err)
+		}
+	}
+
+	payment.Status = model.PaymentFailed
+	payment.FailureReason = reason
+	return s.updatePayment(ctx, payment, from, tx)
+}
+
+// lockPayment locks the payment row and checks it still has the expected status
+func (s *PaymentServiceImpl) lockPayment(ctx context.Context, id int64, status model.PaymentStatus, tx pgx.Tx) (*model.Payment, error) {
+	payment, err := s.paymentRepo.GetPaymentForUpdate(ctx, id, tx)
+	if err != nil {
+		return nil, fmt.Errorf("get payment for update: %w", err)
+	}
+	if payment.Status != status {
+		return nil, fmt.Errorf("%w: payment %d has status %s", model.ErrPaymentStatusConflict, id, payment.Status)
+	}
+	return payment, nil
+}
+
+func (s *PaymentServiceImpl) updatePayment(ctx context.Context, payment *model.Payment, from model.PaymentStatus, tx pgx.Tx) error {
+	updated, err := s.paymentRepo.UpdatePayment(ctx, payment, from, tx)
+	if err != nil {
+		return fmt.Errorf("update payment: %w", err)
+	}
+	if !updated {
+		return fmt.Errorf("%w: payment %d", model.ErrPaymentStatusConflict, payment.ID)
+	}
+	return nil
+}
+
+// checkAmount applies the tenant's source types and the amount limits of the payment source type and the provider.
+// The payment provider serves every tenant, so it is not checked against the tenant's providers.
+func (s *PaymentServiceImpl) checkAmount(ctx context.Context, amount decimal.Decimal) error {
+	if !amount.IsPositive() {
+		return fmt.Errorf("%w: payment must be positive", model.ErrInvalidAmount)
+	}
+	if err := s.tenants.CheckTransaction(ctx, model.SourcePayment, ""); err != nil {
+		return err
+	}
+	return s.tenants.AmountLimits(ctx, s.amountLimits).Check(amount, model.SourcePayment, s.provider.Name())
+}
+
+func (s *PaymentServiceImpl) logPayment(payment *model.Payment) *zerolog.Event {
+	return s.logger.Info().
+		Int64("payment_id", payment.ID).
+		Int64("user_id", payment.UserID).
+		Str("kind", string(payment.Kind)).
+		Str("amount", payment.Amount.StringFixed(2))
+}
+
+func newPayment(userID int64, kind model.PaymentKind, amount decimal.Decimal) *model.Payment {
+	return &model.Payment{
+		UserID:        userID,
+		Kind:          kind,
+		Amount:        amount,
+		Status:        model.PaymentPending,
+		TransactionID: uuid.New().String(),
+	}
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/payment"
+	"transaction-processor/mocks/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func paymentWithStatus(kind model.PaymentKind, status model.PaymentStatus) *model.Payment {
+	return &model.Payment{
+		ID:                7,
+		UserID:            1,
+		Kind:              kind,
+		Amount:            decimal.NewFromInt(30),
+		Status:            status,
+		TransactionID:     "550e8400-e29b-41d4-a716-446655440077",
+		Provider:          payment.StubName,
+		ProviderReference: "stub-ref",
+	}
+}
+
+func passThroughDB(t *testing.T, ctx context.Context) *mocks.DBManager {
+	mockDBManager := mocks.NewDBManager(t)
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
+		return fn(nil)
+	})
+	return mockDBManager
+}
+
+func decimalEq(v int64) interface{} {
+	return mock.MatchedBy(func(d decimal.Decimal) bool { return d.Equal(decimal.NewFromInt(v)) })
+}
+
+func TestPaymentService_RequestWithdrawal_ReservesFunds(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:       1,
+		Balance:  decimal.NewFromInt(100),
+		Reserved: decimal.NewFromInt(10),
+	}, nil)
+	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimalEq(70), mock.Anything).Return(nil)
+	mockUserRepo.On("UpdateReserved", ctx, int64(1), decimalEq(40), mock.Anything).Return(nil)
+	mockPaymentRepo.On("InsertPayment", ctx, mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Kind == model.PaymentWithdrawal && p.Status == model.PaymentPending && p.TransactionID != ""
+	}), mock.Anything).Return(nil)
+
+	service := NewPaymentService(mockUserRepo, nil, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	p, err := service.RequestWithdrawal(ctx, 1, decimal.NewFromInt(30))
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentPending, p.Status)
+}
+
+func TestPaymentService_RequestWithdrawal_UserOfOtherProvider(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "acme-games", ProviderID: "acme"})
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:         1,
+		ProviderID: "other",
+		Balance:    decimal.NewFromInt(100),
+	}, nil)
+
+	service := NewPaymentService(mockUserRepo, nil, nil, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	_, err := service.RequestWithdrawal(ctx, 1, decimal.NewFromInt(30))
+
+	assert.ErrorIs(t, err, model.ErrProviderNotAllowed)
+	mockUserRepo.AssertNotCalled(t, "UpdateReserved")
+}
+
+func TestPaymentService_RequestWithdrawal_BonusCannotBeWithdrawn(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:           1,
+		Balance:      decimal.NewFromInt(10),
+		BonusBalance: decimal.NewFromInt(100),
+	}, nil)
+
+	service := NewPaymentService(mockUserRepo, nil, nil, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	_, err := service.RequestWithdrawal(ctx, 1, decimal.NewFromInt(30))
+
+	assert.ErrorIs(t, err, model.ErrInsufficientBalance)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestPaymentService_RequestDeposit_AmountLimits(t *testing.T) {
+	limits, err := NewAmountLimits(config.LimitsConfig{
+		SourceMaxAmount: map[string]decimal.Decimal{"payment": decimal.NewFromInt(1000)},
+	})
+	assert.NoError(t, err)
+
+	service := NewPaymentService(nil, nil, nil, nil, limits, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	_, err = service.RequestDeposit(context.Background(), 1, decimal.NewFromInt(5000))
+
+	assert.ErrorIs(t, err, model.ErrAmountOutOfRange)
+}
+
+// failingProvider is a payment provider that is down
+type failingProvider struct {
+	*payment.StubProvider
+}
+
+func (failingProvider) Submit(context.Context, *model.Payment) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func TestPaymentService_ApprovePayment_SubmitsToProvider(t *testing.T) {
+	ctx := context.Background()
+
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+	mockPaymentRepo.On("GetPaymentForUpdate", ctx, int64(7), mock.Anything).
+		Return(paymentWithStatus(model.PaymentDeposit, model.PaymentPending), nil).Once()
+	mockPaymentRepo.On("UpdatePayment", ctx, mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.PaymentSubmitting && p.Provider == payment.StubName && p.DecidedBy == "alice"
+	}), model.PaymentPending, mock.Anything).Return(true, nil).Once()
+	// The reference is recorded in a second transaction, after the submission
+	mockPaymentRepo.On("GetPaymentForUpdate", ctx, int64(7), mock.Anything).
+		Return(paymentWithStatus(model.PaymentDeposit, model.PaymentSubmitting), nil).Once()
+	mockPaymentRepo.On("UpdatePayment", ctx, mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.PaymentApproved && p.ProviderReference == "stub-550e8400-e29b-41d4-a716-446655440077"
+	}), model.PaymentSubmitting, mock.Anything).Return(true, nil).Once()
+
+	service := NewPaymentService(nil, nil, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	p, err := service.ApprovePayment(ctx, 7, "alice")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentApproved, p.Status)
+}
+
+func TestPaymentService_ApprovePayment_SubmissionFailureStaysSubmitting(t *testing.T) {
+	ctx := context.Background()
+
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+	mockPaymentRepo.On("GetPaymentForUpdate", ctx, int64(7), mock.Anything).
+		Return(paymentWithStatus(model.PaymentDeposit, model.PaymentPending), nil).Once()
+	mockPaymentRepo.On("UpdatePayment", ctx, mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.PaymentSubmitting
+	}), model.PaymentPending, mock.Anything).Return(true, nil).Once()
+
+	service := NewPaymentService(nil, nil, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, failingProvider{payment.NewStubProvider("secret")}, zerolog.Nop())
+	_, err := service.ApprovePayment(ctx, 7, "alice")
+
+	assert.ErrorContains(t, err, "connection refused")
+	mockPaymentRepo.AssertNumberOfCalls(t, "UpdatePayment", 1)
+}
+
+func TestPaymentService_ApprovePayment_RetriesSubmission(t *testing.T) {
+	ctx := context.Background()
+
+	// A previous approval committed the submitting state but not the reference
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+	mockPaymentRepo.On("GetPaymentForUpdate", ctx, int64(7), mock.Anything).
+		Return(paymentWithStatus(model.PaymentWithdrawal, model.PaymentSubmitting), nil).Twice()
+	mockPaymentRepo.On("UpdatePayment", ctx, mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.PaymentApproved && p.ProviderReference == "stub-550e8400-e29b-41d4-a716-446655440077"
+	}), model.PaymentSubmitting, mock.Anything).Return(true, nil).Once()
+
+	service := NewPaymentService(nil, nil, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	p, err := service.ApprovePayment(ctx, 7, "bob")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentApproved, p.Status)
+}
+
+func TestPaymentService_RejectPayment_ReleasesReservedFunds(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+
+	mockPaymentRepo.On("GetPaymentForUpdate", ctx, int64(7), mock.Anything).
+		Return(paymentWithStatus(model.PaymentWithdrawal, model.PaymentPending), nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:       1,
+		Balance:  decimal.NewFromInt(70),
+		Reserved: decimal.NewFromInt(30),
+	}, nil)
+	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimalEq(100), mock.Anything).Return(nil)
+	mockUserRepo.On("UpdateReserved", ctx, int64(1), decimalEq(0), mock.Anything).Return(nil)
+	mockPaymentRepo.On("UpdatePayment", ctx, mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.PaymentFailed && p.FailureReason == "kyc" && p.DecidedBy == "alice"
+	}), model.PaymentPending, mock.Anything).Return(true, nil)
+
+	service := NewPaymentService(mockUserRepo, nil, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	p, err := service.RejectPayment(ctx, 7, "alice", "kyc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentFailed, p.Status)
+}
+
+func TestPaymentService_HandleCallback_CompletesDeposit(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+
+	mockPaymentRepo.On("GetPaymentByReferenceForUpdate", ctx, payment.StubName, "stub-ref", mock.Anything).
+		Return(paymentWithStatus(model.PaymentDeposit, model.PaymentApproved), nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{ID: 1, Balance: decimal.NewFromInt(100)}, nil)
+	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimalEq(130), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(t *model.Transaction) bool {
+		return t.SourceType == model.SourceSettlement && t.State == model.StateWin && t.Amount.Equal(decimal.NewFromInt(30)) &&
+			t.TransactionID == "550e8400-e29b-41d4-a716-446655440077"
+	}), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.ActorType == model.ActorProvider && e.Actor == payment.StubName && e.Reason == model.EventReasonDeposit
+	}), mock.Anything).Return(nil)
+	mockPaymentRepo.On("UpdatePayment", ctx, mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.PaymentCompleted
+	}), model.PaymentApproved, mock.Anything).Return(true, nil)
+
+	service := NewPaymentService(mockUserRepo, mockTransRepo, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	p, err := service.HandleCallback(ctx, "stub-ref", model.PaymentCompleted, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentCompleted, p.Status)
+}
+
+func TestPaymentService_HandleCallback_CompletesWithdrawalFromReservedFunds(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+
+	mockPaymentRepo.On("GetPaymentByReferenceForUpdate", ctx, payment.StubName, "stub-ref", mock.Anything).
+		Return(paymentWithStatus(model.PaymentWithdrawal, model.PaymentApproved), nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:       1,
+		Balance:  decimal.NewFromInt(70),
+		Reserved: decimal.NewFromInt(30),
+	}, nil)
+	mockUserRepo.On("UpdateReserved", ctx, int64(1), decimalEq(0), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(t *model.Transaction) bool {
+		return t.SourceType == model.SourceSettlement && t.State == model.StateLost && t.Amount.Equal(decimal.NewFromInt(30))
+	}), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.Reason == model.EventReasonWithdrawal && e.BalanceBefore.Equal(*e.BalanceAfter)
+	}), mock.Anything).Return(nil)
+	mockPaymentRepo.On("UpdatePayment", ctx, mock.Anything, model.PaymentApproved, mock.Anything).Return(true, nil)
+
+	service := NewPaymentService(mockUserRepo, mockTransRepo, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	_, err := service.HandleCallback(ctx, "stub-ref", model.PaymentCompleted, "")
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestPaymentService_HandleCallback_CompletesForPausedUser(t *testing.T) {
+	ctx := context.Background()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+
+	mockPaymentRepo.On("GetPaymentByReferenceForUpdate", ctx, payment.StubName, "stub-ref", mock.Anything).
+		Return(paymentWithStatus(model.PaymentWithdrawal, model.PaymentApproved), nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:              1,
+		Balance:         decimal.NewFromInt(70),
+		Reserved:        decimal.NewFromInt(30),
+		MutationsPaused: true,
+	}, nil)
+	mockUserRepo.On("UpdateReserved", ctx, int64(1), decimalEq(0), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockPaymentRepo.On("UpdatePayment", ctx, mock.Anything, model.PaymentApproved, mock.Anything).Return(true, nil)
+
+	service := NewPaymentService(mockUserRepo, mockTransRepo, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	p, err := service.HandleCallback(ctx, "stub-ref", model.PaymentCompleted, "")
+
+	// The money has left, refusing the callback would fail every retry of the provider
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentCompleted, p.Status)
+}
+
+func TestPaymentService_HandleCallback_RetryIsAcknowledged(t *testing.T) {
+	ctx := context.Background()
+
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+	mockPaymentRepo.On("GetPaymentByReferenceForUpdate", ctx, payment.StubName, "stub-ref", mock.Anything).
+		Return(paymentWithStatus(model.PaymentWithdrawal, model.PaymentFailed), nil)
+
+	service := NewPaymentService(nil, nil, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	p, err := service.HandleCallback(ctx, "stub-ref", model.PaymentFailed, "declined")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentFailed, p.Status)
+	mockPaymentRepo.AssertNotCalled(t, "UpdatePayment")
+}
+
+func TestPaymentService_HandleCallback_NotApproved(t *testing.T) {
+	ctx := context.Background()
+
+	mockPaymentRepo := mocks.NewPaymentRepository(t)
+	mockPaymentRepo.On("GetPaymentByReferenceForUpdate", ctx, payment.StubName, "stub-ref", mock.Anything).
+		Return(paymentWithStatus(model.PaymentDeposit, model.PaymentCompleted), nil)
+
+	service := NewPaymentService(nil, nil, mockPaymentRepo, passThroughDB(t, ctx), nil, nil, payment.NewStubProvider("secret"), zerolog.Nop())
+	_, err := service.HandleCallback(ctx, "stub-ref", model.PaymentFailed, "declined")
+
+	assert.ErrorIs(t, err, model.ErrPaymentStatusConflict)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+type ReviewServiceImpl struct {
+	userRepo        repository.UserRepository
+	transactionRepo repository.TransactionRepository
+	limitRepo       repository.LimitRepository
+	dbManager       repository.DBManager
+	debt            *DebtPolicy
+	bonus           *BonusPolicy
+	logger          zerolog.Logger
+}
+
+func NewReviewService(
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	limitRepo repository.LimitRepository,
+	dbManager repository.DBManager,
+	debt *DebtPolicy,
+	bonus *BonusPolicy,
+	logger zerolog.Logger,
+) ReviewService {
+	return &ReviewServiceImpl{
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		limitRepo:       limitRepo,
+		dbManager:       dbManager,
+		debt:            debt,
+		bonus:           bonus,
+		logger:          logger,
+	}
+}
+
+func (s *ReviewServiceImpl) ListPendingReviews(ctx context.Context, limit, offset int) ([]*model.Transaction, error) {
+	transactions, err := s.transactionRepo.GetPendingReviewTransactions(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get pending review transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+func (s *ReviewServiceImpl) ApproveTransaction(ctx context.Context, transactionID, reviewer, note string) (*model.Transaction, error) {
+	var result *model.Transaction
+
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		trans, err := s.lockPendingTransaction(ctx, transactionID, tx)
+		if err != nil {
+			return err
+		}
+
+		user, err := s.userRepo.GetUserForUpdate(ctx, trans.UserID, tx)
+		if err != nil {
+			return fmt.Errorf("get user for update: %w", err)
+		}
+
+		change, err := applyTransaction(ctx, s.userRepo, s.limitRepo, user, trans.State, trans.Amount,
+			s.debt.Enabled(trans.SourceType), s.bonus, tx)
+		if err != nil {
+			return err
+		}
+
+		trans.BonusAmount = change.BonusAmount
+		if err := s.completeReview(ctx, trans, model.StatusProcessed, reviewer, note, tx); err != nil {
+			return err
+		}
+
+		err = recordEvent(ctx, s.transactionRepo, trans, withWallets(&model.TransactionEvent{
+			FromStatus:    model.StatusPendingReview,
+			ActorType:     model.ActorOperator,
+			Actor:         reviewer,
+			Reason:        model.EventReasonReviewApproved,
+			Note:          note,
+			BalanceBefore: &user.Balance,
+			BalanceAfter:  &change.Balance,
+		}, user, change.Debt, change.Bonus), tx)
+		if err != nil {
+			return err
+		}
+
+		s.logger.Info().
+			Str("transaction_id", trans.TransactionID).
+			Int64("user_id", trans.UserID).
+			Str("reviewer", reviewer).
+			Str("amount", trans.Amount.StringFixed(2)).
+			Str("new_balance", change.Balance.StringFixed(2)).
+			Msg("transaction approved")
+
+		result = trans
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *ReviewServiceImpl) RejectTransaction(ctx context.Context, transactionID, reviewer, note string) (*model.Transaction, error) {
+	var result *model.Transaction
+
+	err := s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
+		trans, err := s.lockPendingTransaction(ctx, transactionID, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := s.completeReview(ctx, trans, model.StatusRejected, reviewer, note, tx); err != nil {
+			return err
+		}
+
+		// The balance is untouched and the user not locked, the event has no balances
+		err = recordEvent(ctx, s.transactionRepo, trans, &model.TransactionEvent{
+			FromStatus: model.StatusPendingReview,
+			ActorType:  model.ActorOperator,
+			Actor:      reviewer,
+			Reason:     model.EventReasonReviewRejected,
+			Note:       note,
+		}, tx)
+		if err != nil {
+			return err
+		}
+
+		s.logger.Info().
+			Str("transaction_id", trans.TransactionID).
+			Int64("user_id", trans.UserID).
+			Str("reviewer", reviewer).
+			Msg("transaction rejected")
+
+		result = trans
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// lockPendingTransaction locks the transaction row and checks that it still awaits a decision
+func (s *ReviewServiceImpl) lockPendingTransaction(ctx context.Context, transactionID string, tx pgx.Tx) (*model.Transaction, error) {
+	trans, err := s.transactionRepo.GetTransactionForUpdate(ctx, transactionID, tx)
+	if err != nil {
+		return nil, fmt.Errorf("get transaction for update: %w", err)
+	}
+
+	if trans.Status != model.StatusPendingReview {
+		return nil, fmt.Errorf("%w: transaction %s has status %s", model.ErrNotPendingReview, transactionID, trans.Status)
+	}
+	return trans, nil
+}
+
+func (s *ReviewServiceImpl) completeReview(ctx context.Context, trans *model.Transaction, status model.TransactionStatus, reviewer, note string, tx pgx.Tx) error {
+	updated, err := s.transactionRepo.CompleteReview(ctx, trans.ID, status, reviewer, note, trans.BonusAmount, tx)
+	if err != nil {
+		return fmt.Errorf("complete review: %w", err)
+	}
+	if !updated {
+		return fmt.Errorf("%w: transaction %s", model.ErrNotPendingReview, trans.TransactionID)
+	}
+
+	reviewedAt := time.Now().UTC()
+	trans.Status = status
+	trans.ReviewedBy = reviewer
+	trans.ReviewedAt = &reviewedAt
+	trans.ReviewNote = note
+	return nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"transaction-processor/internal/model"
+	"transaction-processor/mocks/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const reviewTransactionID = "550e8400-e29b-41d4-a716-446655440100"
+
+func TestReviewService_ApproveTransaction_AppliesBalance(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransactionForUpdate", ctx, reviewTransactionID, mock.Anything).Return(&model.Transaction{
+		ID:            7,
+		TransactionID: reviewTransactionID,
+		UserID:        1,
+		State:         model.StateWin,
+		Amount:        decimal.NewFromInt(5000),
+		Status:        model.StatusPendingReview,
+	}, nil)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{ID: 1, Balance: decimal.NewFromInt(100)}, nil)
+	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimal.NewFromInt(5100), mock.Anything).Return(nil)
+	mockLimitRepo.On("AddNetLoss", ctx, int64(1), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockTransRepo.On("CompleteReview", ctx, int64(7), model.StatusProcessed, "alice", "checked", mock.Anything, mock.Anything).Return(true, nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.FromStatus == model.StatusPendingReview && e.ToStatus == model.StatusProcessed && e.Reason == model.EventReasonReviewApproved
+	}), mock.Anything).Return(nil)
+
+	service := NewReviewService(mockUserRepo, mockTransRepo, mockLimitRepo, mockDBManager, nil, nil, logger)
+
+	trans, err := service.ApproveTransaction(ctx, reviewTransactionID, "alice", "checked")
+
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusProcessed, trans.Status)
+	assert.Equal(t, "alice", trans.ReviewedBy)
+	assert.NotNil(t, trans.ReviewedAt)
+}
+
+func TestReviewService_RejectTransaction_KeepsBalance(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransactionForUpdate", ctx, reviewTransactionID, mock.Anything).Return(&model.Transaction{
+		ID:            7,
+		TransactionID: reviewTransactionID,
+		UserID:        1,
+		State:         model.StateWin,
+		Amount:        decimal.NewFromInt(5000),
+		Status:        model.StatusPendingReview,
+	}, nil)
+	mockTransRepo.On("CompleteReview", ctx, int64(7), model.StatusRejected, "alice", "", mock.Anything, mock.Anything).Return(true, nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.FromStatus == model.StatusPendingReview && e.ToStatus == model.StatusRejected && e.BalanceAfter == nil
+	}), mock.Anything).Return(nil)
+
+	service := NewReviewService(mockUserRepo, mockTransRepo, mockLimitRepo, mockDBManager, nil, nil, logger)
+
+	trans, err := service.RejectTransaction(ctx, reviewTransactionID, "alice", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusRejected, trans.Status)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestReviewService_ApproveTransaction_NotPending(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransactionForUpdate", ctx, reviewTransactionID, mock.Anything).Return(&model.Transaction{
+		ID:            7,
+		TransactionID: reviewTransactionID,
+		UserID:        1,
+		Status:        model.StatusProcessed,
+	}, nil)
+
+	service := NewReviewService(mockUserRepo, mockTransRepo, mockLimitRepo, mockDBManager, nil, nil, logger)
+
+	trans, err := service.ApproveTransaction(ctx, reviewTransactionID, "alice", "")
+
+	require.Error(t, err)
+	assert.Nil(t, trans)
+	assert.ErrorIs(t, err, model.ErrNotPendingReview)
+}
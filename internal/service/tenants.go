@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tenantConfig is the declarative form of a tenant in the tenants file
+type tenantConfig struct {
+	ID string `yaml:"id"`
+	// APIKeysSHA256 are the hex SHA-256 hashes of the keys clients send in X-API-Key
+	APIKeysSHA256 []string `yaml:"api_keys_sha256"`
+	// Providers belong to this tenant only, other tenants cannot post transactions with their Provider-ID
+	Providers []string `yaml:"providers"`
+	// SourceTypes the tenant may post, all when empty
+	SourceTypes []string `yaml:"source_types"`
+	// Limits replace the global amount limits for the tenant's transactions when set
+	Limits *config.LimitsConfig `yaml:"limits"`
+	// ShortfallPolicy replaces CANCELLATION_SHORTFALL_POLICY for the cancellation worker when set
+	ShortfallPolicy string `yaml:"shortfall_policy"`
+}
+
+type tenant struct {
+	id        string
+	providers map[string]bool
+	// sourceTypes is nil when every source type is allowed
+	sourceTypes     map[model.SourceType]bool
+	limits          *AmountLimits
+	shortfallPolicy model.ShortfallPolicy
+}
+
+// Tenants resolves API keys to tenants and holds their configuration. Without tenants, e.g. a nil *Tenants,
+// every request belongs to model.DefaultTenant and the global configuration applies.
+type Tenants struct {
+	byID  map[string]*tenant
+	byKey map[string]*tenant
+	// owners maps every provider listed in the file to its tenant
+	owners map[string]string
+}
+
+// LoadTenants reads tenants from a YAML file
+func LoadTenants(path string) (*Tenants, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants: %w", err)
+	}
+	return ParseTenants(data)
+}
+
+// ParseTenants builds the tenants from a YAML document
+func ParseTenants(data []byte) (*Tenants, error) {
+	var doc struct {
+		Tenants []tenantConfig `yaml:"tenants"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse tenants: %w", err)
+	}
+
+	t := &Tenants{
+		byID:   make(map[string]*tenant, len(doc.Tenants)),
+		byKey:  make(map[string]*tenant),
+		owners: make(map[string]string),
+	}
+	for i, cfg := range doc.Tenants {
+		tn, err := cfg.build()
+		if err != nil {
+			return nil, fmt.Errorf("tenant %d (%s): %w", i, cfg.ID, err)
+		}
+		if t.byID[tn.id] != nil {
+			return nil, fmt.Errorf("tenant %d: duplicate id %q", i, tn.id)
+		}
+		t.byID[tn.id] = tn
+
+		for _, key := range cfg.APIKeysSHA256 {
+			if t.byKey[key] != nil {
+				return nil, fmt.Errorf("tenant %d (%s): API key hash %s is used by tenant %s", i, tn.id, key, t.byKey[key].id)
+			}
+			t.byKey[key] = tn
+		}
+		for provider := range tn.providers {
+			if owner, ok := t.owners[provider]; ok {
+				return nil, fmt.Errorf("tenant %d (%s): provider %s belongs to tenant %s", i, tn.id, provider, owner)
+			}
+			t.owners[provider] = tn.id
+		}
+	}
+	return t, nil
+}
+
+func (c tenantConfig) build() (*tenant, error) {
+	if c.ID == "" || len(c.ID) > 50 {
+		return nil, fmt.Errorf("id must have 1 to 50 characters")
+	}
+	if len(c.APIKeysSHA256) == 0 {
+		return nil, fmt.Errorf("api_keys_sha256 must not be empty")
+	}
+	for _, key := range c.APIKeysSHA256 {
+		if decoded, err := hex.DecodeString(key); err != nil || len(decoded) != sha256.Size || key != hex.EncodeToString(decoded) {
+			return nil, fmt.Errorf("api key hash %q must be 64 lowercase hex characters", key)
+		}
+	}
+
+	tn := &tenant{id: c.ID, providers: make(map[string]bool, len(c.Providers))}
+	for _, provider := range c.Providers {
+		tn.providers[provider] = true
+	}
+
+	if len(c.SourceTypes) > 0 {
+		tn.sourceTypes = make(map[model.SourceType]bool, len(c.SourceTypes))
+		for _, name := range c.SourceTypes {
+			sourceType, err := model.ParseSourceType(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", err, name)
+			}
+			tn.sourceTypes[sourceType] = true
+		}
+	}
+
+	if c.Limits != nil {
+		limits, err := NewAmountLimits(*c.Limits)
+		if err != nil {
+			return nil, fmt.Errorf("limits: %w", err)
+		}
+		tn.limits = limits
+	}
+
+	if c.ShortfallPolicy != "" {
+		policy, err := model.ParseShortfallPolicy(c.ShortfallPolicy)
+		if err != nil {
+			return nil, err
+		}
+		tn.shortfallPolicy = policy
+	}
+	return tn, nil
+}
+
+// Enabled reports whether requests must authenticate as one of several tenants
+func (t *Tenants) Enabled() bool {
+	return t != nil && len(t.byID) > 0
+}
+
+// Resolve returns the tenant of an API key, ErrUnauthenticated if the key is missing or unknown
+func (t *Tenants) Resolve(apiKey string) (string, error) {
+	if !t.Enabled() {
+		return model.DefaultTenant, nil
+	}
+	if apiKey == "" {
+		return "", model.ErrUnauthenticated
+	}
+
+	sum := sha256.Sum256([]byte(apiKey))
+	tn, ok := t.byKey[hex.EncodeToString(sum[:])]
+	if !ok {
+		return "", model.ErrUnauthenticated
+	}
+	return tn.id, nil
+}
+
+// Check returns ErrInvalidToken if a token names a tenant that is not configured, only the default tenant
+// exists without tenants
+func (t *Tenants) Check(tenantID string) error {
+	if !t.Enabled() && tenantID == model.DefaultTenant || t.Enabled() && t.byID[tenantID] != nil {
+		return nil
+	}
+	return fmt.Errorf("%w: unknown tenant %s", model.ErrInvalidToken, tenantID)
+}
+
+// CheckTransaction returns ErrSourceTypeNotAllowed or ErrProviderNotAllowed if the tenant of the context may not
+// post the transaction. A provider must be one of the tenant's, or any provider not listed by another tenant
+// when the tenant lists none.
+func (t *Tenants) CheckTransaction(ctx context.Context, sourceType model.SourceType, providerID string) error {
+	tn := t.lookup(ctx)
+	if tn == nil {
+		return nil
+	}
+
+	if tn.sourceTypes != nil && !tn.sourceTypes[sourceType] {
+		return fmt.Errorf("%w: %s for tenant %s", model.ErrSourceTypeNotAllowed, sourceType, tn.id)
+	}
+	if providerID == "" {
+		return nil
+	}
+	return t.CheckProvider(ctx, providerID)
+}
+
+// CheckProvider returns ErrProviderNotAllowed if the provider is not one of the tenant of the context, see
+// CheckTransaction
+func (t *Tenants) CheckProvider(ctx context.Context, providerID string) error {
+	tn := t.lookup(ctx)
+	if tn == nil || tn.providers[providerID] {
+		return nil
+	}
+	if _, owned := t.owners[providerID]; owned || len(tn.providers) > 0 {
+		return fmt.Errorf("%w: %s for tenant %s", model.ErrProviderNotAllowed, providerID, tn.id)
+	}
+	return nil
+}
+
+// AmountLimits returns the limits of the tenant of the context, or fallback if it has none
+func (t *Tenants) AmountLimits(ctx context.Context, fallback *AmountLimits) *AmountLimits {
+	if tn := t.lookup(ctx); tn != nil && tn.limits != nil {
+		return tn.limits
+	}
+	return fallback
+}
+
+// ShortfallPolicy returns the cancellation worker's policy for a tenant, or fallback if it has none
+func (t *Tenants) ShortfallPolicy(tenantID string, fallback model.ShortfallPolicy) model.ShortfallPolicy {
+	if !t.Enabled() {
+		return fallback
+	}
+	if tn, ok := t.byID[tenantID]; ok && tn.shortfallPolicy != "" {
+		return tn.shortfallPolicy
+	}
+	return fallback
+}
+
+// lookup returns the configuration of the tenant of the context, nil in system scope or without tenants
+func (t *Tenants) lookup(ctx context.Context) *tenant {
+	if !t.Enabled() {
+		return nil
+	}
+	return t.byID[repository.TenantID(ctx)]
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"transaction-processor/internal/config"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func testTenants(t *testing.T) *Tenants {
+	tenants, err := ParseTenants([]byte(fmt.Sprintf(`
+tenants:
+  - id: default
+    api_keys_sha256: [%s]
+  - id: brand-a
+    api_keys_sha256: [%s]
+    providers: [acme]
+    source_types: [game, payment]
+    limits:
+      source_max_amount:
+        game: 100
+    shortfall_policy: debt
+  - id: brand-b
+    api_keys_sha256: [%s]
+`, keyHash("platform-key"), keyHash("key-a"), keyHash("key-b"))))
+	require.NoError(t, err)
+	return tenants
+}
+
+func TestTenants_Resolve(t *testing.T) {
+	tenants := testTenants(t)
+
+	tenantID, err := tenants.Resolve("key-a")
+	require.NoError(t, err)
+	assert.Equal(t, "brand-a", tenantID)
+
+	_, err = tenants.Resolve("")
+	assert.ErrorIs(t, err, model.ErrUnauthenticated)
+	_, err = tenants.Resolve("unknown")
+	assert.ErrorIs(t, err, model.ErrUnauthenticated)
+}
+
+func TestTenants_DisabledUsesDefaultTenant(t *testing.T) {
+	var tenants *Tenants
+
+	tenantID, err := tenants.Resolve("")
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultTenant, tenantID)
+	assert.NoError(t, tenants.CheckTransaction(context.Background(), model.SourceServer, "acme"))
+	assert.Equal(t, model.ShortfallSkip, tenants.ShortfallPolicy("brand-a", model.ShortfallSkip))
+}
+
+func TestTenants_Check(t *testing.T) {
+	tenants := testTenants(t)
+	assert.NoError(t, tenants.Check("brand-a"))
+	assert.ErrorIs(t, tenants.Check("brand-z"), model.ErrInvalidToken)
+
+	var disabled *Tenants
+	assert.NoError(t, disabled.Check(model.DefaultTenant))
+	assert.ErrorIs(t, disabled.Check("brand-a"), model.ErrInvalidToken)
+}
+
+func TestTenants_CheckTransaction(t *testing.T) {
+	tenants := testTenants(t)
+	brandA := repository.WithTenant(context.Background(), "brand-a")
+	brandB := repository.WithTenant(context.Background(), "brand-b")
+
+	assert.NoError(t, tenants.CheckTransaction(brandA, model.SourceGame, "acme"))
+	assert.NoError(t, tenants.CheckTransaction(brandA, model.SourceGame, ""))
+	assert.ErrorIs(t, tenants.CheckTransaction(brandA, model.SourceServer, "acme"), model.ErrSourceTypeNotAllowed)
+	assert.ErrorIs(t, tenants.CheckTransaction(brandA, model.SourceGame, "other"), model.ErrProviderNotAllowed)
+
+	// A tenant without providers may use any provider no other tenant owns
+	assert.NoError(t, tenants.CheckTransaction(brandB, model.SourceServer, "other"))
+	assert.ErrorIs(t, tenants.CheckTransaction(brandB, model.SourceGame, "acme"), model.ErrProviderNotAllowed)
+
+	// System scope is not restricted
+	assert.NoError(t, tenants.CheckTransaction(context.Background(), model.SourceServer, "acme"))
+}
+
+func TestTenants_AmountLimits(t *testing.T) {
+	tenants := testTenants(t)
+	global, err := NewAmountLimits(config.LimitsConfig{
+		SourceMaxAmount: map[string]decimal.Decimal{"game": decimal.NewFromInt(1000)},
+	})
+	require.NoError(t, err)
+
+	limits := tenants.AmountLimits(repository.WithTenant(context.Background(), "brand-a"), global)
+	assert.ErrorIs(t, limits.Check(decimal.NewFromInt(500), model.SourceGame, ""), model.ErrAmountOutOfRange)
+
+	limits = tenants.AmountLimits(repository.WithTenant(context.Background(), "brand-b"), global)
+	assert.Same(t, global, limits)
+}
+
+func TestTenants_ShortfallPolicy(t *testing.T) {
+	tenants := testTenants(t)
+
+	assert.Equal(t, model.ShortfallDebt, tenants.ShortfallPolicy("brand-a", model.ShortfallSkip))
+	assert.Equal(t, model.ShortfallSkip, tenants.ShortfallPolicy("brand-b", model.ShortfallSkip))
+}
+
+func TestParseTenants_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"missing id", fmt.Sprintf("tenants:\n  - api_keys_sha256: [%s]\n", keyHash("a"))},
+		{"missing key", "tenants:\n  - id: a\n"},
+		{"key not hashed", "tenants:\n  - id: a\n    api_keys_sha256: [plain-key]\n"},
+		{"duplicate id", fmt.Sprintf("tenants:\n  - id: a\n    api_keys_sha256: [%s]\n  - id: a\n    api_keys_sha256: [%s]\n",
+			keyHash("a"), keyHash("b"))},
+		{"shared key", fmt.Sprintf("tenants:\n  - id: a\n    api_keys_sha256: [%s]\n  - id: b\n    api_keys_sha256: [%s]\n",
+			keyHash("a"), keyHash("a"))},
+		{"shared provider", fmt.Sprintf("tenants:\n  - id: a\n    api_keys_sha256: [%s]\n    providers: [acme]\n"+
+			"  - id: b\n    api_keys_sha256: [%s]\n    providers: [acme]\n", keyHash("a"), keyHash("b"))},
+		{"unknown source type", fmt.Sprintf("tenants:\n  - id: a\n    api_keys_sha256: [%s]\n    source_types: [casino]\n", keyHash("a"))},
+		{"unknown shortfall policy", fmt.Sprintf("tenants:\n  - id: a\n    api_keys_sha256: [%s]\n    shortfall_policy: forgive\n", keyHash("a"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTenants([]byte(tt.doc))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/metrics"
 	"transaction-processor/internal/model"
 	"transaction-processor/internal/repository"
+	"transaction-processor/internal/risk"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog"
@@ -15,29 +21,64 @@ import (
 // rollback and check for duplicate outside tx
 var errDuplicateInsertRace = errors.New("duplicate transaction insert race")
 
+// riskWinStatsWindow is how many of the user's latest wins the average win of the fraud rules is taken over,
+// which keeps the query bounded while the user row is locked
+const riskWinStatsWindow = 100
+
+// transactionIdempotencyScope stores ProcessTransaction responses keyed by transaction_id
+const transactionIdempotencyScope = "transaction"
+
 type TransactionServiceImpl struct {
 	userRepo        repository.UserRepository
 	transactionRepo repository.TransactionRepository
+	limitRepo       repository.LimitRepository
+	idempotencyRepo repository.IdempotencyRepository
 	dbManager       repository.DBManager
+	amountLimits    *AmountLimits
+	tenants         *Tenants
+	debt            *DebtPolicy
+	bonus           *BonusPolicy
+	riskEngine      *risk.Engine
+	idempotencyTTL  time.Duration
 	logger          zerolog.Logger
 }
 
 func NewTransactionService(
 	userRepo repository.UserRepository,
 	transactionRepo repository.TransactionRepository,
+	limitRepo repository.LimitRepository,
+	idempotencyRepo repository.IdempotencyRepository,
 	dbManager repository.DBManager,
+	amountLimits *AmountLimits,
+	tenants *Tenants,
+	debt *DebtPolicy,
+	bonus *BonusPolicy,
+	riskEngine *risk.Engine,
+	idempotencyTTL time.Duration,
 	logger zerolog.Logger,
 ) TransactionService {
 	return &TransactionServiceImpl{
 		userRepo:        userRepo,
 		transactionRepo: transactionRepo,
+		limitRepo:       limitRepo,
+		idempotencyRepo: idempotencyRepo,
 		dbManager:       dbManager,
+		amountLimits:    amountLimits,
+		tenants:         tenants,
+		debt:            debt,
+		bonus:           bonus,
+		riskEngine:      riskEngine,
+		idempotencyTTL:  idempotencyTTL,
 		logger:          logger,
 	}
 }
 
 func (s *TransactionServiceImpl) ProcessTransaction(ctx context.Context, req *model.TransactionRequest, sourceType model.SourceType, userID int64) (*model.TransactionResponse, error) {
-	var result *model.TransactionResponse
+	var (
+		result *model.TransactionResponse
+		// blocked is returned once the blocked transaction is committed
+		blocked error
+	)
 
 	// Validate inputs early, before transaction and locks
 	amount, err := decimal.NewFromString(req.Amount)
@@ -54,6 +95,18 @@ func (s *TransactionServiceImpl) ProcessTransaction(ctx context.Context, req *mo
 		return nil, fmt.Errorf("%w: %v", model.ErrInvalidState, err)
 	}
 
+	if err := s.tenants.CheckTransaction(ctx, sourceType, req.ProviderID); err != nil {
+		return nil, err
+	}
+	if err := auth.CheckProvider(ctx, req.ProviderID); err != nil {
+		return nil, err
+	}
+
+	limits := s.tenants.AmountLimits(ctx, s.amountLimits)
+	if err := limits.Check(amount, sourceType, req.ProviderID); err != nil {
+		return nil, err
+	}
+
 	// Service manages transaction to keep operations to multiple repos atomic
 	err = s.dbManager.WithTransaction(ctx, func(tx pgx.Tx) error {
 		// Get transaction if exists and validate user_id
@@ -70,18 +123,20 @@ func (s *TransactionServiceImpl) ProcessTransaction(ctx context.Context, req *mo
 					model.ErrDuplicateTransaction, req.TransactionID, existingTrans.UserID, userID)
 			}
 
-			// Same transaction_id and same user - return existing result
-			balance, err := s.userRepo.GetBalance(ctx, userID, tx)
+			// Same transaction_id and same user - return existing result if the request matches
+			if err := s.checkReplay(existingTrans, sourceType, req.ProviderID, state, amount); err != nil {
+				return err
+			}
+			if existingTrans.Status == model.StatusBlocked {
+				return blockedError(existingTrans.RiskRules)
+			}
+
+			result, err = s.replayResponse(ctx, req.TransactionID, userID, tx)
 			if err != nil {
-				return fmt.Errorf("get balance: %w", err)
+				return err
 			}
 
 			s.logger.Info().Str("transaction_id", req.TransactionID).Int64("user_id", userID).Msg("transaction already processed")
-			result = &model.TransactionResponse{
-				Status:  "already_processed",
-				Balance: balance.StringFixed(2),
-				Message: "Transaction already processed",
-			}
 			return nil
 		}
 
@@ -90,35 +145,106 @@ func (s *TransactionServiceImpl) ProcessTransaction(ctx context.Context, req *mo
 		if err != nil {
 			return fmt.Errorf("get user for update: %w", err)
 		}
-
-		newBalance := user.Balance
-		switch state {
-		case model.StateWin:
-			newBalance = newBalance.Add(amount)
-		case model.StateLost:
-			newBalance = newBalance.Sub(amount)
-		}
-
-		// Negative balance is not allowed
-		if newBalance.LessThan(decimal.Zero) {
-			return model.ErrInsufficientBalance
-		}
-
-		err = s.userRepo.UpdateBalance(ctx, userID, newBalance, tx)
-		if err != nil {
-			return fmt.Errorf("update balance: %w", err)
+		if err := auth.CheckUser(ctx, user); err != nil {
+			return err
 		}
 
-		// Insert transaction
 		transaction := &model.Transaction{
 			TransactionID: req.TransactionID,
 			UserID:        userID,
 			SourceType:    sourceType,
+			ProviderID:    req.ProviderID,
 			State:         state,
 			Amount:        amount,
 			Status:        model.StatusProcessed,
 		}
 
+		// Fraud rules run before the balance update, while the user row is locked
+		decision, err := s.evaluateRisk(ctx, transaction, tx)
+		if err != nil {
+			return err
+		}
+		if decision.Action == risk.ActionBlock {
+			// The blocked transaction and its decision are committed so that they can be reviewed, the balance is untouched
+			transaction.Status = model.StatusBlocked
+			err = s.transactionRepo.InsertTransaction(ctx, transaction, tx)
+			if err != nil {
+				if errors.Is(err, model.ErrDuplicateTransaction) {
+					return errDuplicateInsertRace
+				}
+				return fmt.Errorf("insert transaction: %w", err)
+			}
+
+			err = recordEvent(ctx, s.transactionRepo, transaction, &model.TransactionEvent{
+				ActorType:     model.ActorProvider,
+				Actor:         providerActor(req.ProviderID, sourceType),
+				Reason:        model.EventReasonRiskBlocked,
+				Note:          strings.Join(decision.Rules, ", "),
+				BalanceBefore: &user.Balance,
+				BalanceAfter:  &user.Balance,
+			}, tx)
+			if err != nil {
+				return err
+			}
+
+			s.logger.Warn().Str("transaction_id", req.TransactionID).Int64("user_id", userID).
+				Strs("rules", decision.Rules).
+				Msg("transaction blocked by risk rules")
+			blocked = blockedError(decision.Rules)
+			return nil
+		}
+
+		// Large or suspicious transactions are stored for manual review without touching the balance
+		switch {
+		case limits.RequiresReview(state, amount, sourceType):
+			transaction.ReviewReason = model.ReviewReasonMaxAutoWin
+		case decision.Action == risk.ActionFlag:
+			transaction.ReviewReason = model.ReviewReasonRiskRules
+		}
+
+		if transaction.ReviewReason != "" {
+			transaction.Status = model.StatusPendingReview
+
+			err = s.transactionRepo.InsertTransaction(ctx, transaction, tx)
+			if err != nil {
+				if errors.Is(err, model.ErrDuplicateTransaction) {
+					return errDuplicateInsertRace
+				}
+				return fmt.Errorf("insert transaction: %w", err)
+			}
+
+			err = recordEvent(ctx, s.transactionRepo, transaction, &model.TransactionEvent{
+				ActorType:     model.ActorProvider,
+				Actor:         providerActor(req.ProviderID, sourceType),
+				Reason:        transaction.ReviewReason,
+				BalanceBefore: &user.Balance,
+				BalanceAfter:  &user.Balance,
+			}, tx)
+			if err != nil {
+				return err
+			}
+
+			s.logger.Info().Str("transaction_id", req.TransactionID).Int64("user_id", userID).
+				Str("amount", amount.String()).
+				Str("source_type", sourceType.String()).
+				Str("review_reason", transaction.ReviewReason).
+				Msg("transaction held for manual review")
+
+			result = &model.TransactionResponse{
+				Status:  "pending_review",
+				Balance: user.Balance.StringFixed(2),
+				Message: "Transaction accepted and pending review",
+			}
+			return s.saveResponse(ctx, req.TransactionID, result, tx)
+		}
+
+		change, err := applyTransaction(ctx, s.userRepo, s.limitRepo, user, state, amount, s.debt.Enabled(sourceType), s.bonus, tx)
+		if err != nil {
+			return err
+		}
+		transaction.BonusAmount = change.BonusAmount
+
+		// Insert transaction
 		err = s.transactionRepo.InsertTransaction(ctx, transaction, tx)
 		if err != nil {
 			if errors.Is(err, model.ErrDuplicateTransaction) {
@@ -128,23 +254,40 @@ func (s *TransactionServiceImpl) ProcessTransaction(ctx context.Context, req *mo
 			return fmt.Errorf("insert transaction: %w", err)
 		}
 
+		err = recordEvent(ctx, s.transactionRepo, transaction, withWallets(&model.TransactionEvent{
+			ActorType:     model.ActorProvider,
+			Actor:         providerActor(req.ProviderID, sourceType),
+			Reason:        model.EventReasonProcessed,
+			BalanceBefore: &user.Balance,
+			BalanceAfter:  &change.Balance,
+		}, user, change.Debt, change.Bonus), tx)
+		if err != nil {
+			return err
+		}
+
 		s.logger.Info().Str("transaction_id", req.TransactionID).Int64("user_id", userID).Str("state", state.String()).
 			Str("amount", amount.String()).
-			Str("new_balance", newBalance.StringFixed(2)).
+			Str("new_balance", change.Balance.StringFixed(2)).
 			Msg("transaction processed successfully")
 
 		result = &model.TransactionResponse{
 			Status:  "success",
-			Balance: newBalance.StringFixed(2),
+			Balance: change.Balance.StringFixed(2),
 			Message: "Transaction processed successfully",
 		}
 
-		return nil
+		return s.saveResponse(ctx, req.TransactionID, result, tx)
 	})
 
 	// Handle duplicate transaction, check if created for same user or not
 	if errors.Is(err, errDuplicateInsertRace) {
+		// The competing insert just committed, a lagging replica may not have it yet
+		ctx := repository.WithReadYourWrites(ctx)
 		existing, getErr := s.transactionRepo.GetTransaction(ctx, req.TransactionID)
+		if errors.Is(getErr, model.ErrTransactionNotFound) {
+			// The transaction_id is taken by another tenant
+			return nil, fmt.Errorf("%w: transaction %s already exists", model.ErrDuplicateTransaction, req.TransactionID)
+		}
 		if getErr != nil {
 			return nil, fmt.Errorf("get transaction after duplicate: %w", getErr)
 		}
@@ -154,9 +297,16 @@ func (s *TransactionServiceImpl) ProcessTransaction(ctx context.Context, req *mo
 				model.ErrDuplicateTransaction, req.TransactionID, existing.UserID, userID)
 		}
 
-		balance, balErr := s.userRepo.GetBalance(ctx, userID)
-		if balErr != nil {
-			return nil, fmt.Errorf("get balance after duplicate: %w", balErr)
+		if err := s.checkReplay(existing, sourceType, req.ProviderID, state, amount); err != nil {
+			return nil, err
+		}
+		if existing.Status == model.StatusBlocked {
+			return nil, blockedError(existing.RiskRules)
+		}
+
+		replayed, replayErr := s.replayResponse(ctx, req.TransactionID, userID)
+		if replayErr != nil {
+			return nil, replayErr
 		}
 
 		s.logger.Info().
@@ -164,33 +314,306 @@ func (s *TransactionServiceImpl) ProcessTransaction(ctx context.Context, req *mo
 			Int64("user_id", userID).
 			Msg("transaction already processed (detected after rollback)")
 
-		return &model.TransactionResponse{
-			Status:  "already_processed",
-			Balance: balance.StringFixed(2),
-			Message: "Transaction already processed",
-		}, nil
+		return replayed, nil
 	}
 
 	if err != nil {
 		return nil, err
 	}
+	if blocked != nil {
+		return nil, blocked
+	}
 
 	return result, nil
 }
 
+// blockedError is returned for a transaction blocked by the fraud rules, also when it is retried
+func blockedError(rules []string) error {
+	return fmt.Errorf("%w: %s", model.ErrTransactionBlocked, strings.Join(rules, ", "))
+}
+
+// saveResponse stores the response of a new transaction so retries get the balance of the original call.
+// The record has no fingerprint, retries are compared with the transaction row itself, see checkReplay.
+func (s *TransactionServiceImpl) saveResponse(ctx context.Context, transactionID string, resp *model.TransactionResponse, tx pgx.Tx) error {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("marshal response: %w", err)
+	}
+
+	err = s.idempotencyRepo.SaveIdempotencyRecord(ctx, &model.IdempotencyRecord{
+		Scope:     transactionIdempotencyScope,
+		Key:       transactionID,
+		Response:  body,
+		ExpiresAt: time.Now().UTC().Add(s.idempotencyTTL),
+	}, tx)
+	if err != nil {
+		return fmt.Errorf("save idempotency record: %w", err)
+	}
+	return nil
+}
+
+// checkReplay compares a resent transaction with the stored one. Differences usually mean a provider bug,
+// so they are counted and logged for escalation.
+func (s *TransactionServiceImpl) checkReplay(existing *model.Transaction, sourceType model.SourceType, providerID string, state model.State, amount decimal.Decimal) error {
+	var mismatches []model.FieldMismatch
+	if !existing.Amount.Equal(amount) {
+		mismatches = append(mismatches, model.FieldMismatch{Field: "amount", Stored: existing.Amount.String(), Received: amount.String()})
+	}
+	if existing.State != state {
+		mismatches = append(mismatches, model.FieldMismatch{Field: "state", Stored: existing.State.String(), Received: state.String()})
+	}
+	if existing.SourceType != sourceType {
+		mismatches = append(mismatches, model.FieldMismatch{Field: "source_type", Stored: existing.SourceType.String(), Received: sourceType.String()})
+	}
+	if existing.ProviderID != providerID {
+		mismatches = append(mismatches, model.FieldMismatch{Field: "provider_id", Stored: existing.ProviderID, Received: providerID})
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	metrics.TransactionMismatchesTotal.WithLabelValues(sourceType.String()).Inc()
+	s.logger.Warn().
+		Str("transaction_id", existing.TransactionID).
+		Int64("user_id", existing.UserID).
+		Str("provider_id", providerID).
+		Interface("mismatches", mismatches).
+		Msg("transaction resent with different fields")
+
+	return &model.TransactionMismatchError{TransactionID: existing.TransactionID, Mismatches: mismatches}
+}
+
+// replayResponse builds the already_processed response for a retried transaction_id from the stored response.
+// Transactions whose record expired (or predate stored responses) get the current balance.
+func (s *TransactionServiceImpl) replayResponse(ctx context.Context, transactionID string, userID int64, tx ...pgx.Tx) (*model.TransactionResponse, error) {
+	resp := &model.TransactionResponse{
+		Status:  "already_processed",
+		Message: "Transaction already processed",
+	}
+
+	rec, err := s.idempotencyRepo.GetIdempotencyRecord(ctx, transactionIdempotencyScope, transactionID, tx...)
+	switch {
+	case err == nil:
+		var original model.TransactionResponse
+		if err := json.Unmarshal(rec.Response, &original); err != nil {
+			return nil, fmt.Errorf("unmarshal stored response: %w", err)
+		}
+		resp.Balance = original.Balance
+	case errors.Is(err, model.ErrIdempotencyNotFound):
+		balance, err := s.userRepo.GetBalance(ctx, userID, tx...)
+		if err != nil {
+			return nil, fmt.Errorf("get balance: %w", err)
+		}
+		resp.Balance = balance.StringFixed(2)
+	default:
+		return nil, fmt.Errorf("get idempotency record: %w", err)
+	}
+
+	return resp, nil
+}
+
+// evaluateRisk runs the fraud rules against the transaction and the user's history and records the decision on it
+func (s *TransactionServiceImpl) evaluateRisk(ctx context.Context, trans *model.Transaction, tx pgx.Tx) (risk.Decision, error) {
+	if s.riskEngine.Empty() {
+		return risk.Decision{Action: risk.ActionAllow}, nil
+	}
+
+	now := time.Now().UTC()
+	recent, err := s.transactionRepo.GetRecentTransactionsByUser(ctx, trans.UserID, now.Add(-s.riskEngine.Lookback()), tx)
+	if err != nil {
+		return risk.Decision{}, fmt.Errorf("get recent transactions: %w", err)
+	}
+
+	averageWin, winCount, err := s.transactionRepo.GetWinStats(ctx, trans.UserID, riskWinStatsWindow, tx)
+	if err != nil {
+		return risk.Decision{}, fmt.Errorf("get win stats: %w", err)
+	}
+
+	decision := s.riskEngine.Evaluate(&risk.Input{
+		UserID:     trans.UserID,
+		SourceType: trans.SourceType,
+		State:      trans.State,
+		Amount:     trans.Amount,
+		Now:        now,
+		Recent:     recent,
+		AverageWin: averageWin,
+		WinCount:   winCount,
+	})
+
+	trans.RiskDecision = string(decision.Action)
+	trans.RiskRules = decision.Rules
+	return decision, nil
+}
+
+// walletChange is the effect of a transaction on the balances of a user
+type walletChange struct {
+	Balance decimal.Decimal
+	Debt    decimal.Decimal
+	Bonus   decimal.Decimal
+	// BonusAmount is the part of the amount taken from or credited to the bonus balance
+	BonusAmount decimal.Decimal
+}
+
+// applyTransaction applies a win/lost amount to the balances of a user locked with GetUserForUpdate,
+// enforcing loss limits and keeping the net loss counters in sync.
+// Lost amounts are taken from the real and bonus balances in the spend order of the bonus policy and count towards
+// the wagering requirement, the bonus becomes real money once it is met. While a bonus is active wins are credited
+// to both balances in proportion. With recoverDebt the real part of a win repays the user's debt first.
+func applyTransaction(
+	ctx context.Context,
+	userRepo repository.UserRepository,
+	limitRepo repository.LimitRepository,
+	user *model.User,
+	state model.State,
+	amount decimal.Decimal,
+	recoverDebt bool,
+	bonus *BonusPolicy,
+	tx pgx.Tx,
+) (*walletChange, error) {
+	if user.MutationsPaused {
+		return nil, fmt.Errorf("%w %d", model.ErrMutationsPaused, user.ID)
+	}
+
+	now := time.Now().UTC()
+
+	// Responsible-gaming loss limits, checked against the aggregated counters
+	if state == model.StateLost {
+		if err := checkLossLimits(ctx, limitRepo, user.ID, amount, now, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	change := &walletChange{Balance: user.Balance, Debt: user.Debt, Bonus: user.BonusBalance}
+	wagering := user.BonusWagering
+	expiresAt := user.BonusExpiresAt
+	switch state {
+	case model.StateWin:
+		change.BonusAmount = bonusWinShare(user, amount)
+		credited := amount.Sub(change.BonusAmount)
+		if recoverDebt && change.Debt.IsPositive() {
+			recovered := decimal.Min(change.Debt, credited)
+			change.Debt = change.Debt.Sub(recovered)
+			credited = credited.Sub(recovered)
+		}
+		change.Balance = change.Balance.Add(credited)
+		change.Bonus = change.Bonus.Add(change.BonusAmount)
+	case model.StateLost:
+		// Negative balance is not allowed
+		if user.Balance.Add(user.BonusBalance).LessThan(amount) {
+			return nil, model.ErrInsufficientBalance
+		}
+		fromReal, fromBonus := bonus.spend(user, amount)
+		change.Balance = change.Balance.Sub(fromReal)
+		change.Bonus = change.Bonus.Sub(fromBonus)
+		change.BonusAmount = fromBonus
+
+		if wagering.IsPositive() {
+			wagering = decimal.Max(wagering.Sub(amount), decimal.Zero)
+			if wagering.IsZero() {
+				change.Balance = change.Balance.Add(change.Bonus)
+				change.Bonus = decimal.Zero
+				expiresAt = nil
+			}
+		}
+	}
+
+	if err := userRepo.UpdateBalance(ctx, user.ID, change.Balance, tx); err != nil {
+		return nil, fmt.Errorf("update balance: %w", err)
+	}
+
+	if !change.Debt.Equal(user.Debt) {
+		if err := userRepo.UpdateDebt(ctx, user.ID, change.Debt, tx); err != nil {
+			return nil, fmt.Errorf("update debt: %w", err)
+		}
+	}
+
+	if !change.Bonus.Equal(user.BonusBalance) || !wagering.Equal(user.BonusWagering) || expiresAt != user.BonusExpiresAt {
+		if err := userRepo.UpdateBonus(ctx, user.ID, change.Bonus, wagering, expiresAt, tx); err != nil {
+			return nil, fmt.Errorf("update bonus: %w", err)
+		}
+	}
+
+	if err := limitRepo.AddNetLoss(ctx, user.ID, netLossDelta(state, amount), now, tx); err != nil {
+		return nil, fmt.Errorf("update loss counters: %w", err)
+	}
+
+	return change, nil
+}
+
+// recordEvent stores a status change in the audit trail, in the database transaction making it.
+// The event gets the transaction's IDs, its current status and the request ID of the context.
+func recordEvent(ctx context.Context, transactionRepo repository.TransactionRepository, trans *model.Transaction,
+	event *model.TransactionEvent, tx pgx.Tx) error {
+	event.TransactionID = trans.TransactionID
+	event.UserID = trans.UserID
+	event.ToStatus = trans.Status
+	event.RequestID = repository.RequestID(ctx)
+
+	if err := transactionRepo.InsertTransactionEvent(ctx, event, tx); err != nil {
+		return fmt.Errorf("record transaction event: %w", err)
+	}
+	return nil
+}
+
+// withWallets sets the debts and bonus balances of an event when the change moved them
+func withWallets(event *model.TransactionEvent, user *model.User, debt, bonus decimal.Decimal) *model.TransactionEvent {
+	if !user.Debt.Equal(debt) {
+		event.DebtBefore = &user.Debt
+		event.DebtAfter = &debt
+	}
+	if !user.BonusBalance.Equal(bonus) {
+		event.BonusBefore = &user.BonusBalance
+		event.BonusAfter = &bonus
+	}
+	return event
+}
+
+// providerActor names the provider of a transaction, the source type when the provider did not identify itself
+func providerActor(providerID string, sourceType model.SourceType) string {
+	if providerID != "" {
+		return providerID
+	}
+	return sourceType.String()
+}
+
+// checkUserAccess returns ErrProviderNotAllowed if the caller's token is restricted to a provider the user does not
+// belong to, the user is only read for such callers
+func checkUserAccess(ctx context.Context, userRepo repository.UserRepository, userID int64) error {
+	if auth.ProviderOf(ctx) == "" {
+		return nil
+	}
+	user, err := userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	return auth.CheckUser(ctx, user)
+}
+
 func (s *TransactionServiceImpl) GetBalance(ctx context.Context, userID int64) (*model.BalanceResponse, error) {
-	balance, err := s.userRepo.GetBalance(ctx, userID)
+	user, err := s.userRepo.GetUser(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("get balance: %w", err)
 	}
+	if err := auth.CheckUser(ctx, user); err != nil {
+		return nil, err
+	}
 
 	return &model.BalanceResponse{
-		UserID:  userID,
-		Balance: balance.StringFixed(2),
+		UserID:   userID,
+		Balance:  user.Balance.StringFixed(2),
+		Bonus:    user.BonusBalance.StringFixed(2),
+		Total:    user.Balance.Add(user.BonusBalance).StringFixed(2),
+		Debt:     user.Debt.StringFixed(2),
+		Reserved: user.Reserved.StringFixed(2),
 	}, nil
 }
 
 func (s *TransactionServiceImpl) GetTransactionsByUser(ctx context.Context, userID int64, limit, offset int) ([]*model.Transaction, error) {
+	if err := checkUserAccess(ctx, s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
 	transactions, err := s.transactionRepo.GetTransactionsByUser(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("get user transactions: %w", err)
@@ -198,3 +621,38 @@ func (s *TransactionServiceImpl) GetTransactionsByUser(ctx context.Context, user
 
 	return transactions, nil
 }
+
+func (s *TransactionServiceImpl) SetUserProvider(ctx context.Context, userID int64, providerID, operator string) error {
+	if err := s.tenants.CheckProvider(ctx, providerID); err != nil {
+		return err
+	}
+	if err := s.userRepo.SetProvider(ctx, userID, providerID); err != nil {
+		return fmt.Errorf("set provider: %w", err)
+	}
+
+	s.logger.Info().Int64("user_id", userID).Str("provider_id", providerID).Str("operator", operator).Msg("user bound to provider")
+	return nil
+}
+
+func (s *TransactionServiceImpl) GetTransaction(ctx context.Context, transactionID string) (*model.Transaction, error) {
+	transaction, err := s.transactionRepo.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("get transaction: %w", err)
+	}
+
+	return transaction, nil
+}
+
+func (s *TransactionServiceImpl) GetTransactionEvents(ctx context.Context, transactionID string) ([]*model.TransactionEvent, error) {
+	// Unknown transactions are reported as such, transactions from before the audit trail have no events
+	if _, err := s.transactionRepo.GetTransaction(ctx, transactionID); err != nil {
+		return nil, fmt.Errorf("get transaction: %w", err)
+	}
+
+	events, err := s.transactionRepo.GetTransactionEvents(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("get transaction events: %w", err)
+	}
+
+	return events, nil
+}
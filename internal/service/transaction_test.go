@@ -2,8 +2,14 @@ package service
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
+	"transaction-processor/internal/auth"
+	"transaction-processor/internal/config"
 	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+	"transaction-processor/internal/risk"
 	"transaction-processor/mocks/repository"
 
 	"github.com/jackc/pgx/v5"
@@ -20,6 +26,7 @@ func TestProcessTransaction_Win_HappyPath(t *testing.T) {
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
 	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
@@ -30,14 +37,20 @@ func TestProcessTransaction_Win_HappyPath(t *testing.T) {
 		Version: 1,
 	}, nil)
 	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimal.RequireFromString("110.50"), mock.Anything).Return(nil)
+	mockLimitRepo.On("AddNetLoss", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.RequireFromString("-10.50"))
+	}), mock.Anything, mock.Anything).Return(nil)
 	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(trans *model.Transaction) bool {
 		return trans.TransactionID == "550e8400-e29b-41d4-a716-446655440000" &&
 			trans.UserID == 1 &&
 			trans.Amount.Equal(decimal.RequireFromString("10.50")) &&
 			trans.State == "win"
 	}), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.ToStatus == model.StatusProcessed && e.ActorType == model.ActorProvider && e.BalanceAfter.Equal(decimal.RequireFromString("110.50"))
+	}), mock.Anything).Return(nil)
 
-	service := NewTransactionService(mockUserRepo, mockTransRepo, mockDBManager, logger)
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
 
 	req := &model.TransactionRequest{
 		State:         "win",
@@ -53,12 +66,55 @@ func TestProcessTransaction_Win_HappyPath(t *testing.T) {
 	assert.Equal(t, "Transaction processed successfully", resp.Message)
 }
 
+func TestProcessTransaction_Win_RepaysDebt(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "tx-1", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:      1,
+		Balance: decimal.Zero,
+		Debt:    decimal.NewFromInt(30),
+	}, nil)
+	// 30 of the 50 win repay the debt, the rest reaches the balance
+	mockUserRepo.On("UpdateBalance", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(20))
+	}), mock.Anything).Return(nil)
+	mockUserRepo.On("UpdateDebt", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.IsZero()
+	}), mock.Anything).Return(nil)
+	mockLimitRepo.On("AddNetLoss", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(-50))
+	}), mock.Anything, mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.DebtBefore.Equal(decimal.NewFromInt(30)) && e.DebtAfter.IsZero()
+	}), mock.Anything).Return(nil)
+
+	debt, err := NewDebtPolicy(config.DebtConfig{SourceTypes: []string{"game"}})
+	require.NoError(t, err)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, debt, nil, nil, time.Hour, logger)
+
+	resp, err := service.ProcessTransaction(ctx, &model.TransactionRequest{State: "win", Amount: "50", TransactionID: "tx-1"}, "game", 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "20.00", resp.Balance)
+}
+
 func TestProcessTransaction_Lost_HappyPath(t *testing.T) {
 	ctx := context.Background()
 	logger := zerolog.Nop()
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
 	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
@@ -70,15 +126,22 @@ func TestProcessTransaction_Lost_HappyPath(t *testing.T) {
 		Balance: decimal.NewFromInt(100),
 		Version: 1,
 	}, nil)
+	mockLimitRepo.On("GetLossLimits", ctx, int64(1), mock.Anything).Return([]*model.LossLimit{}, nil)
 	mockUserRepo.On("UpdateBalance", ctx, int64(1), decimal.RequireFromString("89.50"), mock.Anything).Return(nil)
+	mockLimitRepo.On("AddNetLoss", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.RequireFromString("10.50"))
+	}), mock.Anything, mock.Anything).Return(nil)
 	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(trans *model.Transaction) bool {
 		return trans.TransactionID == "550e8400-e29b-41d4-a716-446655440001" &&
 			trans.UserID == 1 &&
 			trans.Amount.Equal(decimal.RequireFromString("10.50")) &&
 			trans.State == "lost"
 	}), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.ToStatus == model.StatusProcessed && e.Reason == model.EventReasonProcessed
+	}), mock.Anything).Return(nil)
 
-	service := NewTransactionService(mockUserRepo, mockTransRepo, mockDBManager, logger)
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
 
 	req := &model.TransactionRequest{
 		State:         "lost",
@@ -93,12 +156,58 @@ func TestProcessTransaction_Lost_HappyPath(t *testing.T) {
 	assert.Equal(t, "89.50", resp.Balance)
 }
 
+func TestProcessTransaction_Lost_SpendsBonusAndReleasesIt(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	expiresAt := time.Now().Add(time.Hour)
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "tx-1", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:             1,
+		Balance:        decimal.NewFromInt(20),
+		BonusBalance:   decimal.NewFromInt(50),
+		BonusWagering:  decimal.NewFromInt(30),
+		BonusExpiresAt: &expiresAt,
+	}, nil)
+	mockLimitRepo.On("GetLossLimits", ctx, int64(1), mock.Anything).Return([]*model.LossLimit{}, nil)
+	// 20 real and 10 bonus are spent, the stake meets the wagering requirement and the 40 bonus left is released
+	mockUserRepo.On("UpdateBalance", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.Equal(decimal.NewFromInt(40))
+	}), mock.Anything).Return(nil)
+	mockUserRepo.On("UpdateBonus", ctx, int64(1), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.IsZero()
+	}), mock.MatchedBy(func(d decimal.Decimal) bool {
+		return d.IsZero()
+	}), (*time.Time)(nil), mock.Anything).Return(nil)
+	mockLimitRepo.On("AddNetLoss", ctx, int64(1), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(trans *model.Transaction) bool {
+		return trans.BonusAmount.Equal(decimal.NewFromInt(10))
+	}), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.BonusBefore.Equal(decimal.NewFromInt(50)) && e.BonusAfter.IsZero()
+	}), mock.Anything).Return(nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	resp, err := service.ProcessTransaction(ctx, &model.TransactionRequest{State: "lost", Amount: "30", TransactionID: "tx-1"}, "game", 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "40.00", resp.Balance)
+}
+
 func TestProcessTransaction_DuplicateTransaction_SameUser(t *testing.T) {
 	ctx := context.Background()
 	logger := zerolog.Nop()
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
 	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
@@ -108,13 +217,14 @@ func TestProcessTransaction_DuplicateTransaction_SameUser(t *testing.T) {
 		ID:            1,
 		TransactionID: "550e8400-e29b-41d4-a716-446655440002",
 		UserID:        1,
+		SourceType:    model.SourceGame,
 		State:         "win",
 		Amount:        decimal.NewFromFloat(10.50),
 		Status:        "processed",
 	}, nil)
 	mockUserRepo.On("GetBalance", ctx, int64(1), mock.Anything).Return(decimal.NewFromInt(150), nil)
 
-	service := NewTransactionService(mockUserRepo, mockTransRepo, mockDBManager, logger)
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
 
 	req := &model.TransactionRequest{
 		State:         "win",
@@ -135,6 +245,7 @@ func TestProcessTransaction_DuplicateTransaction_DifferentUser(t *testing.T) {
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
 	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error {
@@ -148,7 +259,7 @@ func TestProcessTransaction_DuplicateTransaction_DifferentUser(t *testing.T) {
 		Amount:        decimal.NewFromFloat(10.50),
 	}, nil)
 
-	service := NewTransactionService(mockUserRepo, mockTransRepo, mockDBManager, logger)
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
 
 	req := &model.TransactionRequest{
 		State:         "win",
@@ -170,6 +281,7 @@ func TestProcessTransaction_InsufficientBalance(t *testing.T) {
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
 	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
@@ -179,8 +291,9 @@ func TestProcessTransaction_InsufficientBalance(t *testing.T) {
 		Balance: decimal.NewFromInt(5),
 		Version: 1,
 	}, nil)
+	mockLimitRepo.On("GetLossLimits", ctx, int64(1), mock.Anything).Return([]*model.LossLimit{}, nil)
 
-	service := NewTransactionService(mockUserRepo, mockTransRepo, mockDBManager, logger)
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
 
 	req := &model.TransactionRequest{
 		State:         "lost",
@@ -201,9 +314,10 @@ func TestProcessTransaction_InvalidAmount_Zero(t *testing.T) {
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
-	service := NewTransactionService(mockUserRepo, mockTransRepo, mockDBManager, logger)
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
 
 	req := &model.TransactionRequest{
 		State:         "win",
@@ -219,15 +333,149 @@ func TestProcessTransaction_InvalidAmount_Zero(t *testing.T) {
 	assert.Contains(t, err.Error(), "amount must be positive")
 }
 
+func TestProcessTransaction_SourceTypeNotAllowedForTenant(t *testing.T) {
+	ctx := repository.WithTenant(context.Background(), "brand-a")
+	logger := zerolog.Nop()
+
+	tenants, err := ParseTenants([]byte("tenants:\n  - id: brand-a\n    api_keys_sha256: [" + keyHash("key-a") + "]\n    source_types: [game]\n"))
+	require.NoError(t, err)
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, tenants, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "10",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440006",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "server", 1)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrSourceTypeNotAllowed)
+}
+
+func TestProcessTransaction_ProviderNotAllowedForToken(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "acme-games", ProviderID: "acme"})
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "10",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440007",
+		ProviderID:    "other",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrProviderNotAllowed)
+}
+
+func TestProcessTransaction_UserOfOtherProvider(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "acme-games", ProviderID: "acme"})
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440008", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:         1,
+		ProviderID: "other",
+		Balance:    decimal.NewFromInt(100),
+	}, nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "10",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440008",
+		ProviderID:    "acme",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrProviderNotAllowed)
+	mockUserRepo.AssertNotCalled(t, "BindProvider")
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestProcessTransaction_UnboundUser(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "acme-games", ProviderID: "acme"})
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440009", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:      1,
+		Balance: decimal.NewFromInt(100),
+	}, nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "10",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440009",
+		ProviderID:    "acme",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	// The first provider to post must not take the user over, operators bind it
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrProviderNotAllowed)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestSetUserProvider(t *testing.T) {
+	ctx := context.Background()
+
+	tenants, err := ParseTenants([]byte("tenants:\n  - id: brand-a\n    api_keys_sha256: [" + strings.Repeat("ab", 32) + "]\n    providers: [acme]\n  - id: brand-b\n    api_keys_sha256: [" + strings.Repeat("cd", 32) + "]\n    providers: [beta]\n"))
+	require.NoError(t, err)
+	inBrandA := repository.WithTenant(ctx, "brand-a")
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockUserRepo.On("SetProvider", inBrandA, int64(1), "acme").Return(nil).Once()
+
+	service := NewTransactionService(mockUserRepo, nil, nil, nil, nil, nil, tenants, nil, nil, nil, time.Hour, zerolog.Nop())
+
+	assert.NoError(t, service.SetUserProvider(inBrandA, 1, "acme", "ops"))
+	assert.ErrorIs(t, service.SetUserProvider(inBrandA, 1, "beta", "ops"), model.ErrProviderNotAllowed)
+}
+
 func TestProcessTransaction_InvalidAmount_Negative(t *testing.T) {
 	ctx := context.Background()
 	logger := zerolog.Nop()
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
-	service := NewTransactionService(mockUserRepo, mockTransRepo, mockDBManager, logger)
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
 
 	req := &model.TransactionRequest{
 		State:         "win",
@@ -248,13 +496,14 @@ func TestProcessTransaction_UserNotFound(t *testing.T) {
 
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
 	mockDBManager := mocks.NewDBManager(t)
 
 	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
 	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440008", mock.Anything).Return(nil, model.ErrTransactionNotFound)
 	mockUserRepo.On("GetUserForUpdate", ctx, int64(999), mock.Anything).Return(nil, model.ErrUserNotFound)
 
-	service := NewTransactionService(mockUserRepo, mockTransRepo, mockDBManager, logger)
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
 
 	req := &model.TransactionRequest{
 		State:         "win",
@@ -268,3 +517,331 @@ func TestProcessTransaction_UserNotFound(t *testing.T) {
 	assert.Nil(t, resp)
 	assert.ErrorIs(t, err, model.ErrUserNotFound)
 }
+
+func TestProcessTransaction_LossLimitExceeded(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	now := time.Now().UTC()
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440009", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:      1,
+		Balance: decimal.NewFromInt(100),
+		Version: 1,
+	}, nil)
+	mockLimitRepo.On("GetLossLimits", ctx, int64(1), mock.Anything).Return([]*model.LossLimit{
+		{UserID: 1, Period: model.PeriodDaily, Amount: decimal.NewFromInt(50)},
+	}, nil)
+	mockLimitRepo.On("GetLossCounters", ctx, int64(1), mock.Anything).Return([]*model.LossCounter{
+		{UserID: 1, Period: model.PeriodDaily, WindowStart: model.PeriodDaily.WindowStart(now), NetLoss: decimal.NewFromInt(45)},
+	}, nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "lost",
+		Amount:        "10.00",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440009",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrLossLimitExceeded)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestProcessTransaction_LargeWin_PendingReview(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440010", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:      1,
+		Balance: decimal.NewFromInt(100),
+		Version: 1,
+	}, nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(trans *model.Transaction) bool {
+		return trans.Status == model.StatusPendingReview && trans.SourceType == model.SourcePayment
+	}), mock.Anything).Return(nil)
+
+	amountLimits, err := NewAmountLimits(config.LimitsConfig{
+		MaxAutoWin: map[string]decimal.Decimal{"payment": decimal.NewFromInt(1000)},
+	})
+	require.NoError(t, err)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.ToStatus == model.StatusPendingReview && e.Reason == model.ReviewReasonMaxAutoWin
+	}), mock.Anything).Return(nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, amountLimits, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "5000.00",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440010",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "payment", 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "pending_review", resp.Status)
+	assert.Equal(t, "100.00", resp.Balance)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+	mockLimitRepo.AssertNotCalled(t, "AddNetLoss")
+}
+
+func TestProcessTransaction_BlockedByRiskRules(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440011", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{ID: 1, Balance: decimal.NewFromInt(100)}, nil)
+	mockTransRepo.On("GetRecentTransactionsByUser", ctx, int64(1), mock.Anything, mock.Anything).Return([]*model.Transaction{
+		{State: model.StateWin, SourceType: model.SourceGame, CreatedAt: time.Now().UTC()},
+	}, nil)
+	mockTransRepo.On("GetWinStats", ctx, int64(1), riskWinStatsWindow, mock.Anything).Return(decimal.NewFromInt(10), 1, nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(trans *model.Transaction) bool {
+		return trans.Status == model.StatusBlocked && trans.RiskDecision == "block" &&
+			len(trans.RiskRules) == 1 && trans.RiskRules[0] == "rapid_wins"
+	}), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.ToStatus == model.StatusBlocked && e.Reason == model.EventReasonRiskBlocked && e.Note == "rapid_wins"
+	}), mock.Anything).Return(nil)
+
+	engine := risk.NewEngine(risk.NewWinVelocityRule("rapid_wins", risk.ActionBlock, 1, time.Minute, true))
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, engine, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "10.00",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440011",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrTransactionBlocked)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestProcessTransaction_BlockedRetryIsBlocked(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440013", mock.Anything).Return(&model.Transaction{
+		TransactionID: "550e8400-e29b-41d4-a716-446655440013",
+		UserID:        1,
+		SourceType:    model.SourceGame,
+		State:         model.StateWin,
+		Amount:        decimal.NewFromInt(10),
+		Status:        model.StatusBlocked,
+		RiskRules:     []string{"rapid_wins"},
+	}, nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "10.00",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440013",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrTransactionBlocked)
+}
+
+func TestProcessTransaction_FlaggedByRiskRules_PendingReview(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440012", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{ID: 1, Balance: decimal.NewFromInt(100)}, nil)
+	mockTransRepo.On("GetRecentTransactionsByUser", ctx, int64(1), mock.Anything, mock.Anything).Return([]*model.Transaction{}, nil)
+	mockTransRepo.On("GetWinStats", ctx, int64(1), riskWinStatsWindow, mock.Anything).Return(decimal.NewFromInt(10), 20, nil)
+	mockTransRepo.On("InsertTransaction", ctx, mock.MatchedBy(func(trans *model.Transaction) bool {
+		return trans.Status == model.StatusPendingReview &&
+			trans.ReviewReason == model.ReviewReasonRiskRules &&
+			trans.RiskDecision == "flag" &&
+			len(trans.RiskRules) == 1 && trans.RiskRules[0] == "outsized_win"
+	}), mock.Anything).Return(nil)
+	mockTransRepo.On("InsertTransactionEvent", ctx, mock.MatchedBy(func(e *model.TransactionEvent) bool {
+		return e.ToStatus == model.StatusPendingReview && e.Reason == model.ReviewReasonRiskRules
+	}), mock.Anything).Return(nil)
+
+	engine := risk.NewEngine(risk.NewWinAmountMultipleRule("outsized_win", risk.ActionFlag, decimal.NewFromInt(20), 5))
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, engine, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "500.00",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440012",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "pending_review", resp.Status)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestProcessTransaction_MutationsPaused(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440030", mock.Anything).Return(nil, model.ErrTransactionNotFound)
+	mockUserRepo.On("GetUserForUpdate", ctx, int64(1), mock.Anything).Return(&model.User{
+		ID:              1,
+		Balance:         decimal.NewFromInt(100),
+		Version:         1,
+		MutationsPaused: true,
+	}, nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, newIdempotencyRepo(t), mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "10.00",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440030",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrMutationsPaused)
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance")
+	mockTransRepo.AssertNotCalled(t, "InsertTransaction")
+}
+
+func TestProcessTransaction_DuplicateTransaction_ReplaysStoredBalance(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockIdempotencyRepo := mocks.NewIdempotencyRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440031", mock.Anything).Return(&model.Transaction{
+		ID:            1,
+		TransactionID: "550e8400-e29b-41d4-a716-446655440031",
+		UserID:        1,
+		SourceType:    model.SourceGame,
+		State:         "win",
+		Amount:        decimal.RequireFromString("10.50"),
+		Status:        "processed",
+	}, nil)
+	mockIdempotencyRepo.On("GetIdempotencyRecord", ctx, "transaction", "550e8400-e29b-41d4-a716-446655440031", mock.Anything).Return(&model.IdempotencyRecord{
+		Response: []byte(`{"status":"success","balance":"110.50","message":"Transaction processed successfully"}`),
+	}, nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, mockIdempotencyRepo, mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "win",
+		Amount:        "10.50",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440031",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "game", 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "already_processed", resp.Status)
+	assert.Equal(t, "110.50", resp.Balance)
+	mockUserRepo.AssertNotCalled(t, "GetBalance")
+}
+
+func TestProcessTransaction_DuplicateTransaction_Mismatch(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockTransRepo := mocks.NewTransactionRepository(t)
+	mockLimitRepo := mocks.NewLimitRepository(t)
+	mockIdempotencyRepo := mocks.NewIdempotencyRepository(t)
+	mockDBManager := mocks.NewDBManager(t)
+
+	mockDBManager.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(pgx.Tx) error) error { return fn(nil) })
+	mockTransRepo.On("GetTransaction", ctx, "550e8400-e29b-41d4-a716-446655440032", mock.Anything).Return(&model.Transaction{
+		ID:            1,
+		TransactionID: "550e8400-e29b-41d4-a716-446655440032",
+		UserID:        1,
+		SourceType:    model.SourceGame,
+		State:         "win",
+		Amount:        decimal.RequireFromString("10.50"),
+		Status:        "processed",
+	}, nil)
+
+	service := NewTransactionService(mockUserRepo, mockTransRepo, mockLimitRepo, mockIdempotencyRepo, mockDBManager, nil, nil, nil, nil, nil, time.Hour, logger)
+
+	req := &model.TransactionRequest{
+		State:         "lost",
+		Amount:        "10.5",
+		TransactionID: "550e8400-e29b-41d4-a716-446655440032",
+	}
+
+	resp, err := service.ProcessTransaction(ctx, req, "server", 1)
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrTransactionMismatch)
+
+	var mismatch *model.TransactionMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, []model.FieldMismatch{
+		{Field: "state", Stored: "win", Received: "lost"},
+		{Field: "source_type", Stored: "game", Received: "server"},
+	}, mismatch.Mismatches)
+	mockIdempotencyRepo.AssertNotCalled(t, "GetIdempotencyRecord")
+}
+
+// newIdempotencyRepo returns a repository without stored responses, for tests that do not replay transactions
+func newIdempotencyRepo(t *testing.T) *mocks.IdempotencyRepository {
+	repo := mocks.NewIdempotencyRepository(t)
+	repo.On("SaveIdempotencyRecord", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("GetIdempotencyRecord", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, model.ErrIdempotencyNotFound).Maybe()
+	return repo
+}
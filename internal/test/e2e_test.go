@@ -10,16 +10,19 @@ import (
 	"os"
 	"sync"
 	"testing"
+	"time"
 	"transaction-processor/internal/config"
 	"transaction-processor/internal/database"
 	"transaction-processor/internal/handler"
 	"transaction-processor/internal/model"
+	"transaction-processor/internal/payment"
 	"transaction-processor/internal/repository/postgres"
 	"transaction-processor/internal/service"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -42,14 +45,14 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
-	pool, err := database.NewPool(ctx, cfg.Database)
+	pools, err := database.NewPool(ctx, cfg.Database)
 	if err != nil {
 		fmt.Printf("failed to connect to database: %v\n", err)
 		os.Exit(1)
 	}
-	defer pool.Close()
+	defer pools.Close()
 
-	testPool = pool
+	testPool = pools.Primary
 	os.Exit(m.Run())
 }
 
@@ -61,6 +64,14 @@ func setupE2E(t *testing.T) *handler.Handler {
 	ctx := context.Background()
 	_, err := testPool.Exec(ctx, "DELETE FROM transactions WHERE user_id = $1", testUserID)
 	require.NoError(t, err)
+	_, err = testPool.Exec(ctx, "DELETE FROM transaction_index WHERE user_id = $1", testUserID)
+	require.NoError(t, err)
+	_, err = testPool.Exec(ctx, "DELETE FROM user_loss_limits WHERE user_id = $1", testUserID)
+	require.NoError(t, err)
+	_, err = testPool.Exec(ctx, "DELETE FROM user_loss_counters WHERE user_id = $1", testUserID)
+	require.NoError(t, err)
+	_, err = testPool.Exec(ctx, "DELETE FROM payments WHERE user_id = $1", testUserID)
+	require.NoError(t, err)
 
 	// Seed test user, update balance and version if already exists
 	_, err = testPool.Exec(ctx, `
@@ -68,19 +79,35 @@ func setupE2E(t *testing.T) *handler.Handler {
 		VALUES ($1, 100.00, 0)
 		ON CONFLICT (id) DO UPDATE
 		SET balance = EXCLUDED.balance,
+			opening_balance = EXCLUDED.opening_balance,
 			version = EXCLUDED.version,
+			debt = 0,
+			bonus_balance = 0,
+			bonus_wagering = 0,
+			bonus_expires_at = NULL,
+			reserved = 0,
 			updated_at = NOW()
 	`, testUserID)
 	require.NoError(t, err)
 
 	logger := zerolog.Nop()
-	userRepo := postgres.NewUserRepository(testPool)
-	transRepo := postgres.NewTransactionRepository(testPool)
+	userRepo := postgres.NewUserRepository(testPool, nil)
+	transRepo := postgres.NewTransactionRepository(testPool, nil)
+	limitRepo := postgres.NewLimitRepository(testPool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(testPool)
 	dbManager := postgres.NewTransactionManager(testPool)
 
-	txService := service.NewTransactionService(userRepo, transRepo, dbManager, logger)
+	txService := service.NewTransactionService(userRepo, transRepo, limitRepo, idempotencyRepo, dbManager, nil, nil, nil, nil, nil, time.Hour, logger)
+	limitService := service.NewLimitService(userRepo, limitRepo, dbManager, time.Hour, logger)
+	reviewService := service.NewReviewService(userRepo, transRepo, limitRepo, dbManager, nil, nil, logger)
 
-	return handler.NewHandler(txService, logger)
+	return handler.NewHandler(handler.Deps{
+		Transactions: txService,
+		Limits:       limitService,
+		Reviews:      reviewService,
+		Idempotency:  service.NewIdempotencyService(idempotencyRepo, time.Hour, logger),
+		Logger:       logger,
+	})
 }
 
 // Test_ConcurrentRequests_SameTransactionID_DuplicateAndBalanceCorrect verifies:
@@ -374,3 +401,44 @@ func Test_BasicTransactionFlow(t *testing.T) {
 		assert.Equal(t, "INSUFFICIENT_BALANCE", errResp.Code)
 	})
 }
+
+// Test_CompletedWithdrawal_NeverSwept verifies that settled payments are out of reach of the odd-record
+// cancellation, which would otherwise put paid-out withdrawals back on the balance
+func Test_CompletedWithdrawal_NeverSwept(t *testing.T) {
+	setupE2E(t)
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	userRepo := postgres.NewUserRepository(testPool, nil)
+	transRepo := postgres.NewTransactionRepository(testPool, nil)
+	dbManager := postgres.NewTransactionManager(testPool)
+	paymentService := service.NewPaymentService(userRepo, transRepo, postgres.NewPaymentRepository(testPool), dbManager, nil, nil, payment.NewStubProvider("secret"), logger)
+	cancelService := service.NewCancellationService(userRepo, transRepo, postgres.NewLimitRepository(testPool), nil, dbManager, model.ShortfallSkip, nil, nil, logger)
+
+	// Two settlements in a row, so one of them has an odd id
+	settled := map[string]bool{}
+	for range 2 {
+		p, err := paymentService.RequestWithdrawal(ctx, testUserID, decimal.NewFromInt(10))
+		require.NoError(t, err)
+		p, err = paymentService.ApprovePayment(ctx, p.ID, "ops")
+		require.NoError(t, err)
+		_, err = paymentService.HandleCallback(ctx, p.ProviderReference, model.PaymentCompleted, "")
+		require.NoError(t, err)
+		settled[p.TransactionID] = true
+	}
+
+	odd, err := transRepo.GetLatestOddProcessedTransactions(ctx, 1000)
+	require.NoError(t, err)
+	for _, trans := range odd {
+		assert.False(t, settled[trans.TransactionID], "settlement %s is swept", trans.TransactionID)
+	}
+
+	for transactionID := range settled {
+		_, err := cancelService.CancelTransaction(ctx, transactionID, "ops", model.CancelReasonOther, "")
+		assert.ErrorIs(t, err, model.ErrNotCancellable)
+	}
+
+	balance, err := userRepo.GetBalance(ctx, testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, "80.00", balance.StringFixed(2))
+}
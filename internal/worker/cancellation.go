@@ -2,60 +2,213 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
 	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/repository"
+	"transaction-processor/internal/scheduler"
 	"transaction-processor/internal/service"
 
 	"github.com/rs/zerolog"
 )
 
+// Ensure implementation satisfies interface at compile time
+var _ service.CancellationWorkerControl = (*CancellationWorker)(nil)
+
+// statusRuns is how many of the latest runs Status looks at to find the last finished one
+const statusRuns = 5
+
+// CancellationWorker controls the scheduled odd-record cancellation job for the admin API
 type CancellationWorker struct {
-	service  service.CancellationService
-	interval time.Duration
-	logger   zerolog.Logger
-	stopChan chan struct{}
-	wg       *sync.WaitGroup
+	service   service.CancellationService
+	scheduler *scheduler.Scheduler
+	runRepo   repository.JobRunRepository
+	logger    zerolog.Logger
+
+	mu sync.Mutex
+	// configured is the schedule from the config, applied is the one last set on the scheduler
+	configured scheduler.Schedule
+	applied    string
 }
 
-func NewCancellationWorker(svc service.CancellationService, interval time.Duration, logger zerolog.Logger) *CancellationWorker {
+func NewCancellationWorker(svc service.CancellationService, sched *scheduler.Scheduler, runRepo repository.JobRunRepository, logger zerolog.Logger) *CancellationWorker {
 	return &CancellationWorker{
-		service:  svc,
-		interval: interval,
-		logger:   logger,
-		stopChan: make(chan struct{}),
-		wg:       &sync.WaitGroup{},
-	}
-}
-
-func (w *CancellationWorker) Start(ctx context.Context) {
-	w.wg.Add(1)
-	go func() {
-		defer w.wg.Done()
-		ticker := time.NewTicker(w.interval)
-		defer ticker.Stop()
-
-		w.logger.Info().Dur("interval", w.interval).Msg("Cancellation worker started")
-
-		for {
-			select {
-			case <-ticker.C:
-				w.logger.Debug().Msg("Running cancellation task")
-				err := w.service.ProcessOddRecordCancellation(ctx)
-				if err != nil {
-					w.logger.Error().Err(err).Msg("Failed to run cancellation task")
-				}
-			case <-w.stopChan:
-				w.logger.Info().Msg("Cancellation worker stopping")
-				return
-			case <-ctx.Done():
-				w.logger.Info().Msg("Cancellation worker stopping (context done)")
-				return
+		service:   svc,
+		scheduler: sched,
+		runRepo:   runRepo,
+		logger:    logger,
+	}
+}
+
+// Job cancels odd-numbered processed transactions unless operators paused it.
+// The schedule is the configured one, an interval set through the admin API replaces it once synced.
+func (w *CancellationWorker) Job(schedule scheduler.Schedule) scheduler.Job {
+	w.mu.Lock()
+	w.configured = schedule
+	w.applied = schedule.String()
+	w.mu.Unlock()
+
+	return scheduler.Job{
+		Name:     CancellationJob,
+		Schedule: schedule,
+		Run: func(ctx context.Context) (any, error) {
+			result, err := w.service.ProcessOddRecordCancellation(ctx)
+			if err != nil {
+				return nil, err
 			}
+			return result, nil
+		},
+		Paused: func(ctx context.Context) (bool, error) {
+			state, err := w.service.GetWorkerState(ctx)
+			if err != nil {
+				return false, err
+			}
+			return state.Paused, nil
+		},
+	}
+}
+
+// Run applies the interval stored in worker_state every period until the context is done,
+// so that the leader picks up an interval set through another instance
+func (w *CancellationWorker) Run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Sync(ctx); err != nil && ctx.Err() == nil {
+			w.logger.Error().Err(err).Str("job", CancellationJob).Msg("Failed to sync job schedule")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
 		}
-	}()
+	}
 }
 
-func (w *CancellationWorker) Stop() {
-	close(w.stopChan)
-	w.wg.Wait()
+// Sync sets the scheduler to the interval stored in worker_state, or to the configured schedule without one
+func (w *CancellationWorker) Sync(ctx context.Context) error {
+	state, err := w.service.GetWorkerState(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	schedule := w.schedule(state)
+	if schedule.String() == w.applied {
+		return nil
+	}
+	if err := w.scheduler.SetSchedule(CancellationJob, schedule); err != nil {
+		return err
+	}
+	w.applied = schedule.String()
+	return nil
+}
+
+// Configure replaces the configured schedule on config reload, a stored interval still takes precedence
+func (w *CancellationWorker) Configure(ctx context.Context, schedule scheduler.Schedule) error {
+	w.mu.Lock()
+	w.configured = schedule
+	w.mu.Unlock()
+
+	return w.Sync(ctx)
+}
+
+// schedule returns the schedule runs follow, guarded by mu
+func (w *CancellationWorker) schedule(state *model.WorkerState) scheduler.Schedule {
+	if state.Interval > 0 {
+		return scheduler.Every(state.Interval)
+	}
+	return w.configured
+}
+
+// Status returns the pause state and schedule shared by all instances, and the latest run of any instance
+func (w *CancellationWorker) Status(ctx context.Context) (*model.WorkerStatus, error) {
+	state, err := w.service.GetWorkerState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	job, err := w.scheduler.Status(CancellationJob)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := w.runRepo.GetJobRuns(ctx, CancellationJob, statusRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	interval := w.schedule(state).String()
+	w.mu.Unlock()
+
+	status := &model.WorkerStatus{
+		Name:     CancellationJob,
+		Paused:   state.Paused,
+		Interval: interval,
+		Leader:   w.scheduler.IsLeader(),
+		// A run left running by an instance that stopped is reported until the next run starts
+		Running: job.Running || (len(runs) > 0 && runs[0].Status == model.JobRunRunning),
+	}
+	if state.Paused {
+		status.PausedBy = state.UpdatedBy
+	}
+	if run := lastFinished(runs); run != nil {
+		status.LastRunAt = &run.StartedAt
+		status.LastRunDuration = run.FinishedAt.Sub(run.StartedAt).String()
+		if raw, ok := run.Result.(json.RawMessage); ok {
+			var result model.CancellationResult
+			if err := json.Unmarshal(raw, &result); err == nil {
+				status.LastResult = &result
+			}
+		}
+		status.LastError = run.Error
+	}
+	// The next run is only known to the leader, and only while its schedule matches the stored one
+	if !job.NextRunAt.IsZero() && !state.Paused && status.Leader && job.Schedule == interval {
+		nextRunAt := job.NextRunAt.UTC()
+		status.NextRunAt = &nextRunAt
+	}
+	return status, nil
+}
+
+// lastFinished returns the newest run that finished, runs are newest first
+func lastFinished(runs []*model.JobRun) *model.JobRun {
+	for _, run := range runs {
+		if run.FinishedAt != nil {
+			return run
+		}
+	}
+	return nil
+}
+
+// Pause stops automatic runs on all instances until resumed, a run in progress completes
+func (w *CancellationWorker) Pause(ctx context.Context, operator string) error {
+	return w.service.SetWorkerPaused(ctx, true, operator)
+}
+
+// Resume restarts automatic runs
+func (w *CancellationWorker) Resume(ctx context.Context, operator string) error {
+	return w.service.SetWorkerPaused(ctx, false, operator)
+}
+
+// Trigger runs a cancellation now on this instance, over every tenant like scheduled runs
+func (w *CancellationWorker) Trigger(ctx context.Context) (*model.WorkerStatus, error) {
+	if _, err := w.scheduler.Trigger(repository.WithTenant(ctx, ""), CancellationJob); err != nil {
+		return nil, err
+	}
+	return w.Status(ctx)
+}
+
+// SetInterval stores the interval between automatic runs for all instances and applies it here, the leader picks it
+// up within its sync period. The next run is one interval from when the leader applies it, zero restores the
+// configured schedule.
+func (w *CancellationWorker) SetInterval(ctx context.Context, interval time.Duration, operator string) error {
+	if err := w.service.SetWorkerInterval(ctx, interval, operator); err != nil {
+		return err
+	}
+	return w.Sync(ctx)
 }
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+	"transaction-processor/internal/model"
+	"transaction-processor/internal/scheduler"
+	repomocks "transaction-processor/mocks/repository"
+	"transaction-processor/mocks/service"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newCancellationWorker(t *testing.T) (*CancellationWorker, *scheduler.Scheduler, *mocks.CancellationService, *repomocks.JobRunRepository) {
+	svc := mocks.NewCancellationService(t)
+	runRepo := repomocks.NewJobRunRepository(t)
+	sched := scheduler.NewScheduler(runRepo, nil, 0, time.Minute, 0, zerolog.Nop())
+	w := NewCancellationWorker(svc, sched, runRepo, zerolog.Nop())
+	require.NoError(t, sched.Register(w.Job(scheduler.Every(2*time.Minute))))
+	return w, sched, svc, runRepo
+}
+
+func TestCancellationWorker_SyncAppliesStoredInterval(t *testing.T) {
+	ctx := context.Background()
+	w, sched, svc, _ := newCancellationWorker(t)
+
+	// Set through another instance
+	svc.On("GetWorkerState", ctx).Return(&model.WorkerState{Name: CancellationJob, Interval: 30 * time.Second}, nil).Once()
+	require.NoError(t, w.Sync(ctx))
+
+	status, err := sched.Status(CancellationJob)
+	require.NoError(t, err)
+	assert.Equal(t, "30s", status.Schedule)
+
+	// Reset, the configured schedule applies again
+	svc.On("GetWorkerState", ctx).Return(&model.WorkerState{Name: CancellationJob}, nil).Once()
+	require.NoError(t, w.Sync(ctx))
+
+	status, err = sched.Status(CancellationJob)
+	require.NoError(t, err)
+	assert.Equal(t, "2m0s", status.Schedule)
+}
+
+func TestCancellationWorker_StatusFromJobRuns(t *testing.T) {
+	ctx := context.Background()
+	w, _, svc, runRepo := newCancellationWorker(t)
+
+	startedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	finishedAt := startedAt.Add(3 * time.Second)
+	result, _ := json.Marshal(&model.CancellationResult{Requested: 4, Cancelled: 3})
+
+	svc.On("GetWorkerState", ctx).Return(&model.WorkerState{Name: CancellationJob, Interval: 30 * time.Second}, nil)
+	// The newest run is in progress on the leader, the one before finished there
+	runRepo.On("GetJobRuns", ctx, CancellationJob, mock.Anything).Return([]*model.JobRun{
+		{ID: 2, Job: CancellationJob, Instance: "leader", Status: model.JobRunRunning, StartedAt: finishedAt.Add(time.Minute)},
+		{ID: 1, Job: CancellationJob, Instance: "leader", Status: model.JobRunFailed, StartedAt: startedAt, FinishedAt: &finishedAt,
+			Error: "partial failure", Result: json.RawMessage(result)},
+	}, nil)
+
+	status, err := w.Status(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, "30s", status.Interval)
+	assert.True(t, status.Running)
+	require.NotNil(t, status.LastRunAt)
+	assert.Equal(t, startedAt, *status.LastRunAt)
+	assert.Equal(t, "3s", status.LastRunDuration)
+	assert.Equal(t, "partial failure", status.LastError)
+	require.NotNil(t, status.LastResult)
+	assert.Equal(t, 3, status.LastResult.Cancelled)
+}
@@ -0,0 +1,65 @@
+// Package worker defines the background jobs run by the scheduler
+package worker
+
+import (
+	"context"
+	"errors"
+	"transaction-processor/internal/scheduler"
+	"transaction-processor/internal/service"
+)
+
+// Job names, also used in job_runs
+const (
+	CancellationJob       = "cancellation"
+	IntegrityJob          = "integrity"
+	PartitionJob          = "partition"
+	IdempotencyCleanupJob = "idempotency_cleanup"
+	BonusExpiryJob        = "bonus_expiry"
+)
+
+// NewIntegrityJob verifies balances against transaction history
+func NewIntegrityJob(svc service.IntegrityService, schedule scheduler.Schedule) scheduler.Job {
+	return scheduler.Job{
+		Name:     IntegrityJob,
+		Schedule: schedule,
+		Run: func(ctx context.Context) (any, error) {
+			return svc.RunCheck(ctx)
+		},
+	}
+}
+
+// NewPartitionJob creates upcoming transaction partitions and archives expired ones
+func NewPartitionJob(svc service.PartitionService, schedule scheduler.Schedule) scheduler.Job {
+	return scheduler.Job{
+		Name:     PartitionJob,
+		Schedule: schedule,
+		Run: func(ctx context.Context) (any, error) {
+			// Archival does not depend on the new partitions, run it even if creating them failed
+			ensureErr := svc.EnsurePartitions(ctx)
+			archived, archiveErr := svc.ArchivePartitions(ctx)
+			return archived, errors.Join(ensureErr, archiveErr)
+		},
+	}
+}
+
+// NewIdempotencyCleanupJob deletes expired idempotency keys
+func NewIdempotencyCleanupJob(svc service.IdempotencyService, schedule scheduler.Schedule) scheduler.Job {
+	return scheduler.Job{
+		Name:     IdempotencyCleanupJob,
+		Schedule: schedule,
+		Run: func(ctx context.Context) (any, error) {
+			return nil, svc.DeleteExpired(ctx)
+		},
+	}
+}
+
+// NewBonusExpiryJob removes expired bonuses
+func NewBonusExpiryJob(svc service.BonusService, schedule scheduler.Schedule) scheduler.Job {
+	return scheduler.Job{
+		Name:     BonusExpiryJob,
+		Schedule: schedule,
+		Run: func(ctx context.Context) (any, error) {
+			return svc.ExpireBonuses(ctx)
+		},
+	}
+}
@@ -0,0 +1,23 @@
+-- Responsible-gaming loss limits, one row per user and period
+CREATE TABLE IF NOT EXISTS user_loss_limits (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period VARCHAR(10) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,
+    pending_amount NUMERIC(20, 2),
+    pending_effective_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, period),
+    CONSTRAINT loss_limit_non_negative CHECK (amount >= 0 AND (pending_amount IS NULL OR pending_amount >= 0))
+);
+
+-- Net loss (lost minus win) of the current window per user and period.
+-- Maintained in the same transaction as the balance update so limit checks never scan transactions.
+CREATE TABLE IF NOT EXISTS user_loss_counters (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period VARCHAR(10) NOT NULL,
+    window_start TIMESTAMP NOT NULL,
+    net_loss NUMERIC(20, 2) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, period)
+);
@@ -0,0 +1,5 @@
+-- Provider that submitted the transaction, used for per-provider amount limits
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS provider_id VARCHAR(100);
+
+-- Large wins waiting for manual review are not applied to the balance
+CREATE INDEX IF NOT EXISTS idx_transactions_status_pending_review ON transactions(created_at) WHERE status = 'pending_review';
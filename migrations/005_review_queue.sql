@@ -0,0 +1,6 @@
+-- Manual review of transactions held back by risk rules
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS review_reason VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS review_note TEXT;
@@ -0,0 +1,7 @@
+-- Outcome of the fraud rules engine for each stored transaction
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS risk_decision VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS risk_rules TEXT[];
+
+-- Velocity rules read a user's most recent transactions
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id_created_at ON transactions(user_id, created_at DESC);
@@ -0,0 +1,40 @@
+-- Balance a user started with, so the current balance can be recomputed from transaction history.
+-- Existing users get the balance minus their processed transactions, new users the balance they are created with.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS opening_balance NUMERIC(20, 2);
+
+UPDATE users u
+SET opening_balance = u.balance - COALESCE((
+    SELECT SUM(CASE WHEN t.state = 'win' THEN t.amount ELSE -t.amount END)
+    FROM transactions t
+    WHERE t.user_id = u.id AND t.status = 'processed'
+), 0)
+WHERE u.opening_balance IS NULL;
+
+ALTER TABLE users ALTER COLUMN opening_balance SET DEFAULT 0;
+ALTER TABLE users ALTER COLUMN opening_balance SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION users_set_opening_balance() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.opening_balance := NEW.balance;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_users_opening_balance ON users;
+CREATE TRIGGER trg_users_opening_balance
+    BEFORE INSERT ON users
+    FOR EACH ROW EXECUTE FUNCTION users_set_opening_balance();
+
+-- Manual credits/debits by operators, each one is also recorded as an 'adjustment' transaction
+CREATE TABLE IF NOT EXISTS balance_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    amount NUMERIC(20, 2) NOT NULL,
+    reason TEXT NOT NULL,
+    created_by VARCHAR(100) NOT NULL,
+    transaction_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT adjustment_non_zero CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_adjustments_user_id ON balance_adjustments(user_id);
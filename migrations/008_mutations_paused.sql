@@ -0,0 +1,4 @@
+-- Users whose balance drifted from their transaction history can be frozen until an operator investigates
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mutations_paused BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_users_mutations_paused ON users(id) WHERE mutations_paused;
@@ -0,0 +1,18 @@
+-- Maker-checker workflow for balance adjustments: created as pending, applied when a second operator approves.
+-- Adjustments made before this migration were applied directly and are marked approved.
+ALTER TABLE balance_adjustments ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'approved';
+ALTER TABLE balance_adjustments ALTER COLUMN status SET DEFAULT 'pending';
+
+ALTER TABLE balance_adjustments ADD COLUMN IF NOT EXISTS decided_by VARCHAR(100);
+ALTER TABLE balance_adjustments ADD COLUMN IF NOT EXISTS decided_at TIMESTAMP;
+ALTER TABLE balance_adjustments ADD COLUMN IF NOT EXISTS decision_note TEXT;
+
+ALTER TABLE balance_adjustments DROP CONSTRAINT IF EXISTS adjustment_status_valid;
+ALTER TABLE balance_adjustments ADD CONSTRAINT adjustment_status_valid
+    CHECK (status IN ('pending', 'approved', 'rejected'));
+
+ALTER TABLE balance_adjustments DROP CONSTRAINT IF EXISTS adjustment_four_eyes;
+ALTER TABLE balance_adjustments ADD CONSTRAINT adjustment_four_eyes
+    CHECK (decided_by IS NULL OR decided_by <> created_by);
+
+CREATE INDEX IF NOT EXISTS idx_balance_adjustments_pending ON balance_adjustments(created_at) WHERE status = 'pending';
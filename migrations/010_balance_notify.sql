@@ -0,0 +1,19 @@
+-- Publish every balance change on the balance_changed channel, delivered to listeners on commit.
+-- Covers all mutation paths (transactions, cancellations, reviews, adjustments) across API replicas.
+CREATE OR REPLACE FUNCTION users_notify_balance() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('balance_changed', json_build_object(
+        'user_id', NEW.id,
+        'balance', NEW.balance,
+        'version', NEW.version
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_users_notify_balance ON users;
+CREATE TRIGGER trg_users_notify_balance
+    AFTER UPDATE OF balance ON users
+    FOR EACH ROW
+    WHEN (OLD.balance IS DISTINCT FROM NEW.balance)
+    EXECUTE FUNCTION users_notify_balance();
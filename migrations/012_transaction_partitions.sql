@@ -0,0 +1,88 @@
+-- Monthly range partitioning of transactions on created_at.
+-- A partitioned table cannot enforce a unique transaction_id across partitions, so uniqueness moves to
+-- transaction_index, which also points to the partition (created_at) and, once archived, the archive file.
+CREATE TABLE IF NOT EXISTS transaction_index (
+    transaction_id UUID PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    archive_path TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_index_created_at ON transaction_index(created_at);
+
+-- Partitions managed by the app: created ahead of time, detached and exported once older than the retention
+CREATE TABLE IF NOT EXISTS transaction_partitions (
+    name VARCHAR(63) PRIMARY KEY,
+    range_start TIMESTAMP,
+    range_end TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    archived_at TIMESTAMP,
+    archive_path TEXT,
+    row_count BIGINT
+);
+
+-- Net of processed transactions moved to archives, so balances can still be recomputed from history
+ALTER TABLE users ADD COLUMN IF NOT EXISTS archived_net NUMERIC(20, 2) NOT NULL DEFAULT 0;
+
+-- Convert the existing table once: it becomes the transactions_legacy partition holding everything
+-- up to the end of the current month, later months get their own partitions.
+DO $$
+DECLARE
+    legacy_end TIMESTAMP := date_trunc('month', NOW()) + INTERVAL '1 month';
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = 'transactions'::regclass) THEN
+        RETURN;
+    END IF;
+
+    ALTER TABLE transactions RENAME TO transactions_legacy;
+    ALTER TABLE transactions_legacy RENAME CONSTRAINT transactions_pkey TO transactions_legacy_pkey;
+    ALTER TABLE transactions_legacy DROP CONSTRAINT IF EXISTS transactions_transaction_id_key;
+    ALTER INDEX IF EXISTS idx_transactions_user_id RENAME TO transactions_legacy_user_id_idx;
+    ALTER INDEX IF EXISTS idx_transactions_created_at RENAME TO transactions_legacy_created_at_idx;
+    ALTER INDEX IF EXISTS idx_transactions_status_processed RENAME TO transactions_legacy_status_processed_idx;
+    ALTER INDEX IF EXISTS idx_transactions_status_pending_review RENAME TO transactions_legacy_status_pending_review_idx;
+    ALTER INDEX IF EXISTS idx_transactions_user_id_created_at RENAME TO transactions_legacy_user_id_created_at_idx;
+
+    CREATE TABLE transactions (
+        id BIGINT NOT NULL DEFAULT nextval('transactions_id_seq'),
+        transaction_id UUID NOT NULL,
+        user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        source_type VARCHAR(50) NOT NULL,
+        state VARCHAR(10) NOT NULL,
+        amount NUMERIC(20, 2) NOT NULL,
+        status VARCHAR(20) NOT NULL DEFAULT 'processed',
+        cancelled_at TIMESTAMP,
+        created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+        updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+        provider_id VARCHAR(100),
+        review_reason VARCHAR(100),
+        reviewed_by VARCHAR(100),
+        reviewed_at TIMESTAMP,
+        review_note TEXT,
+        risk_decision VARCHAR(10),
+        risk_rules TEXT[],
+        PRIMARY KEY (id, created_at)
+    ) PARTITION BY RANGE (created_at);
+
+    ALTER SEQUENCE transactions_id_seq OWNED BY transactions.id;
+    ALTER TABLE transactions_legacy ALTER COLUMN id DROP DEFAULT;
+
+    -- Lets ATTACH skip the validation scan
+    EXECUTE format('ALTER TABLE transactions_legacy ADD CONSTRAINT transactions_legacy_range CHECK (created_at < %L)', legacy_end);
+    EXECUTE format('ALTER TABLE transactions ATTACH PARTITION transactions_legacy FOR VALUES FROM (MINVALUE) TO (%L)', legacy_end);
+    ALTER TABLE transactions_legacy DROP CONSTRAINT transactions_legacy_range;
+
+    INSERT INTO transaction_partitions (name, range_start, range_end)
+    VALUES ('transactions_legacy', NULL, legacy_end);
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_transaction_id ON transactions(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions(user_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_status_processed ON transactions(status) WHERE status = 'processed';
+CREATE INDEX IF NOT EXISTS idx_transactions_status_pending_review ON transactions(created_at) WHERE status = 'pending_review';
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id_created_at ON transactions(user_id, created_at DESC);
+
+INSERT INTO transaction_index (transaction_id, user_id, created_at)
+SELECT transaction_id, user_id, created_at FROM transactions
+ON CONFLICT (transaction_id) DO NOTHING;
@@ -0,0 +1,7 @@
+-- Runtime state of background workers set by operators, shared by all instances and kept across restarts
+CREATE TABLE IF NOT EXISTS worker_state (
+    name VARCHAR(50) PRIMARY KEY,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by VARCHAR(100),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
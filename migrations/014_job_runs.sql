@@ -0,0 +1,15 @@
+-- History of background job runs recorded by the scheduler, pruned after WORKER_JOB_RUN_RETENTION.
+-- Runs left in 'running' were interrupted by the instance stopping.
+CREATE TABLE IF NOT EXISTS job_runs (
+    id BIGSERIAL PRIMARY KEY,
+    job VARCHAR(50) NOT NULL,
+    trigger VARCHAR(10) NOT NULL,
+    instance VARCHAR(255) NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP,
+    error TEXT,
+    result JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started_at ON job_runs(job, started_at DESC);
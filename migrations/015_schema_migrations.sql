@@ -0,0 +1,10 @@
+-- Applied migrations, the readiness probe fails while the schema is older than the version the app needs.
+-- Every later migration ends by recording its own version.
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO schema_migrations (version)
+SELECT generate_series(1, 15)
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,22 @@
+-- Audit trail of transaction status changes, written in the database transaction making the change
+CREATE TABLE IF NOT EXISTS transaction_events (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id UUID NOT NULL,
+    user_id BIGINT NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,
+    actor VARCHAR(100),
+    reason VARCHAR(50) NOT NULL,
+    note TEXT,
+    request_id VARCHAR(100),
+    balance_before NUMERIC(20, 2),
+    balance_after NUMERIC(20, 2),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_events_transaction_id ON transaction_events(transaction_id, id);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cancel_reason VARCHAR(50);
+
+INSERT INTO schema_migrations (version) VALUES (16) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,10 @@
+-- Outcome of automatic cancellations the balance could not cover, see CANCELLATION_SHORTFALL_POLICY
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cancel_policy VARCHAR(20);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cancel_shortfall NUMERIC(20, 2);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_cancel_shortfall ON transactions(created_at) WHERE cancel_shortfall IS NOT NULL;
+
+-- The allow_negative policy reverses wins that were already spent
+ALTER TABLE users DROP CONSTRAINT IF EXISTS balance_non_negative;
+
+INSERT INTO schema_migrations (version) VALUES (17) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,16 @@
+-- Debt owed by users after cancellations their balance could not cover, repaid from later wins
+ALTER TABLE users ADD COLUMN IF NOT EXISTS debt NUMERIC(20, 2) NOT NULL DEFAULT 0;
+
+-- Negative balances left by the allow_negative cancellation policy become debt, which replaces it
+UPDATE users SET debt = debt - balance, balance = 0 WHERE balance < 0;
+UPDATE transactions SET cancel_policy = 'debt' WHERE cancel_policy = 'allow_negative';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS balance_non_negative;
+ALTER TABLE users ADD CONSTRAINT balance_non_negative CHECK (balance >= 0);
+ALTER TABLE users DROP CONSTRAINT IF EXISTS debt_non_negative;
+ALTER TABLE users ADD CONSTRAINT debt_non_negative CHECK (debt >= 0);
+
+ALTER TABLE transaction_events ADD COLUMN IF NOT EXISTS debt_before NUMERIC(20, 2);
+ALTER TABLE transaction_events ADD COLUMN IF NOT EXISTS debt_after NUMERIC(20, 2);
+
+INSERT INTO schema_migrations (version) VALUES (18) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,33 @@
+-- Bonus funds granted by promotions, they become real money once the wagering requirement is met
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bonus_balance NUMERIC(20, 2) NOT NULL DEFAULT 0;
+-- Amount still to be staked before the bonus is released
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bonus_wagering NUMERIC(20, 2) NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bonus_expires_at TIMESTAMP;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS bonus_balance_non_negative;
+ALTER TABLE users ADD CONSTRAINT bonus_balance_non_negative CHECK (bonus_balance >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_users_bonus_expires_at ON users(bonus_expires_at) WHERE bonus_expires_at IS NOT NULL;
+
+-- Part of a transaction's amount taken from or credited to the bonus balance
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS bonus_amount NUMERIC(20, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_events ADD COLUMN IF NOT EXISTS bonus_before NUMERIC(20, 2);
+ALTER TABLE transaction_events ADD COLUMN IF NOT EXISTS bonus_after NUMERIC(20, 2);
+
+-- Grants and expirations, the integrity check adds their net to the balance recomputed from transactions
+CREATE TABLE IF NOT EXISTS bonus_entries (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    kind VARCHAR(20) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,
+    wagering NUMERIC(20, 2) NOT NULL DEFAULT 0,
+    actor VARCHAR(100) NOT NULL,
+    note TEXT,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bonus_entries_user_id ON bonus_entries(user_id, id);
+
+INSERT INTO schema_migrations (version) VALUES (19) ON CONFLICT (version) DO NOTHING;
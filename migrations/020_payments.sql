@@ -0,0 +1,33 @@
+-- Funds of withdrawals in progress, moved out of the balance when requested and back into it if the withdrawal fails
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reserved NUMERIC(20, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS reserved_non_negative;
+ALTER TABLE users ADD CONSTRAINT reserved_non_negative CHECK (reserved >= 0);
+
+-- Deposits and withdrawals: pending until an operator approves and submits them to the payment provider,
+-- completed or failed by the provider's callback. Completed payments are recorded as 'payment' transactions.
+CREATE TABLE IF NOT EXISTS payments (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    kind VARCHAR(20) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    transaction_id UUID NOT NULL,
+    provider VARCHAR(100),
+    provider_reference VARCHAR(100),
+    decided_by VARCHAR(100),
+    failure_reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    CONSTRAINT payment_kind_valid CHECK (kind IN ('deposit', 'withdrawal')),
+    CONSTRAINT payment_status_valid CHECK (status IN ('pending', 'approved', 'completed', 'failed')),
+    CONSTRAINT payment_amount_positive CHECK (amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_user_id ON payments(user_id, id);
+CREATE INDEX IF NOT EXISTS idx_payments_status ON payments(status, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_provider_reference ON payments(provider, provider_reference)
+    WHERE provider_reference IS NOT NULL;
+
+INSERT INTO schema_migrations (version) VALUES (20) ON CONFLICT (version) DO NOTHING;
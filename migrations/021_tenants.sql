@@ -0,0 +1,14 @@
+-- Brands sharing the service. Every user belongs to one tenant, transactions copy it from their user so
+-- tenant-scoped queries need no join. Existing rows, and deployments without a tenants file, use 'default'.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(50) NOT NULL DEFAULT 'default';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(50) NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON users(tenant_id, id);
+CREATE INDEX IF NOT EXISTS idx_transactions_tenant_id_created_at ON transactions(tenant_id, created_at);
+
+-- Idempotency keys are chosen by the clients, so two tenants may use the same key
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(50) NOT NULL DEFAULT 'default';
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD CONSTRAINT idempotency_keys_pkey PRIMARY KEY (tenant_id, scope, key);
+
+INSERT INTO schema_migrations (version) VALUES (21) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,7 @@
+-- Completed deposits and withdrawals were recorded with the client-postable 'payment' source type, which the
+-- cancellation worker swept like game transactions. They get their own source type that is never cancelled.
+UPDATE transactions SET source_type = 'settlement'
+WHERE source_type = 'payment'
+  AND transaction_id IN (SELECT transaction_id FROM payments WHERE status = 'completed');
+
+INSERT INTO schema_migrations (version) VALUES (22) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,7 @@
+-- Approved payments are committed as 'submitting' before the provider is called, and 'approved' once its reference
+-- is recorded
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payment_status_valid;
+ALTER TABLE payments ADD CONSTRAINT payment_status_valid
+    CHECK (status IN ('pending', 'submitting', 'approved', 'completed', 'failed'));
+
+INSERT INTO schema_migrations (version) VALUES (23) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,16 @@
+-- The game provider a player account belongs to. Provider tokens may only post transactions, deposits and withdrawals
+-- for users of their own provider, a user is bound to the provider of the first transaction a provider token posts
+-- for it. Users whose transactions all come from one provider are bound to it.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS provider_id VARCHAR(100);
+
+UPDATE users u SET provider_id = t.provider_id
+FROM (
+    SELECT user_id, MIN(provider_id) AS provider_id
+    FROM transactions
+    WHERE provider_id IS NOT NULL AND provider_id <> '' AND source_type IN ('game', 'server', 'payment')
+    GROUP BY user_id
+    HAVING COUNT(DISTINCT provider_id) = 1
+) t
+WHERE u.id = t.user_id AND u.provider_id IS NULL;
+
+INSERT INTO schema_migrations (version) VALUES (24) ON CONFLICT (version) DO NOTHING;
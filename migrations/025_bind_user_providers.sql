@@ -0,0 +1,13 @@
+-- Provider tokens can no longer bind users by posting their first transaction. Users left unbound by migration 024,
+-- whose history has several providers, are bound to the provider of their most recent transaction, operators rebind
+-- them with PUT /admin/users/:id/provider. Users without transactions stay unbound until an operator binds them.
+UPDATE users u SET provider_id = t.provider_id
+FROM (
+    SELECT DISTINCT ON (user_id) user_id, provider_id
+    FROM transactions
+    WHERE provider_id IS NOT NULL AND provider_id <> '' AND source_type IN ('game', 'server', 'payment')
+    ORDER BY user_id, created_at DESC, id DESC
+) t
+WHERE u.id = t.user_id AND u.provider_id IS NULL;
+
+INSERT INTO schema_migrations (version) VALUES (25) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,5 @@
+-- Interval between automatic runs set through the admin API, applied by every instance in place of the configured
+-- schedule. NULL keeps the configured schedule.
+ALTER TABLE worker_state ADD COLUMN IF NOT EXISTS interval_ms BIGINT;
+
+INSERT INTO schema_migrations (version) VALUES (26) ON CONFLICT (version) DO NOTHING;
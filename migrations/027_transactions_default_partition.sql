@@ -0,0 +1,6 @@
+-- Catches transactions no monthly partition covers, so inserts keep working while the partition job is late or
+-- failing. Creating the missing partition moves the rows out of it. It is not recorded in transaction_partitions
+-- and never archived.
+CREATE TABLE IF NOT EXISTS transactions_default PARTITION OF transactions DEFAULT;
+
+INSERT INTO schema_migrations (version) VALUES (27) ON CONFLICT (version) DO NOTHING;
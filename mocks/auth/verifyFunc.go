@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	crypto "crypto"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// verifyFunc is an autogenerated mock type for the verifyFunc type
+type verifyFunc struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: key, signingInput, signature
+func (_m *verifyFunc) Execute(key crypto.PublicKey, signingInput []byte, signature []byte) error {
+	ret := _m.Called(key, signingInput, signature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(crypto.PublicKey, []byte, []byte) error); ok {
+		r0 = rf(key, signingInput, signature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// newVerifyFunc creates a new instance of verifyFunc. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newVerifyFunc(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *verifyFunc {
+	mock := &verifyFunc{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
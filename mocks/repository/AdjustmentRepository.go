@@ -0,0 +1,144 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// AdjustmentRepository is an autogenerated mock type for the AdjustmentRepository type
+type AdjustmentRepository struct {
+	mock.Mock
+}
+
+// CompleteAdjustment provides a mock function with given fields: ctx, adj, tx
+func (_m *AdjustmentRepository) CompleteAdjustment(ctx context.Context, adj *model.BalanceAdjustment, tx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, adj, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteAdjustment")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.BalanceAdjustment, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, adj, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.BalanceAdjustment, pgx.Tx) bool); ok {
+		r0 = rf(ctx, adj, tx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.BalanceAdjustment, pgx.Tx) error); ok {
+		r1 = rf(ctx, adj, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAdjustmentForUpdate provides a mock function with given fields: ctx, id, tx
+func (_m *AdjustmentRepository) GetAdjustmentForUpdate(ctx context.Context, id int64, tx pgx.Tx) (*model.BalanceAdjustment, error) {
+	ret := _m.Called(ctx, id, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAdjustmentForUpdate")
+	}
+
+	var r0 *model.BalanceAdjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, pgx.Tx) (*model.BalanceAdjustment, error)); ok {
+		return rf(ctx, id, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, pgx.Tx) *model.BalanceAdjustment); ok {
+		r0 = rf(ctx, id, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BalanceAdjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, pgx.Tx) error); ok {
+		r1 = rf(ctx, id, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAdjustments provides a mock function with given fields: ctx, status, limit, offset
+func (_m *AdjustmentRepository) GetAdjustments(ctx context.Context, status model.AdjustmentStatus, limit int, offset int) ([]*model.BalanceAdjustment, error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAdjustments")
+	}
+
+	var r0 []*model.BalanceAdjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.AdjustmentStatus, int, int) ([]*model.BalanceAdjustment, error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.AdjustmentStatus, int, int) []*model.BalanceAdjustment); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BalanceAdjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.AdjustmentStatus, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertAdjustment provides a mock function with given fields: ctx, adj, tx
+func (_m *AdjustmentRepository) InsertAdjustment(ctx context.Context, adj *model.BalanceAdjustment, tx ...pgx.Tx) error {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, adj)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertAdjustment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.BalanceAdjustment, ...pgx.Tx) error); ok {
+		r0 = rf(ctx, adj, tx...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAdjustmentRepository creates a new instance of AdjustmentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdjustmentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdjustmentRepository {
+	mock := &AdjustmentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
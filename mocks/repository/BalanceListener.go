@@ -0,0 +1,47 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BalanceListener is an autogenerated mock type for the BalanceListener type
+type BalanceListener struct {
+	mock.Mock
+}
+
+// Listen provides a mock function with given fields: ctx, ready, fn
+func (_m *BalanceListener) Listen(ctx context.Context, ready func(), fn func(*model.BalanceEvent)) error {
+	ret := _m.Called(ctx, ready, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Listen")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(), func(*model.BalanceEvent)) error); ok {
+		r0 = rf(ctx, ready, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBalanceListener creates a new instance of BalanceListener. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBalanceListener(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BalanceListener {
+	mock := &BalanceListener{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,111 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	time "time"
+)
+
+// BonusRepository is an autogenerated mock type for the BonusRepository type
+type BonusRepository struct {
+	mock.Mock
+}
+
+// ExpireBonuses provides a mock function with given fields: ctx, now, limit
+func (_m *BonusRepository) ExpireBonuses(ctx context.Context, now time.Time, limit int) ([]*model.BonusEntry, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireBonuses")
+	}
+
+	var r0 []*model.BonusEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*model.BonusEntry, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*model.BonusEntry); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BonusEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBonusEntries provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *BonusRepository) GetBonusEntries(ctx context.Context, userID int64, limit int, offset int) ([]*model.BonusEntry, error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBonusEntries")
+	}
+
+	var r0 []*model.BonusEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*model.BonusEntry, error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*model.BonusEntry); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BonusEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertBonusEntry provides a mock function with given fields: ctx, entry, tx
+func (_m *BonusRepository) InsertBonusEntry(ctx context.Context, entry *model.BonusEntry, tx pgx.Tx) error {
+	ret := _m.Called(ctx, entry, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertBonusEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.BonusEntry, pgx.Tx) error); ok {
+		r0 = rf(ctx, entry, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBonusRepository creates a new instance of BonusRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBonusRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BonusRepository {
+	mock := &BonusRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthRepository is an autogenerated mock type for the HealthRepository type
+type HealthRepository struct {
+	mock.Mock
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *HealthRepository) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PoolStats provides a mock function with no fields
+func (_m *HealthRepository) PoolStats() model.PoolStats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PoolStats")
+	}
+
+	var r0 model.PoolStats
+	if rf, ok := ret.Get(0).(func() model.PoolStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(model.PoolStats)
+	}
+
+	return r0
+}
+
+// SchemaVersion provides a mock function with given fields: ctx
+func (_m *HealthRepository) SchemaVersion(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SchemaVersion")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewHealthRepository creates a new instance of HealthRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthRepository {
+	mock := &HealthRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
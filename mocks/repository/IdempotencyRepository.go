@@ -0,0 +1,169 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	time "time"
+)
+
+// IdempotencyRepository is an autogenerated mock type for the IdempotencyRepository type
+type IdempotencyRepository struct {
+	mock.Mock
+}
+
+// DeleteExpiredIdempotencyKeys provides a mock function with given fields: ctx
+func (_m *IdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredIdempotencyKeys")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteIdempotencyKey provides a mock function with given fields: ctx, scope, key
+func (_m *IdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, scope string, key string) error {
+	ret := _m.Called(ctx, scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetIdempotencyRecord provides a mock function with given fields: ctx, scope, key, tx
+func (_m *IdempotencyRepository) GetIdempotencyRecord(ctx context.Context, scope string, key string, tx ...pgx.Tx) (*model.IdempotencyRecord, error) {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, scope, key)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdempotencyRecord")
+	}
+
+	var r0 *model.IdempotencyRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...pgx.Tx) (*model.IdempotencyRecord, error)); ok {
+		return rf(ctx, scope, key, tx...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ...pgx.Tx) *model.IdempotencyRecord); ok {
+		r0 = rf(ctx, scope, key, tx...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.IdempotencyRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ...pgx.Tx) error); ok {
+		r1 = rf(ctx, scope, key, tx...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReserveIdempotencyKey provides a mock function with given fields: ctx, scope, key, fingerprint, expiresAt
+func (_m *IdempotencyRepository) ReserveIdempotencyKey(ctx context.Context, scope string, key string, fingerprint string, expiresAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, scope, key, fingerprint, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveIdempotencyKey")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) (bool, error)); ok {
+		return rf(ctx, scope, key, fingerprint, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) bool); ok {
+		r0 = rf(ctx, scope, key, fingerprint, expiresAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, time.Time) error); ok {
+		r1 = rf(ctx, scope, key, fingerprint, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveIdempotencyRecord provides a mock function with given fields: ctx, rec, tx
+func (_m *IdempotencyRepository) SaveIdempotencyRecord(ctx context.Context, rec *model.IdempotencyRecord, tx ...pgx.Tx) error {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, rec)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveIdempotencyRecord")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.IdempotencyRecord, ...pgx.Tx) error); ok {
+		r0 = rf(ctx, rec, tx...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyRepository {
+	mock := &IdempotencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,125 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// JobRunRepository is an autogenerated mock type for the JobRunRepository type
+type JobRunRepository struct {
+	mock.Mock
+}
+
+// CreateJobRun provides a mock function with given fields: ctx, run
+func (_m *JobRunRepository) CreateJobRun(ctx context.Context, run *model.JobRun) error {
+	ret := _m.Called(ctx, run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJobRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.JobRun) error); ok {
+		r0 = rf(ctx, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteJobRunsBefore provides a mock function with given fields: ctx, job, before
+func (_m *JobRunRepository) DeleteJobRunsBefore(ctx context.Context, job string, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, job, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteJobRunsBefore")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int64, error)); ok {
+		return rf(ctx, job, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int64); ok {
+		r0 = rf(ctx, job, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, job, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishJobRun provides a mock function with given fields: ctx, run
+func (_m *JobRunRepository) FinishJobRun(ctx context.Context, run *model.JobRun) error {
+	ret := _m.Called(ctx, run)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishJobRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.JobRun) error); ok {
+		r0 = rf(ctx, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetJobRuns provides a mock function with given fields: ctx, job, limit
+func (_m *JobRunRepository) GetJobRuns(ctx context.Context, job string, limit int) ([]*model.JobRun, error) {
+	ret := _m.Called(ctx, job, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobRuns")
+	}
+
+	var r0 []*model.JobRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*model.JobRun, error)); ok {
+		return rf(ctx, job, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*model.JobRun); ok {
+		r0 = rf(ctx, job, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.JobRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, job, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewJobRunRepository creates a new instance of JobRunRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewJobRunRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *JobRunRepository {
+	mock := &JobRunRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
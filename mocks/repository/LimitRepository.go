@@ -0,0 +1,145 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	decimal "github.com/shopspring/decimal"
+	mock "github.com/stretchr/testify/mock"
+
+	model "transaction-processor/internal/model"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	time "time"
+)
+
+// LimitRepository is an autogenerated mock type for the LimitRepository type
+type LimitRepository struct {
+	mock.Mock
+}
+
+// AddNetLoss provides a mock function with given fields: ctx, userID, delta, at, tx
+func (_m *LimitRepository) AddNetLoss(ctx context.Context, userID int64, delta decimal.Decimal, at time.Time, tx pgx.Tx) error {
+	ret := _m.Called(ctx, userID, delta, at, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddNetLoss")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal, time.Time, pgx.Tx) error); ok {
+		r0 = rf(ctx, userID, delta, at, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLossCounters provides a mock function with given fields: ctx, userID, tx
+func (_m *LimitRepository) GetLossCounters(ctx context.Context, userID int64, tx ...pgx.Tx) ([]*model.LossCounter, error) {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLossCounters")
+	}
+
+	var r0 []*model.LossCounter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, ...pgx.Tx) ([]*model.LossCounter, error)); ok {
+		return rf(ctx, userID, tx...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, ...pgx.Tx) []*model.LossCounter); ok {
+		r0 = rf(ctx, userID, tx...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.LossCounter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, ...pgx.Tx) error); ok {
+		r1 = rf(ctx, userID, tx...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLossLimits provides a mock function with given fields: ctx, userID, tx
+func (_m *LimitRepository) GetLossLimits(ctx context.Context, userID int64, tx ...pgx.Tx) ([]*model.LossLimit, error) {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLossLimits")
+	}
+
+	var r0 []*model.LossLimit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, ...pgx.Tx) ([]*model.LossLimit, error)); ok {
+		return rf(ctx, userID, tx...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, ...pgx.Tx) []*model.LossLimit); ok {
+		r0 = rf(ctx, userID, tx...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.LossLimit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, ...pgx.Tx) error); ok {
+		r1 = rf(ctx, userID, tx...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertLossLimit provides a mock function with given fields: ctx, limit, tx
+func (_m *LimitRepository) UpsertLossLimit(ctx context.Context, limit *model.LossLimit, tx pgx.Tx) error {
+	ret := _m.Called(ctx, limit, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertLossLimit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.LossLimit, pgx.Tx) error); ok {
+		r0 = rf(ctx, limit, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLimitRepository creates a new instance of LimitRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLimitRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LimitRepository {
+	mock := &LimitRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,145 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	time "time"
+)
+
+// PartitionRepository is an autogenerated mock type for the PartitionRepository type
+type PartitionRepository struct {
+	mock.Mock
+}
+
+// ArchivePartition provides a mock function with given fields: ctx, name, archivePath, rowCount, tx
+func (_m *PartitionRepository) ArchivePartition(ctx context.Context, name string, archivePath string, rowCount int64, tx pgx.Tx) error {
+	ret := _m.Called(ctx, name, archivePath, rowCount, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchivePartition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, pgx.Tx) error); ok {
+		r0 = rf(ctx, name, archivePath, rowCount, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePartition provides a mock function with given fields: ctx, name, from, to
+func (_m *PartitionRepository) CreatePartition(ctx context.Context, name string, from time.Time, to time.Time) error {
+	ret := _m.Called(ctx, name, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePartition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r0 = rf(ctx, name, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportPartition provides a mock function with given fields: ctx, name, fn, tx
+func (_m *PartitionRepository) ExportPartition(ctx context.Context, name string, fn func(*model.Transaction) error, tx pgx.Tx) error {
+	ret := _m.Called(ctx, name, fn, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportPartition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, func(*model.Transaction) error, pgx.Tx) error); ok {
+		r0 = rf(ctx, name, fn, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPartitions provides a mock function with given fields: ctx
+func (_m *PartitionRepository) GetPartitions(ctx context.Context) ([]*model.TransactionPartition, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPartitions")
+	}
+
+	var r0 []*model.TransactionPartition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.TransactionPartition, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.TransactionPartition); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionPartition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasPendingReview provides a mock function with given fields: ctx, name, tx
+func (_m *PartitionRepository) HasPendingReview(ctx context.Context, name string, tx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, name, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasPendingReview")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, name, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, pgx.Tx) bool); ok {
+		r0 = rf(ctx, name, tx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, pgx.Tx) error); ok {
+		r1 = rf(ctx, name, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPartitionRepository creates a new instance of PartitionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPartitionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PartitionRepository {
+	mock := &PartitionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
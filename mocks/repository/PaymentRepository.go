@@ -0,0 +1,234 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// PaymentRepository is an autogenerated mock type for the PaymentRepository type
+type PaymentRepository struct {
+	mock.Mock
+}
+
+// GetPayment provides a mock function with given fields: ctx, id
+func (_m *PaymentRepository) GetPayment(ctx context.Context, id int64) (*model.Payment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayment")
+	}
+
+	var r0 *model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Payment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Payment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentByReferenceForUpdate provides a mock function with given fields: ctx, provider, reference, tx
+func (_m *PaymentRepository) GetPaymentByReferenceForUpdate(ctx context.Context, provider string, reference string, tx pgx.Tx) (*model.Payment, error) {
+	ret := _m.Called(ctx, provider, reference, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentByReferenceForUpdate")
+	}
+
+	var r0 *model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, pgx.Tx) (*model.Payment, error)); ok {
+		return rf(ctx, provider, reference, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, pgx.Tx) *model.Payment); ok {
+		r0 = rf(ctx, provider, reference, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, pgx.Tx) error); ok {
+		r1 = rf(ctx, provider, reference, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentForUpdate provides a mock function with given fields: ctx, id, tx
+func (_m *PaymentRepository) GetPaymentForUpdate(ctx context.Context, id int64, tx pgx.Tx) (*model.Payment, error) {
+	ret := _m.Called(ctx, id, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentForUpdate")
+	}
+
+	var r0 *model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, pgx.Tx) (*model.Payment, error)); ok {
+		return rf(ctx, id, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, pgx.Tx) *model.Payment); ok {
+		r0 = rf(ctx, id, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, pgx.Tx) error); ok {
+		r1 = rf(ctx, id, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayments provides a mock function with given fields: ctx, status, limit, offset
+func (_m *PaymentRepository) GetPayments(ctx context.Context, status model.PaymentStatus, limit int, offset int) ([]*model.Payment, error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayments")
+	}
+
+	var r0 []*model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.PaymentStatus, int, int) ([]*model.Payment, error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.PaymentStatus, int, int) []*model.Payment); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.PaymentStatus, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentsByUser provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *PaymentRepository) GetPaymentsByUser(ctx context.Context, userID int64, limit int, offset int) ([]*model.Payment, error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentsByUser")
+	}
+
+	var r0 []*model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*model.Payment, error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*model.Payment); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertPayment provides a mock function with given fields: ctx, payment, tx
+func (_m *PaymentRepository) InsertPayment(ctx context.Context, payment *model.Payment, tx ...pgx.Tx) error {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, payment)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertPayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Payment, ...pgx.Tx) error); ok {
+		r0 = rf(ctx, payment, tx...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePayment provides a mock function with given fields: ctx, payment, from, tx
+func (_m *PaymentRepository) UpdatePayment(ctx context.Context, payment *model.Payment, from model.PaymentStatus, tx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, payment, from, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePayment")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Payment, model.PaymentStatus, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, payment, from, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Payment, model.PaymentStatus, pgx.Tx) bool); ok {
+		r0 = rf(ctx, payment, from, tx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Payment, model.PaymentStatus, pgx.Tx) error); ok {
+		r1 = rf(ctx, payment, from, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPaymentRepository creates a new instance of PaymentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentRepository {
+	mock := &PaymentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
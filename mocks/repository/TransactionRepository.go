@@ -4,11 +4,15 @@ package mocks
 
 import (
 	context "context"
-	model "transaction-processor/internal/model"
 
+	decimal "github.com/shopspring/decimal"
 	mock "github.com/stretchr/testify/mock"
 
+	model "transaction-processor/internal/model"
+
 	pgx "github.com/jackc/pgx/v5"
+
+	time "time"
 )
 
 // TransactionRepository is an autogenerated mock type for the TransactionRepository type
@@ -16,9 +20,9 @@ type TransactionRepository struct {
 	mock.Mock
 }
 
-// CancelTransactionIfProcessed provides a mock function with given fields: ctx, id, tx
-func (_m *TransactionRepository) CancelTransactionIfProcessed(ctx context.Context, id int64, tx pgx.Tx) (bool, error) {
-	ret := _m.Called(ctx, id, tx)
+// CancelTransactionIfProcessed provides a mock function with given fields: ctx, id, reason, policy, shortfall, tx
+func (_m *TransactionRepository) CancelTransactionIfProcessed(ctx context.Context, id int64, reason string, policy model.ShortfallPolicy, shortfall *decimal.Decimal, tx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, id, reason, policy, shortfall, tx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CancelTransactionIfProcessed")
@@ -26,17 +30,75 @@ func (_m *TransactionRepository) CancelTransactionIfProcessed(ctx context.Contex
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, pgx.Tx) (bool, error)); ok {
-		return rf(ctx, id, tx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, model.ShortfallPolicy, *decimal.Decimal, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, id, reason, policy, shortfall, tx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64, pgx.Tx) bool); ok {
-		r0 = rf(ctx, id, tx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, model.ShortfallPolicy, *decimal.Decimal, pgx.Tx) bool); ok {
+		r0 = rf(ctx, id, reason, policy, shortfall, tx)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64, pgx.Tx) error); ok {
-		r1 = rf(ctx, id, tx)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, model.ShortfallPolicy, *decimal.Decimal, pgx.Tx) error); ok {
+		r1 = rf(ctx, id, reason, policy, shortfall, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteReview provides a mock function with given fields: ctx, id, status, reviewer, note, bonusAmount, tx
+func (_m *TransactionRepository) CompleteReview(ctx context.Context, id int64, status model.TransactionStatus, reviewer string, note string, bonusAmount decimal.Decimal, tx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, id, status, reviewer, note, bonusAmount, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteReview")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.TransactionStatus, string, string, decimal.Decimal, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, id, status, reviewer, note, bonusAmount, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.TransactionStatus, string, string, decimal.Decimal, pgx.Tx) bool); ok {
+		r0 = rf(ctx, id, status, reviewer, note, bonusAmount, tx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, model.TransactionStatus, string, string, decimal.Decimal, pgx.Tx) error); ok {
+		r1 = rf(ctx, id, status, reviewer, note, bonusAmount, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCancellationShortfalls provides a mock function with given fields: ctx, limit, offset
+func (_m *TransactionRepository) GetCancellationShortfalls(ctx context.Context, limit int, offset int) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCancellationShortfalls")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*model.Transaction, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*model.Transaction); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -74,6 +136,73 @@ func (_m *TransactionRepository) GetLatestOddProcessedTransactions(ctx context.C
 	return r0, r1
 }
 
+// GetPendingReviewTransactions provides a mock function with given fields: ctx, limit, offset
+func (_m *TransactionRepository) GetPendingReviewTransactions(ctx context.Context, limit int, offset int) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingReviewTransactions")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*model.Transaction, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*model.Transaction); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRecentTransactionsByUser provides a mock function with given fields: ctx, userID, since, tx
+func (_m *TransactionRepository) GetRecentTransactionsByUser(ctx context.Context, userID int64, since time.Time, tx ...pgx.Tx) ([]*model.Transaction, error) {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userID, since)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentTransactionsByUser")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, ...pgx.Tx) ([]*model.Transaction, error)); ok {
+		return rf(ctx, userID, since, tx...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, ...pgx.Tx) []*model.Transaction); ok {
+		r0 = rf(ctx, userID, since, tx...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, ...pgx.Tx) error); ok {
+		r1 = rf(ctx, userID, since, tx...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransaction provides a mock function with given fields: ctx, transactionID, tx
 func (_m *TransactionRepository) GetTransaction(ctx context.Context, transactionID string, tx ...pgx.Tx) (*model.Transaction, error) {
 	_va := make([]interface{}, len(tx))
@@ -111,6 +240,66 @@ func (_m *TransactionRepository) GetTransaction(ctx context.Context, transaction
 	return r0, r1
 }
 
+// GetTransactionEvents provides a mock function with given fields: ctx, transactionID
+func (_m *TransactionRepository) GetTransactionEvents(ctx context.Context, transactionID string) ([]*model.TransactionEvent, error) {
+	ret := _m.Called(ctx, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionEvents")
+	}
+
+	var r0 []*model.TransactionEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.TransactionEvent, error)); ok {
+		return rf(ctx, transactionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.TransactionEvent); ok {
+		r0 = rf(ctx, transactionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionForUpdate provides a mock function with given fields: ctx, transactionID, tx
+func (_m *TransactionRepository) GetTransactionForUpdate(ctx context.Context, transactionID string, tx pgx.Tx) (*model.Transaction, error) {
+	ret := _m.Called(ctx, transactionID, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionForUpdate")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, pgx.Tx) (*model.Transaction, error)); ok {
+		return rf(ctx, transactionID, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, pgx.Tx) *model.Transaction); ok {
+		r0 = rf(ctx, transactionID, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, pgx.Tx) error); ok {
+		r1 = rf(ctx, transactionID, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionsByUser provides a mock function with given fields: ctx, userID, limit, offset
 func (_m *TransactionRepository) GetTransactionsByUser(ctx context.Context, userID int64, limit int, offset int) ([]*model.Transaction, error) {
 	ret := _m.Called(ctx, userID, limit, offset)
@@ -141,6 +330,48 @@ func (_m *TransactionRepository) GetTransactionsByUser(ctx context.Context, user
 	return r0, r1
 }
 
+// GetWinStats provides a mock function with given fields: ctx, userID, limit, tx
+func (_m *TransactionRepository) GetWinStats(ctx context.Context, userID int64, limit int, tx ...pgx.Tx) (decimal.Decimal, int, error) {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userID, limit)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWinStats")
+	}
+
+	var r0 decimal.Decimal
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, ...pgx.Tx) (decimal.Decimal, int, error)); ok {
+		return rf(ctx, userID, limit, tx...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, ...pgx.Tx) decimal.Decimal); ok {
+		r0 = rf(ctx, userID, limit, tx...)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, ...pgx.Tx) int); ok {
+		r1 = rf(ctx, userID, limit, tx...)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, int, ...pgx.Tx) error); ok {
+		r2 = rf(ctx, userID, limit, tx...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // InsertTransaction provides a mock function with given fields: ctx, trans, tx
 func (_m *TransactionRepository) InsertTransaction(ctx context.Context, trans *model.Transaction, tx pgx.Tx) error {
 	ret := _m.Called(ctx, trans, tx)
@@ -159,6 +390,24 @@ func (_m *TransactionRepository) InsertTransaction(ctx context.Context, trans *m
 	return r0
 }
 
+// InsertTransactionEvent provides a mock function with given fields: ctx, event, tx
+func (_m *TransactionRepository) InsertTransactionEvent(ctx context.Context, event *model.TransactionEvent, tx pgx.Tx) error {
+	ret := _m.Called(ctx, event, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertTransactionEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TransactionEvent, pgx.Tx) error); ok {
+		r0 = rf(ctx, event, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LockTransactionForCancellation provides a mock function with given fields: ctx, id, tx
 func (_m *TransactionRepository) LockTransactionForCancellation(ctx context.Context, id int64, tx pgx.Tx) (bool, error) {
 	ret := _m.Called(ctx, id, tx)
@@ -187,6 +436,34 @@ func (_m *TransactionRepository) LockTransactionForCancellation(ctx context.Cont
 	return r0, r1
 }
 
+// MarkCancellationFailed provides a mock function with given fields: ctx, id, reason, shortfall, tx
+func (_m *TransactionRepository) MarkCancellationFailed(ctx context.Context, id int64, reason string, shortfall decimal.Decimal, tx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, id, reason, shortfall, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkCancellationFailed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, decimal.Decimal, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, id, reason, shortfall, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, decimal.Decimal, pgx.Tx) bool); ok {
+		r0 = rf(ctx, id, reason, shortfall, tx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, decimal.Decimal, pgx.Tx) error); ok {
+		r1 = rf(ctx, id, reason, shortfall, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTransactionRepository creates a new instance of TransactionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTransactionRepository(t interface {
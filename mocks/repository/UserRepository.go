@@ -11,6 +11,8 @@ import (
 	model "transaction-processor/internal/model"
 
 	pgx "github.com/jackc/pgx/v5"
+
+	time "time"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -53,6 +55,73 @@ func (_m *UserRepository) GetBalance(ctx context.Context, userID int64, tx ...pg
 	return r0, r1
 }
 
+// GetBalanceDrifts provides a mock function with given fields: ctx
+func (_m *UserRepository) GetBalanceDrifts(ctx context.Context) ([]*model.BalanceDrift, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceDrifts")
+	}
+
+	var r0 []*model.BalanceDrift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.BalanceDrift, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.BalanceDrift); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BalanceDrift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: ctx, userID, tx
+func (_m *UserRepository) GetUser(ctx context.Context, userID int64, tx ...pgx.Tx) (*model.User, error) {
+	_va := make([]interface{}, len(tx))
+	for _i := range tx {
+		_va[_i] = tx[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, ...pgx.Tx) (*model.User, error)); ok {
+		return rf(ctx, userID, tx...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, ...pgx.Tx) *model.User); ok {
+		r0 = rf(ctx, userID, tx...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, ...pgx.Tx) error); ok {
+		r1 = rf(ctx, userID, tx...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserForUpdate provides a mock function with given fields: ctx, userID, tx
 func (_m *UserRepository) GetUserForUpdate(ctx context.Context, userID int64, tx pgx.Tx) (*model.User, error) {
 	ret := _m.Called(ctx, userID, tx)
@@ -83,6 +152,54 @@ func (_m *UserRepository) GetUserForUpdate(ctx context.Context, userID int64, tx
 	return r0, r1
 }
 
+// SetMutationsPaused provides a mock function with given fields: ctx, userIDs, paused
+func (_m *UserRepository) SetMutationsPaused(ctx context.Context, userIDs []int64, paused bool) ([]int64, error) {
+	ret := _m.Called(ctx, userIDs, paused)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMutationsPaused")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, bool) ([]int64, error)); ok {
+		return rf(ctx, userIDs, paused)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, bool) []int64); ok {
+		r0 = rf(ctx, userIDs, paused)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64, bool) error); ok {
+		r1 = rf(ctx, userIDs, paused)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetProvider provides a mock function with given fields: ctx, userID, providerID
+func (_m *UserRepository) SetProvider(ctx context.Context, userID int64, providerID string) error {
+	ret := _m.Called(ctx, userID, providerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetProvider")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, providerID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateBalance provides a mock function with given fields: ctx, userID, balance, tx
 func (_m *UserRepository) UpdateBalance(ctx context.Context, userID int64, balance decimal.Decimal, tx pgx.Tx) error {
 	ret := _m.Called(ctx, userID, balance, tx)
@@ -101,6 +218,60 @@ func (_m *UserRepository) UpdateBalance(ctx context.Context, userID int64, balan
 	return r0
 }
 
+// UpdateBonus provides a mock function with given fields: ctx, userID, bonus, wagering, expiresAt, tx
+func (_m *UserRepository) UpdateBonus(ctx context.Context, userID int64, bonus decimal.Decimal, wagering decimal.Decimal, expiresAt *time.Time, tx pgx.Tx) error {
+	ret := _m.Called(ctx, userID, bonus, wagering, expiresAt, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBonus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal, decimal.Decimal, *time.Time, pgx.Tx) error); ok {
+		r0 = rf(ctx, userID, bonus, wagering, expiresAt, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDebt provides a mock function with given fields: ctx, userID, debt, tx
+func (_m *UserRepository) UpdateDebt(ctx context.Context, userID int64, debt decimal.Decimal, tx pgx.Tx) error {
+	ret := _m.Called(ctx, userID, debt, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDebt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal, pgx.Tx) error); ok {
+		r0 = rf(ctx, userID, debt, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateReserved provides a mock function with given fields: ctx, userID, reserved, tx
+func (_m *UserRepository) UpdateReserved(ctx context.Context, userID int64, reserved decimal.Decimal, tx pgx.Tx) error {
+	ret := _m.Called(ctx, userID, reserved, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateReserved")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal, pgx.Tx) error); ok {
+		r0 = rf(ctx, userID, reserved, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WorkerStateRepository is an autogenerated mock type for the WorkerStateRepository type
+type WorkerStateRepository struct {
+	mock.Mock
+}
+
+// GetWorkerState provides a mock function with given fields: ctx, name
+func (_m *WorkerStateRepository) GetWorkerState(ctx context.Context, name string) (*model.WorkerState, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkerState")
+	}
+
+	var r0 *model.WorkerState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.WorkerState, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.WorkerState); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WorkerState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetWorkerInterval provides a mock function with given fields: ctx, name, interval
+func (_m *WorkerStateRepository) SetWorkerInterval(ctx context.Context, name string, interval time.Duration) error {
+	ret := _m.Called(ctx, name, interval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWorkerInterval")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, name, interval)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetWorkerPaused provides a mock function with given fields: ctx, name, paused, operator
+func (_m *WorkerStateRepository) SetWorkerPaused(ctx context.Context, name string, paused bool, operator string) error {
+	ret := _m.Called(ctx, name, paused, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWorkerPaused")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, string) error); ok {
+		r0 = rf(ctx, name, paused, operator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWorkerStateRepository creates a new instance of WorkerStateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWorkerStateRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WorkerStateRepository {
+	mock := &WorkerStateRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
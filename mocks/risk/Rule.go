@@ -0,0 +1,102 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	risk "transaction-processor/internal/risk"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Rule is an autogenerated mock type for the Rule type
+type Rule struct {
+	mock.Mock
+}
+
+// Action provides a mock function with no fields
+func (_m *Rule) Action() risk.Action {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Action")
+	}
+
+	var r0 risk.Action
+	if rf, ok := ret.Get(0).(func() risk.Action); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(risk.Action)
+	}
+
+	return r0
+}
+
+// Lookback provides a mock function with no fields
+func (_m *Rule) Lookback() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lookback")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// Matches provides a mock function with given fields: in
+func (_m *Rule) Matches(in *risk.Input) bool {
+	ret := _m.Called(in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Matches")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*risk.Input) bool); ok {
+		r0 = rf(in)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Name provides a mock function with no fields
+func (_m *Rule) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// NewRule creates a new instance of Rule. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRule(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Rule {
+	mock := &Rule{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,64 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Schedule is an autogenerated mock type for the Schedule type
+type Schedule struct {
+	mock.Mock
+}
+
+// Next provides a mock function with given fields: from
+func (_m *Schedule) Next(from time.Time) time.Time {
+	ret := _m.Called(from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Next")
+	}
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(time.Time) time.Time); ok {
+		r0 = rf(from)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// String provides a mock function with no fields
+func (_m *Schedule) String() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for String")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// NewSchedule creates a new instance of Schedule. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSchedule(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Schedule {
+	mock := &Schedule{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
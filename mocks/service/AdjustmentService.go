@@ -0,0 +1,151 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	decimal "github.com/shopspring/decimal"
+	mock "github.com/stretchr/testify/mock"
+
+	model "transaction-processor/internal/model"
+)
+
+// AdjustmentService is an autogenerated mock type for the AdjustmentService type
+type AdjustmentService struct {
+	mock.Mock
+}
+
+// ApproveAdjustment provides a mock function with given fields: ctx, id, operator, note
+func (_m *AdjustmentService) ApproveAdjustment(ctx context.Context, id int64, operator string, note string) (*model.BalanceAdjustment, error) {
+	ret := _m.Called(ctx, id, operator, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveAdjustment")
+	}
+
+	var r0 *model.BalanceAdjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*model.BalanceAdjustment, error)); ok {
+		return rf(ctx, id, operator, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *model.BalanceAdjustment); ok {
+		r0 = rf(ctx, id, operator, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BalanceAdjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, operator, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAdjustment provides a mock function with given fields: ctx, userID, amount, reason, operator
+func (_m *AdjustmentService) CreateAdjustment(ctx context.Context, userID int64, amount decimal.Decimal, reason string, operator string) (*model.BalanceAdjustment, error) {
+	ret := _m.Called(ctx, userID, amount, reason, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAdjustment")
+	}
+
+	var r0 *model.BalanceAdjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal, string, string) (*model.BalanceAdjustment, error)); ok {
+		return rf(ctx, userID, amount, reason, operator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal, string, string) *model.BalanceAdjustment); ok {
+		r0 = rf(ctx, userID, amount, reason, operator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BalanceAdjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, decimal.Decimal, string, string) error); ok {
+		r1 = rf(ctx, userID, amount, reason, operator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAdjustments provides a mock function with given fields: ctx, status, limit, offset
+func (_m *AdjustmentService) ListAdjustments(ctx context.Context, status model.AdjustmentStatus, limit int, offset int) ([]*model.BalanceAdjustment, error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAdjustments")
+	}
+
+	var r0 []*model.BalanceAdjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.AdjustmentStatus, int, int) ([]*model.BalanceAdjustment, error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.AdjustmentStatus, int, int) []*model.BalanceAdjustment); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BalanceAdjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.AdjustmentStatus, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectAdjustment provides a mock function with given fields: ctx, id, operator, note
+func (_m *AdjustmentService) RejectAdjustment(ctx context.Context, id int64, operator string, note string) (*model.BalanceAdjustment, error) {
+	ret := _m.Called(ctx, id, operator, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectAdjustment")
+	}
+
+	var r0 *model.BalanceAdjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*model.BalanceAdjustment, error)); ok {
+		return rf(ctx, id, operator, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *model.BalanceAdjustment); ok {
+		r0 = rf(ctx, id, operator, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BalanceAdjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, operator, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAdjustmentService creates a new instance of AdjustmentService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdjustmentService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdjustmentService {
+	mock := &AdjustmentService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BalanceStreamService is an autogenerated mock type for the BalanceStreamService type
+type BalanceStreamService struct {
+	mock.Mock
+}
+
+// Current provides a mock function with given fields: ctx, userID
+func (_m *BalanceStreamService) Current(ctx context.Context, userID int64) (*model.BalanceEvent, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Current")
+	}
+
+	var r0 *model.BalanceEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.BalanceEvent, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.BalanceEvent); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BalanceEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Run provides a mock function with given fields: ctx
+func (_m *BalanceStreamService) Run(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// Subscribe provides a mock function with given fields: userID
+func (_m *BalanceStreamService) Subscribe(userID int64) (<-chan *model.BalanceEvent, func()) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan *model.BalanceEvent
+	var r1 func()
+	if rf, ok := ret.Get(0).(func(int64) (<-chan *model.BalanceEvent, func())); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) <-chan *model.BalanceEvent); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *model.BalanceEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) func()); ok {
+		r1 = rf(userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
+// NewBalanceStreamService creates a new instance of BalanceStreamService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBalanceStreamService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BalanceStreamService {
+	mock := &BalanceStreamService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
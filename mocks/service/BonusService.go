@@ -0,0 +1,151 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	decimal "github.com/shopspring/decimal"
+	mock "github.com/stretchr/testify/mock"
+
+	model "transaction-processor/internal/model"
+)
+
+// BonusService is an autogenerated mock type for the BonusService type
+type BonusService struct {
+	mock.Mock
+}
+
+// ExpireBonuses provides a mock function with given fields: ctx
+func (_m *BonusService) ExpireBonuses(ctx context.Context) (*model.BonusExpiryResult, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireBonuses")
+	}
+
+	var r0 *model.BonusExpiryResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.BonusExpiryResult, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.BonusExpiryResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BonusExpiryResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBonusWallet provides a mock function with given fields: ctx, userID
+func (_m *BonusService) GetBonusWallet(ctx context.Context, userID int64) (*model.BonusWallet, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBonusWallet")
+	}
+
+	var r0 *model.BonusWallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.BonusWallet, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.BonusWallet); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BonusWallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantBonus provides a mock function with given fields: ctx, userID, amount, wagering, note, operator
+func (_m *BonusService) GrantBonus(ctx context.Context, userID int64, amount decimal.Decimal, wagering *decimal.Decimal, note string, operator string) (*model.BonusWallet, error) {
+	ret := _m.Called(ctx, userID, amount, wagering, note, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantBonus")
+	}
+
+	var r0 *model.BonusWallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal, *decimal.Decimal, string, string) (*model.BonusWallet, error)); ok {
+		return rf(ctx, userID, amount, wagering, note, operator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal, *decimal.Decimal, string, string) *model.BonusWallet); ok {
+		r0 = rf(ctx, userID, amount, wagering, note, operator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BonusWallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, decimal.Decimal, *decimal.Decimal, string, string) error); ok {
+		r1 = rf(ctx, userID, amount, wagering, note, operator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBonusEntries provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *BonusService) ListBonusEntries(ctx context.Context, userID int64, limit int, offset int) ([]*model.BonusEntry, error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBonusEntries")
+	}
+
+	var r0 []*model.BonusEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*model.BonusEntry, error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*model.BonusEntry); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BonusEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBonusService creates a new instance of BonusService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBonusService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BonusService {
+	mock := &BonusService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,8 +4,11 @@ package mocks
 
 import (
 	context "context"
+	model "transaction-processor/internal/model"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // CancellationService is an autogenerated mock type for the CancellationService type
@@ -13,17 +16,155 @@ type CancellationService struct {
 	mock.Mock
 }
 
+// CancelTransaction provides a mock function with given fields: ctx, transactionID, operator, reason, note
+func (_m *CancellationService) CancelTransaction(ctx context.Context, transactionID string, operator string, reason model.CancelReason, note string) (*model.Transaction, error) {
+	ret := _m.Called(ctx, transactionID, operator, reason, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelTransaction")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.CancelReason, string) (*model.Transaction, error)); ok {
+		return rf(ctx, transactionID, operator, reason, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.CancelReason, string) *model.Transaction); ok {
+		r0 = rf(ctx, transactionID, operator, reason, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, model.CancelReason, string) error); ok {
+		r1 = rf(ctx, transactionID, operator, reason, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkerState provides a mock function with given fields: ctx
+func (_m *CancellationService) GetWorkerState(ctx context.Context) (*model.WorkerState, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkerState")
+	}
+
+	var r0 *model.WorkerState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.WorkerState, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.WorkerState); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WorkerState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCancellationShortfalls provides a mock function with given fields: ctx, limit, offset
+func (_m *CancellationService) ListCancellationShortfalls(ctx context.Context, limit int, offset int) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCancellationShortfalls")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*model.Transaction, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*model.Transaction); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProcessOddRecordCancellation provides a mock function with given fields: ctx
-func (_m *CancellationService) ProcessOddRecordCancellation(ctx context.Context) error {
+func (_m *CancellationService) ProcessOddRecordCancellation(ctx context.Context) (*model.CancellationResult, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ProcessOddRecordCancellation")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+	var r0 *model.CancellationResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.CancellationResult, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.CancellationResult); ok {
 		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CancellationResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetWorkerInterval provides a mock function with given fields: ctx, interval, operator
+func (_m *CancellationService) SetWorkerInterval(ctx context.Context, interval time.Duration, operator string) error {
+	ret := _m.Called(ctx, interval, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWorkerInterval")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, string) error); ok {
+		r0 = rf(ctx, interval, operator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetWorkerPaused provides a mock function with given fields: ctx, paused, operator
+func (_m *CancellationService) SetWorkerPaused(ctx context.Context, paused bool, operator string) error {
+	ret := _m.Called(ctx, paused, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWorkerPaused")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool, string) error); ok {
+		r0 = rf(ctx, paused, operator)
 	} else {
 		r0 = ret.Error(0)
 	}
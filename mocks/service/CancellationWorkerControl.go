@@ -0,0 +1,145 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CancellationWorkerControl is an autogenerated mock type for the CancellationWorkerControl type
+type CancellationWorkerControl struct {
+	mock.Mock
+}
+
+// Pause provides a mock function with given fields: ctx, operator
+func (_m *CancellationWorkerControl) Pause(ctx context.Context, operator string) error {
+	ret := _m.Called(ctx, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pause")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, operator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resume provides a mock function with given fields: ctx, operator
+func (_m *CancellationWorkerControl) Resume(ctx context.Context, operator string) error {
+	ret := _m.Called(ctx, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, operator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetInterval provides a mock function with given fields: ctx, interval, operator
+func (_m *CancellationWorkerControl) SetInterval(ctx context.Context, interval time.Duration, operator string) error {
+	ret := _m.Called(ctx, interval, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetInterval")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, string) error); ok {
+		r0 = rf(ctx, interval, operator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Status provides a mock function with given fields: ctx
+func (_m *CancellationWorkerControl) Status(ctx context.Context) (*model.WorkerStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Status")
+	}
+
+	var r0 *model.WorkerStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.WorkerStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.WorkerStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WorkerStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Trigger provides a mock function with given fields: ctx
+func (_m *CancellationWorkerControl) Trigger(ctx context.Context) (*model.WorkerStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Trigger")
+	}
+
+	var r0 *model.WorkerStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.WorkerStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.WorkerStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WorkerStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCancellationWorkerControl creates a new instance of CancellationWorkerControl. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCancellationWorkerControl(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CancellationWorkerControl {
+	mock := &CancellationWorkerControl{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,54 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthService is an autogenerated mock type for the HealthService type
+type HealthService struct {
+	mock.Mock
+}
+
+// Drain provides a mock function with no fields
+func (_m *HealthService) Drain() {
+	_m.Called()
+}
+
+// Readiness provides a mock function with given fields: ctx
+func (_m *HealthService) Readiness(ctx context.Context) *model.ReadinessResponse {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Readiness")
+	}
+
+	var r0 *model.ReadinessResponse
+	if rf, ok := ret.Get(0).(func(context.Context) *model.ReadinessResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ReadinessResponse)
+		}
+	}
+
+	return r0
+}
+
+// NewHealthService creates a new instance of HealthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthService {
+	mock := &HealthService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
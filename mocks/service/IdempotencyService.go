@@ -0,0 +1,113 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdempotencyService is an autogenerated mock type for the IdempotencyService type
+type IdempotencyService struct {
+	mock.Mock
+}
+
+// Complete provides a mock function with given fields: ctx, scope, key, fingerprint, statusCode, response
+func (_m *IdempotencyService) Complete(ctx context.Context, scope string, key string, fingerprint string, statusCode int, response []byte) error {
+	ret := _m.Called(ctx, scope, key, fingerprint, statusCode, response)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int, []byte) error); ok {
+		r0 = rf(ctx, scope, key, fingerprint, statusCode, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: ctx
+func (_m *IdempotencyService) DeleteExpired(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Release provides a mock function with given fields: ctx, scope, key
+func (_m *IdempotencyService) Release(ctx context.Context, scope string, key string) error {
+	ret := _m.Called(ctx, scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reserve provides a mock function with given fields: ctx, scope, key, fingerprint
+func (_m *IdempotencyService) Reserve(ctx context.Context, scope string, key string, fingerprint string) (*model.IdempotencyRecord, error) {
+	ret := _m.Called(ctx, scope, key, fingerprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 *model.IdempotencyRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.IdempotencyRecord, error)); ok {
+		return rf(ctx, scope, key, fingerprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.IdempotencyRecord); ok {
+		r0 = rf(ctx, scope, key, fingerprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.IdempotencyRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, scope, key, fingerprint)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIdempotencyService creates a new instance of IdempotencyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyService {
+	mock := &IdempotencyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IntegrityService is an autogenerated mock type for the IntegrityService type
+type IntegrityService struct {
+	mock.Mock
+}
+
+// ResumeUser provides a mock function with given fields: ctx, userID, operator
+func (_m *IntegrityService) ResumeUser(ctx context.Context, userID int64, operator string) error {
+	ret := _m.Called(ctx, userID, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResumeUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, operator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RunCheck provides a mock function with given fields: ctx
+func (_m *IntegrityService) RunCheck(ctx context.Context) (*model.IntegrityReport, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunCheck")
+	}
+
+	var r0 *model.IntegrityReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.IntegrityReport, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.IntegrityReport); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.IntegrityReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyBalances provides a mock function with given fields: ctx
+func (_m *IntegrityService) VerifyBalances(ctx context.Context) ([]*model.BalanceDrift, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyBalances")
+	}
+
+	var r0 []*model.BalanceDrift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.BalanceDrift, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.BalanceDrift); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BalanceDrift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIntegrityService creates a new instance of IntegrityService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIntegrityService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IntegrityService {
+	mock := &IntegrityService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LimitService is an autogenerated mock type for the LimitService type
+type LimitService struct {
+	mock.Mock
+}
+
+// GetLossLimits provides a mock function with given fields: ctx, userID
+func (_m *LimitService) GetLossLimits(ctx context.Context, userID int64) (*model.LossLimitsResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLossLimits")
+	}
+
+	var r0 *model.LossLimitsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.LossLimitsResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.LossLimitsResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LossLimitsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLossLimit provides a mock function with given fields: ctx, userID, req
+func (_m *LimitService) SetLossLimit(ctx context.Context, userID int64, req *model.LossLimitRequest) (*model.LossLimitResponse, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLossLimit")
+	}
+
+	var r0 *model.LossLimitResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.LossLimitRequest) (*model.LossLimitResponse, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *model.LossLimitRequest) *model.LossLimitResponse); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LossLimitResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *model.LossLimitRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLimitService creates a new instance of LimitService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLimitService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LimitService {
+	mock := &LimitService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PartitionService is an autogenerated mock type for the PartitionService type
+type PartitionService struct {
+	mock.Mock
+}
+
+// ArchivePartitions provides a mock function with given fields: ctx
+func (_m *PartitionService) ArchivePartitions(ctx context.Context) ([]*model.TransactionPartition, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchivePartitions")
+	}
+
+	var r0 []*model.TransactionPartition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.TransactionPartition, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.TransactionPartition); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionPartition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnsurePartitions provides a mock function with given fields: ctx
+func (_m *PartitionService) EnsurePartitions(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnsurePartitions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListPartitions provides a mock function with given fields: ctx
+func (_m *PartitionService) ListPartitions(ctx context.Context) ([]*model.TransactionPartition, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPartitions")
+	}
+
+	var r0 []*model.TransactionPartition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.TransactionPartition, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.TransactionPartition); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionPartition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPartitionService creates a new instance of PartitionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPartitionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PartitionService {
+	mock := &PartitionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PaymentProvider is an autogenerated mock type for the PaymentProvider type
+type PaymentProvider struct {
+	mock.Mock
+}
+
+// Name provides a mock function with no fields
+func (_m *PaymentProvider) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Submit provides a mock function with given fields: ctx, payment
+func (_m *PaymentProvider) Submit(ctx context.Context, payment *model.Payment) (string, error) {
+	ret := _m.Called(ctx, payment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Submit")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Payment) (string, error)); ok {
+		return rf(ctx, payment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Payment) string); ok {
+		r0 = rf(ctx, payment)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Payment) error); ok {
+		r1 = rf(ctx, payment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyCallback provides a mock function with given fields: body, signature
+func (_m *PaymentProvider) VerifyCallback(body []byte, signature string) error {
+	ret := _m.Called(body, signature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyCallback")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]byte, string) error); ok {
+		r0 = rf(body, signature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPaymentProvider creates a new instance of PaymentProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentProvider {
+	mock := &PaymentProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
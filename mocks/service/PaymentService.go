@@ -0,0 +1,259 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	decimal "github.com/shopspring/decimal"
+	mock "github.com/stretchr/testify/mock"
+
+	model "transaction-processor/internal/model"
+)
+
+// PaymentService is an autogenerated mock type for the PaymentService type
+type PaymentService struct {
+	mock.Mock
+}
+
+// ApprovePayment provides a mock function with given fields: ctx, id, operator
+func (_m *PaymentService) ApprovePayment(ctx context.Context, id int64, operator string) (*model.Payment, error) {
+	ret := _m.Called(ctx, id, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApprovePayment")
+	}
+
+	var r0 *model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*model.Payment, error)); ok {
+		return rf(ctx, id, operator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *model.Payment); ok {
+		r0 = rf(ctx, id, operator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, id, operator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HandleCallback provides a mock function with given fields: ctx, reference, status, reason
+func (_m *PaymentService) HandleCallback(ctx context.Context, reference string, status model.PaymentStatus, reason string) (*model.Payment, error) {
+	ret := _m.Called(ctx, reference, status, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandleCallback")
+	}
+
+	var r0 *model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.PaymentStatus, string) (*model.Payment, error)); ok {
+		return rf(ctx, reference, status, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.PaymentStatus, string) *model.Payment); ok {
+		r0 = rf(ctx, reference, status, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.PaymentStatus, string) error); ok {
+		r1 = rf(ctx, reference, status, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPayments provides a mock function with given fields: ctx, status, limit, offset
+func (_m *PaymentService) ListPayments(ctx context.Context, status model.PaymentStatus, limit int, offset int) ([]*model.Payment, error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayments")
+	}
+
+	var r0 []*model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.PaymentStatus, int, int) ([]*model.Payment, error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.PaymentStatus, int, int) []*model.Payment); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.PaymentStatus, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUserPayments provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *PaymentService) ListUserPayments(ctx context.Context, userID int64, limit int, offset int) ([]*model.Payment, error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserPayments")
+	}
+
+	var r0 []*model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*model.Payment, error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*model.Payment); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectPayment provides a mock function with given fields: ctx, id, operator, reason
+func (_m *PaymentService) RejectPayment(ctx context.Context, id int64, operator string, reason string) (*model.Payment, error) {
+	ret := _m.Called(ctx, id, operator, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectPayment")
+	}
+
+	var r0 *model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*model.Payment, error)); ok {
+		return rf(ctx, id, operator, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *model.Payment); ok {
+		r0 = rf(ctx, id, operator, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, operator, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestDeposit provides a mock function with given fields: ctx, userID, amount
+func (_m *PaymentService) RequestDeposit(ctx context.Context, userID int64, amount decimal.Decimal) (*model.Payment, error) {
+	ret := _m.Called(ctx, userID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestDeposit")
+	}
+
+	var r0 *model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal) (*model.Payment, error)); ok {
+		return rf(ctx, userID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal) *model.Payment); ok {
+		r0 = rf(ctx, userID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, decimal.Decimal) error); ok {
+		r1 = rf(ctx, userID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestWithdrawal provides a mock function with given fields: ctx, userID, amount
+func (_m *PaymentService) RequestWithdrawal(ctx context.Context, userID int64, amount decimal.Decimal) (*model.Payment, error) {
+	ret := _m.Called(ctx, userID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestWithdrawal")
+	}
+
+	var r0 *model.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal) (*model.Payment, error)); ok {
+		return rf(ctx, userID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, decimal.Decimal) *model.Payment); ok {
+		r0 = rf(ctx, userID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, decimal.Decimal) error); ok {
+		r1 = rf(ctx, userID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyCallback provides a mock function with given fields: body, signature
+func (_m *PaymentService) VerifyCallback(body []byte, signature string) error {
+	ret := _m.Called(body, signature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyCallback")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]byte, string) error); ok {
+		r0 = rf(body, signature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPaymentService creates a new instance of PaymentService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentService {
+	mock := &PaymentService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
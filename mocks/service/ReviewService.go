@@ -0,0 +1,119 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "transaction-processor/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReviewService is an autogenerated mock type for the ReviewService type
+type ReviewService struct {
+	mock.Mock
+}
+
+// ApproveTransaction provides a mock function with given fields: ctx, transactionID, reviewer, note
+func (_m *ReviewService) ApproveTransaction(ctx context.Context, transactionID string, reviewer string, note string) (*model.Transaction, error) {
+	ret := _m.Called(ctx, transactionID, reviewer, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveTransaction")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.Transaction, error)); ok {
+		return rf(ctx, transactionID, reviewer, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.Transaction); ok {
+		r0 = rf(ctx, transactionID, reviewer, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, transactionID, reviewer, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPendingReviews provides a mock function with given fields: ctx, limit, offset
+func (_m *ReviewService) ListPendingReviews(ctx context.Context, limit int, offset int) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPendingReviews")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*model.Transaction, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*model.Transaction); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectTransaction provides a mock function with given fields: ctx, transactionID, reviewer, note
+func (_m *ReviewService) RejectTransaction(ctx context.Context, transactionID string, reviewer string, note string) (*model.Transaction, error) {
+	ret := _m.Called(ctx, transactionID, reviewer, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectTransaction")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*model.Transaction, error)); ok {
+		return rf(ctx, transactionID, reviewer, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *model.Transaction); ok {
+		r0 = rf(ctx, transactionID, reviewer, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, transactionID, reviewer, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReviewService creates a new instance of ReviewService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReviewService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReviewService {
+	mock := &ReviewService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -44,6 +44,66 @@ func (_m *TransactionService) GetBalance(ctx context.Context, userID int64) (*mo
 	return r0, r1
 }
 
+// GetTransaction provides a mock function with given fields: ctx, transactionID
+func (_m *TransactionService) GetTransaction(ctx context.Context, transactionID string) (*model.Transaction, error) {
+	ret := _m.Called(ctx, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransaction")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Transaction, error)); ok {
+		return rf(ctx, transactionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Transaction); ok {
+		r0 = rf(ctx, transactionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionEvents provides a mock function with given fields: ctx, transactionID
+func (_m *TransactionService) GetTransactionEvents(ctx context.Context, transactionID string) ([]*model.TransactionEvent, error) {
+	ret := _m.Called(ctx, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionEvents")
+	}
+
+	var r0 []*model.TransactionEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.TransactionEvent, error)); ok {
+		return rf(ctx, transactionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.TransactionEvent); ok {
+		r0 = rf(ctx, transactionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionsByUser provides a mock function with given fields: ctx, userID, limit, offset
 func (_m *TransactionService) GetTransactionsByUser(ctx context.Context, userID int64, limit int, offset int) ([]*model.Transaction, error) {
 	ret := _m.Called(ctx, userID, limit, offset)
@@ -104,6 +164,24 @@ func (_m *TransactionService) ProcessTransaction(ctx context.Context, req *model
 	return r0, r1
 }
 
+// SetUserProvider provides a mock function with given fields: ctx, userID, providerID, operator
+func (_m *TransactionService) SetUserProvider(ctx context.Context, userID int64, providerID string, operator string) error {
+	ret := _m.Called(ctx, userID, providerID, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserProvider")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, userID, providerID, operator)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewTransactionService creates a new instance of TransactionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTransactionService(t interface {
@@ -0,0 +1,42 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// WorkerHealth is an autogenerated mock type for the WorkerHealth type
+type WorkerHealth struct {
+	mock.Mock
+}
+
+// Healthy provides a mock function with no fields
+func (_m *WorkerHealth) Healthy() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Healthy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWorkerHealth creates a new instance of WorkerHealth. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWorkerHealth(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WorkerHealth {
+	mock := &WorkerHealth{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}